@@ -0,0 +1,27 @@
+// Package api holds the contracts of the application: the OpenAPI document of the HTTP
+// API and the protobuf definitions of the gRPC one.
+package api
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+//go:embed openapi.yaml
+var spec []byte
+
+// OpenAPI loads and validates the OpenAPI document of the HTTP API.
+func OpenAPI() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
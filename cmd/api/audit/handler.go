@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"errors"
+	"net/http"
+
+	authHandler "github.com/johan-ag/testing/cmd/api/auth"
+	"github.com/johan-ag/testing/internal/audit"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+type handler struct {
+	service audit.Service
+}
+
+func NewHandler(service audit.Service) *handler {
+	return &handler{
+		service,
+	}
+}
+
+// List returns the audit trail of the entity given by the entity and id query params.
+func (h *handler) List(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+
+	events, err := h.service.List(r.Context(), query.Get("entity"), query.Get("id"))
+	var denied *authz.DeniedError
+	if errors.As(err, &denied) {
+		return authHandler.EncodeDenied(w, denied)
+	}
+	if errors.Is(err, audit.ErrorMissingEntity) {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return web.NewError(http.StatusInternalServerError, err.Error())
+	}
+
+	return web.EncodeJSON(w, events, http.StatusOK)
+}
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+// deniedResponse is the body of the forbidden responses, Reason is the code of the rule
+// that denied the action.
+type deniedResponse struct {
+	Message string `json:"message"`
+	Action  string `json:"action"`
+	Reason  string `json:"reason"`
+}
+
+// EncodeDenied writes a 403 response telling why the action was denied.
+func EncodeDenied(w http.ResponseWriter, denied *authz.DeniedError) error {
+	return web.EncodeJSON(w, deniedResponse{
+		Message: authz.ErrorDenied.Error(),
+		Action:  denied.Action,
+		Reason:  denied.Reason,
+	}, http.StatusForbidden)
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+
+	requestLogging "github.com/johan-ag/testing/cmd/api/logging"
+	"github.com/johan-ag/testing/internal/audit"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// NewUnaryInterceptor is the gRPC counterpart of NewMiddleware, the bearer token is read
+// from the authorization metadata.
+func NewUnaryInterceptor(validator *auth.Validator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var header string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				header = values[0]
+			}
+		}
+
+		principal, err := validator.ValidateHeader(header)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		ctx = auth.WithPrincipal(ctx, principal)
+		ctx = audit.WithActor(ctx, principal.Subject)
+		ctx = logging.WithFields(ctx, log.String(logging.SubjectField, principal.Subject))
+		requestLogging.SetSubject(ctx, principal.Subject)
+
+		return handler(ctx, req)
+	}
+}
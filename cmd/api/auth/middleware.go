@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"net/http"
+
+	requestLogging "github.com/johan-ag/testing/cmd/api/logging"
+	"github.com/johan-ag/testing/internal/audit"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+// NewMiddleware returns a middleware that rejects the requests without a valid bearer token
+// and puts the authenticated principal in the request context, it is also recorded as the
+// actor of the audit events and in the logs. The requests to publicPaths go through
+// without a token.
+func NewMiddleware(validator *auth.Validator, publicPaths ...string) web.Middleware {
+	public := make(map[string]bool, len(publicPaths))
+	for _, path := range publicPaths {
+		public[path] = true
+	}
+
+	return func(next web.Handler) web.Handler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if public[r.URL.Path] {
+				return next(w, r)
+			}
+
+			principal, err := validator.ValidateHeader(r.Header.Get("Authorization"))
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				return web.NewError(http.StatusUnauthorized, err.Error())
+			}
+
+			ctx := auth.WithPrincipal(r.Context(), principal)
+			ctx = audit.WithActor(ctx, principal.Subject)
+			ctx = logging.WithFields(ctx, log.String(logging.SubjectField, principal.Subject))
+			requestLogging.SetSubject(ctx, principal.Subject)
+
+			return next(w, r.WithContext(ctx))
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package books
+
+import (
+	"errors"
+	"net/http"
+
+	authHandler "github.com/johan-ag/testing/cmd/api/auth"
+	"github.com/johan-ag/testing/cmd/api/response"
+	"github.com/johan-ag/testing/internal/books"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/johan-ag/testing/internal/users"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+// relationAuthor is the relation of the books that Find embeds on ?include=.
+const relationAuthor = "author"
+
+type handler struct {
+	service books.Service
+	users   users.Service
+}
+
+func NewHandler(service books.Service, usersService users.Service) *handler {
+	return &handler{
+		service,
+		usersService,
+	}
+}
+
+func (h *handler) Save(w http.ResponseWriter, r *http.Request) error {
+	var book books.Book
+	if err := web.DecodeJSON(r, &book); err != nil {
+		return web.NewError(http.StatusBadRequest, "error to read body")
+	}
+
+	id, err := h.service.Save(r.Context(), book.Title, book.Author)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	return web.EncodeJSON(w, id, http.StatusCreated)
+}
+
+func (h *handler) Find(w http.ResponseWriter, r *http.Request) error {
+	id, err := web.Params(r).Uint("id")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	shape, err := response.Parse(r, books.Book{}, relationAuthor)
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	book, err := h.service.Find(r.Context(), id)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	var related map[string]interface{}
+	if shape.Includes(relationAuthor) {
+		// the user replaces the id of the author, it is null when the author was deleted
+		authors, denied, err := h.users.FindMany(r.Context(), []uint{book.Author})
+		if err == nil {
+			err = denied[book.Author]
+		}
+		if err != nil {
+			return serviceError(w, err)
+		}
+
+		var author interface{}
+		if len(authors) > 0 {
+			author = authors[0]
+		}
+		related = map[string]interface{}{relationAuthor: author}
+	}
+
+	body, err := shape.Apply(book, related)
+	if err != nil {
+		return err
+	}
+
+	return web.EncodeJSON(w, body, http.StatusOK)
+}
+
+// serviceError maps the books domain errors to web errors, denials are written as forbidden responses.
+func serviceError(w http.ResponseWriter, err error) error {
+	var denied *authz.DeniedError
+	if errors.As(err, &denied) {
+		return authHandler.EncodeDenied(w, denied)
+	}
+
+	if errors.Is(err, books.ErrorBookNotFound) {
+		return web.NewError(http.StatusNotFound, err.Error())
+	}
+
+	return web.NewError(http.StatusInternalServerError, err.Error())
+}
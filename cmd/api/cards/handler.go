@@ -0,0 +1,178 @@
+package cards
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	authHandler "github.com/johan-ag/testing/cmd/api/auth"
+	"github.com/johan-ag/testing/cmd/api/response"
+	"github.com/johan-ag/testing/internal/cards"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+type handler struct {
+	service cards.Service
+}
+
+func NewHandler(service cards.Service) *handler {
+	return &handler{
+		service,
+	}
+}
+
+// LastSync returns the status of the latest sync of the catalog.
+func (h *handler) LastSync(w http.ResponseWriter, r *http.Request) error {
+	shape, err := response.Parse(r, cards.Run{})
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	run, err := h.service.LastRun(r.Context())
+	var denied *authz.DeniedError
+	if errors.As(err, &denied) {
+		return authHandler.EncodeDenied(w, denied)
+	}
+	if errors.Is(err, cards.ErrorNeverSynced) {
+		return web.NewError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return web.NewError(http.StatusInternalServerError, err.Error())
+	}
+
+	body, err := shape.Apply(run, nil)
+	if err != nil {
+		return err
+	}
+
+	return web.EncodeJSON(w, body, http.StatusOK)
+}
+
+// List returns a page of the catalog, shaped by ?fields=.
+func (h *handler) List(w http.ResponseWriter, r *http.Request) error {
+	limit, err := queryUint(r, "limit")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	offset, err := queryUint(r, "offset")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	shape, err := response.Parse(r, cards.Card{})
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	list, err := h.service.List(r.Context(), limit, offset)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	body := make([]interface{}, len(list))
+	for i, card := range list {
+		if body[i], err = shape.Apply(card, nil); err != nil {
+			return err
+		}
+	}
+
+	return web.EncodeJSON(w, body, http.StatusOK)
+}
+
+// Find returns a card of the catalog, shaped by ?fields=.
+func (h *handler) Find(w http.ResponseWriter, r *http.Request) error {
+	id, err := web.Params(r).Uint("id")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	shape, err := response.Parse(r, cards.Card{})
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	card, err := h.service.Find(r.Context(), id)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	body, err := shape.Apply(card, nil)
+	if err != nil {
+		return err
+	}
+
+	return web.EncodeJSON(w, body, http.StatusOK)
+}
+
+// Assign gives the card card_id to the user id.
+func (h *handler) Assign(w http.ResponseWriter, r *http.Request) error {
+	owner, id, err := ownership(r)
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.service.Assign(r.Context(), owner, id); err != nil {
+		return serviceError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// Unassign takes the card card_id from the user id.
+func (h *handler) Unassign(w http.ResponseWriter, r *http.Request) error {
+	owner, id, err := ownership(r)
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.service.Unassign(r.Context(), owner, id); err != nil {
+		return serviceError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ownership is the user and the card of the path of r.
+func ownership(r *http.Request) (uint, uint, error) {
+	owner, err := web.Params(r).Uint("id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	id, err := web.Params(r).Uint("card_id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return owner, id, nil
+}
+
+func queryUint(r *http.Request, key string) (uint, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseUint(value, 10, 32)
+	return uint(n), err
+}
+
+func serviceError(w http.ResponseWriter, err error) error {
+	var denied *authz.DeniedError
+	if errors.As(err, &denied) {
+		return authHandler.EncodeDenied(w, denied)
+	}
+
+	switch {
+	case errors.Is(err, cards.ErrorCardNotFound),
+		errors.Is(err, cards.ErrorCardNotOwned),
+		errors.Is(err, cards.ErrorOwnerNotFound):
+		return web.NewError(http.StatusNotFound, err.Error())
+	}
+
+	return web.NewError(http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// MaxComplexity is the highest cost of the queries run, see complexity.
+const MaxComplexity = 1000
+
+// maxCost saturates the costs so that they can't overflow.
+const maxCost = math.MaxInt32
+
+// complexity is the cost of the operation of doc named operationName, or of its only one.
+// Each field costs one and the fields selected under a list cost once for each item the
+// limit of the list lets it return, so users(limit: 10) { books { id } } costs 1 + 10 *
+// (1 + 10 * 1). doc must have been validated against schema.
+func complexity(schema graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) int {
+	w := walker{
+		fragments: map[string]*ast.FragmentDefinition{},
+		variables: variables,
+		defaults:  map[string]ast.Value{},
+	}
+
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch d := definition.(type) {
+		case *ast.FragmentDefinition:
+			w.fragments[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			if operation == nil && (operationName == "" || d.Name != nil && d.Name.Value == operationName) {
+				operation = d
+			}
+		}
+	}
+	if operation == nil {
+		return 0
+	}
+
+	for _, v := range operation.VariableDefinitions {
+		if v.DefaultValue != nil {
+			w.defaults[v.Variable.Name.Value] = v.DefaultValue
+		}
+	}
+
+	return w.selectionSet(schema.QueryType(), operation.SelectionSet)
+}
+
+type walker struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	defaults  map[string]ast.Value
+}
+
+// selectionSet is the cost of set selected on parent, which is nil for the introspection
+// types.
+func (w walker) selectionSet(parent *graphql.Object, set *ast.SelectionSet) int {
+	if set == nil {
+		return 0
+	}
+
+	cost := 0
+	for _, selection := range set.Selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			cost += w.field(parent, s)
+		case *ast.InlineFragment:
+			cost += w.selectionSet(parent, s.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := w.fragments[s.Name.Value]; ok {
+				cost += w.selectionSet(parent, fragment.SelectionSet)
+			}
+		}
+		cost = saturate(cost)
+	}
+
+	return cost
+}
+
+func (w walker) field(parent *graphql.Object, field *ast.Field) int {
+	var definition *graphql.FieldDefinition
+	if parent != nil {
+		definition = parent.Fields()[field.Name.Value]
+	}
+	if definition == nil {
+		return saturate(1 + w.selectionSet(nil, field.SelectionSet))
+	}
+
+	items := 1
+	if _, ok := graphql.GetNullable(definition.Type).(*graphql.List); ok {
+		items = w.limit(definition, field)
+	}
+
+	child, _ := graphql.GetNamed(definition.Type).(*graphql.Object)
+	return saturate(1 + items*w.selectionSet(child, field.SelectionSet))
+}
+
+// limit is the value of the limit argument of field, lists without it count as one item.
+func (w walker) limit(definition *graphql.FieldDefinition, field *ast.Field) int {
+	limit := 1
+	for _, arg := range definition.Args {
+		if arg.Name() == "limit" {
+			limit, _ = arg.DefaultValue.(int)
+		}
+	}
+
+	for _, arg := range field.Arguments {
+		if arg.Name.Value == "limit" {
+			if n, ok := w.int(arg.Value); ok {
+				limit = n
+			}
+		}
+	}
+
+	if limit < 0 {
+		return 0
+	}
+	return saturate(limit)
+}
+
+// int is the value of an int literal or variable.
+func (w walker) int(value ast.Value) (int, bool) {
+	switch v := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.ParseInt(v.Value, 10, 64)
+		return saturate64(n), err == nil
+	case *ast.Variable:
+		switch n := w.variables[v.Name.Value].(type) {
+		case float64:
+			return saturate64(int64(n)), true
+		case int:
+			return n, true
+		}
+		if defaultValue, ok := w.defaults[v.Name.Value]; ok {
+			return w.int(defaultValue)
+		}
+	}
+
+	return 0, false
+}
+
+func saturate(n int) int {
+	return saturate64(int64(n))
+}
+
+func saturate64(n int64) int {
+	if n > maxCost {
+		return maxCost
+	}
+	return int(n)
+}
@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/johan-ag/testing/internal/books"
+	"github.com/johan-ag/testing/internal/cards"
+	"github.com/johan-ag/testing/internal/users"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+// Path is where the GraphQL API is served.
+const Path = "/graphql"
+
+type handler struct {
+	schema graphql.Schema
+	users  users.Service
+	books  books.Service
+	cards  cards.Service
+}
+
+func NewHandler(usersService users.Service, booksService books.Service, cardsService cards.Service) (*handler, error) {
+	schema, err := newSchema(usersService, cardsService)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handler{
+		schema,
+		usersService,
+		booksService,
+		cardsService,
+	}, nil
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Serve runs the query of the request. The queries that don't parse, aren't valid or cost
+// more than MaxComplexity are rejected with a 400 before running, the errors of the fields
+// are reported along with the data.
+func (h *handler) Serve(w http.ResponseWriter, r *http.Request) error {
+	var req request
+	if err := web.DecodeJSON(r, &req); err != nil {
+		return web.NewError(http.StatusBadRequest, "error to read body")
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return web.EncodeJSON(w, &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, http.StatusBadRequest)
+	}
+
+	validation := graphql.ValidateDocument(&h.schema, doc, nil)
+	if !validation.IsValid {
+		return web.EncodeJSON(w, &graphql.Result{Errors: validation.Errors}, http.StatusBadRequest)
+	}
+
+	if cost := complexity(h.schema, doc, req.OperationName, req.Variables); cost > MaxComplexity {
+		err := fmt.Errorf("query complexity %d exceeds the limit of %d", cost, MaxComplexity)
+		return web.EncodeJSON(w, &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, http.StatusBadRequest)
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(r.Context(), newLoaders(h.users, h.books, h.cards)),
+	})
+
+	return web.EncodeJSON(w, result, http.StatusOK)
+}
@@ -0,0 +1,208 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/johan-ag/testing/internal/books"
+	"github.com/johan-ag/testing/internal/cards"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/johan-ag/testing/internal/users"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerServe(t *testing.T) {
+	tests := []struct {
+		name              string
+		executeBeforeTest func(u *users.MockService, b *books.MockService, c *cards.MockService)
+		query             string
+		variables         map[string]interface{}
+		expectedCode      int
+		expectedBody      string
+	}{
+		{
+			name: "user with books and cards",
+			executeBeforeTest: func(u *users.MockService, b *books.MockService, c *cards.MockService) {
+				u.EXPECT().
+					FindMany(gomock.Any(), []uint{1}).
+					Return([]users.User{{ID: 1, Name: "ana"}}, nil, nil)
+				b.EXPECT().
+					ListByAuthors(gomock.Any(), []uint{1}).
+					Return([]books.Book{{ID: 10, Title: "first", Author: 1}, {ID: 11, Title: "second", Author: 1}}, nil)
+				c.EXPECT().
+					ListByOwners(gomock.Any(), []uint{1}).
+					Return(map[uint][]cards.Card{1: {{ID: 5, Name: "Rick Sanchez"}}}, nil)
+			},
+			query:        `query($id: Int!) { user(id: $id) { name books(limit: 1) { title author { name } } cards { name } } }`,
+			variables:    map[string]interface{}{"id": 1},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"data":{"user":{"name":"ana","books":[{"title":"first","author":{"name":"ana"}}],"cards":[{"name":"Rick Sanchez"}]}}}`,
+		},
+		{
+			name: "relations of a list are loaded once",
+			executeBeforeTest: func(u *users.MockService, b *books.MockService, c *cards.MockService) {
+				u.EXPECT().
+					List(gomock.Any(), users.ListFilter{Limit: 3}).
+					Return([]users.User{{ID: 1, Name: "ana"}, {ID: 2, Name: "bob"}, {ID: 3, Name: "eve"}}, nil)
+				b.EXPECT().
+					ListByAuthors(gomock.Any(), []uint{1, 2, 3}).
+					Return([]books.Book{{ID: 10, Title: "first", Author: 1}, {ID: 11, Title: "second", Author: 2}}, nil)
+				u.EXPECT().
+					FindMany(gomock.Any(), []uint{1, 2}).
+					Return([]users.User{{ID: 1, Name: "ana"}, {ID: 2, Name: "bob"}}, nil, nil)
+			},
+			query:        `{ users(limit: 3) { id books { id author { name } } } }`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"data":{"users":[
+				{"id":1,"books":[{"id":10,"author":{"name":"ana"}}]},
+				{"id":2,"books":[{"id":11,"author":{"name":"bob"}}]},
+				{"id":3,"books":[]}
+			]}}`,
+		},
+		{
+			name: "denied fields are reported along with the data",
+			executeBeforeTest: func(u *users.MockService, b *books.MockService, c *cards.MockService) {
+				u.EXPECT().
+					FindMany(gomock.Any(), []uint{2}).
+					Return(nil, map[uint]error{2: &authz.DeniedError{Action: "users:read", Reason: authz.ReasonNotOwner}}, nil)
+			},
+			query:        `{ user(id: 2) { name } }`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"data":{"user":null},"errors":[{"message":"permission denied: users:read (not_owner)","locations":[{"line":1,"column":3}],"path":["user"]}]}`,
+		},
+		{
+			name: "denied users don't fail the others of the batch",
+			executeBeforeTest: func(u *users.MockService, b *books.MockService, c *cards.MockService) {
+				// the root fields are resolved in any order
+				u.EXPECT().
+					FindMany(gomock.Any(), gomock.Len(2)).
+					Return([]users.User{{ID: 1, Name: "ana"}}, map[uint]error{2: &authz.DeniedError{Action: "users:read", Reason: authz.ReasonNotOwner}}, nil)
+			},
+			query:        `{ mine: user(id: 1) { name } other: user(id: 2) { name } }`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"data":{"mine":{"name":"ana"},"other":null},"errors":[{"message":"permission denied: users:read (not_owner)","locations":[{"line":1,"column":30}],"path":["other"]}]}`,
+		},
+		{
+			name: "card catalog",
+			executeBeforeTest: func(u *users.MockService, b *books.MockService, c *cards.MockService) {
+				c.EXPECT().
+					List(gomock.Any(), uint(2), uint(4)).
+					Return([]cards.Card{{ID: 5, Name: "Rick Sanchez"}, {ID: 6, Name: "Morty Smith"}}, nil)
+			},
+			query:        `{ cards(limit: 2, offset: 4) { id name } }`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"data":{"cards":[{"id":5,"name":"Rick Sanchez"},{"id":6,"name":"Morty Smith"}]}}`,
+		},
+		{
+			name:         "too complex queries are rejected",
+			query:        `query($n: Int) { users(limit: 100) { books(limit: $n) { title } } }`,
+			variables:    map[string]interface{}{"n": 100},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"data":null,"errors":[{"message":"query complexity 10101 exceeds the limit of 1000","locations":[]}]}`,
+		},
+		{
+			name:         "invalid queries are rejected",
+			query:        `{ user(id: 1) { email } }`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"data":null,"errors":[{"message":"Cannot query field \"email\" on type \"User\".","locations":[{"line":1,"column":17}]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			u, b, c := users.NewMockService(ctrl), books.NewMockService(ctrl), cards.NewMockService(ctrl)
+			if tt.executeBeforeTest != nil {
+				tt.executeBeforeTest(u, b, c)
+			}
+
+			h, err := NewHandler(u, b, c)
+			require.NoError(t, err)
+
+			body, err := json.Marshal(request{Query: tt.query, Variables: tt.variables})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, Path, strings.NewReader(string(body))).WithContext(context.Background())
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			// when
+			err = h.Serve(rr, req)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedCode, rr.Code)
+			require.JSONEq(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestComplexity(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		variables    map[string]interface{}
+		expectedCost int
+	}{
+		{
+			name:         "fields",
+			query:        `{ user(id: 1) { id name } }`,
+			expectedCost: 3,
+		},
+		{
+			name:         "default limits",
+			query:        `{ users { books { id } } }`,
+			expectedCost: 1 + 20*(1+10*1),
+		},
+		{
+			name:         "limit variables with defaults",
+			query:        `query($n: Int = 2) { users(limit: $n) { id } }`,
+			expectedCost: 1 + 2*1,
+		},
+		{
+			name:         "fragments",
+			query:        `{ users(limit: 5) { ...books } } fragment books on User { books(limit: 2) { id title } }`,
+			expectedCost: 1 + 5*(1+2*2),
+		},
+		{
+			name:         "saturated",
+			query:        `query($n: Int) { users(limit: $n) { books(limit: $n) { author { books(limit: $n) { id } } } } }`,
+			variables:    map[string]interface{}{"n": float64(1 << 30)},
+			expectedCost: maxCost,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			h, err := NewHandler(nil, nil, nil)
+			require.NoError(t, err)
+
+			doc := parse(t, tt.query)
+
+			// when
+			cost := complexity(h.schema, doc, "", tt.variables)
+
+			// then
+			require.Equal(t, tt.expectedCost, cost)
+		})
+	}
+}
+
+func parse(t *testing.T, query string) *ast.Document {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(query)}),
+	})
+	require.NoError(t, err)
+
+	return doc
+}
@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/johan-ag/testing/internal/books"
+	"github.com/johan-ag/testing/internal/cards"
+	"github.com/johan-ag/testing/internal/users"
+)
+
+// batchFunc loads the values of keys at once, the keys missing from the map resolve to nil.
+// errs are the errors of single keys, such as the denials, while err fails all of them.
+type batchFunc func(ctx context.Context, keys []uint) (values map[uint]interface{}, errs map[uint]error, err error)
+
+// loader batches the loads of a request. The keys loaded while a level of the query is
+// resolved are collected and loaded with a single call of batch when the first of their
+// values is needed, the values are then kept for the rest of the request.
+type loader struct {
+	batch batchFunc
+
+	mu      sync.Mutex
+	pending []uint
+	queued  map[uint]bool
+	results map[uint]result
+}
+
+type result struct {
+	value interface{}
+	err   error
+}
+
+func newLoader(batch batchFunc) *loader {
+	return &loader{
+		batch:   batch,
+		queued:  map[uint]bool{},
+		results: map[uint]result{},
+	}
+}
+
+// Load queues key and returns the thunk resolving to its value, as the executor expects
+// from the resolvers that defer their work.
+func (l *loader) Load(ctx context.Context, key uint) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, done := l.results[key]; !done && !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.dispatch(ctx)
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		r := l.results[key]
+		return r.value, r.err
+	}
+}
+
+// dispatch loads the queued keys, if any.
+func (l *loader) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	if len(keys) == 0 {
+		return
+	}
+
+	values, errs, err := l.batch(ctx, keys)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		delete(l.queued, key)
+
+		keyErr := err
+		if keyErr == nil {
+			keyErr = errs[key]
+		}
+		l.results[key] = result{values[key], keyErr}
+	}
+}
+
+// loaders are the loaders of a request.
+type loaders struct {
+	users *loader
+	books *loader
+	cards *loader
+}
+
+// newLoaders loads users by id, and books and cards by the id of their author and owner.
+func newLoaders(usersService users.Service, booksService books.Service, cardsService cards.Service) *loaders {
+	return &loaders{
+		users: newLoader(func(ctx context.Context, ids []uint) (map[uint]interface{}, map[uint]error, error) {
+			list, denied, err := usersService.FindMany(ctx, ids)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			values := make(map[uint]interface{}, len(list))
+			for _, user := range list {
+				values[user.ID] = user
+			}
+
+			return values, denied, nil
+		}),
+		books: newLoader(func(ctx context.Context, authors []uint) (map[uint]interface{}, map[uint]error, error) {
+			list, err := booksService.ListByAuthors(ctx, authors)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			byAuthor := make(map[uint][]books.Book, len(authors))
+			for _, book := range list {
+				byAuthor[book.Author] = append(byAuthor[book.Author], book)
+			}
+
+			values := make(map[uint]interface{}, len(authors))
+			for _, author := range authors {
+				values[author] = byAuthor[author]
+			}
+
+			return values, nil, nil
+		}),
+		cards: newLoader(func(ctx context.Context, owners []uint) (map[uint]interface{}, map[uint]error, error) {
+			byOwner, err := cardsService.ListByOwners(ctx, owners)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			values := make(map[uint]interface{}, len(owners))
+			for _, owner := range owners {
+				values[owner] = byOwner[owner]
+			}
+
+			return values, nil, nil
+		}),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,213 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/johan-ag/testing/internal/books"
+	"github.com/johan-ag/testing/internal/cards"
+	"github.com/johan-ag/testing/internal/users"
+)
+
+// Page sizes of the list fields, their limit argument defaults to them.
+const (
+	defaultUsersLimit   = 20
+	defaultBooksLimit   = 10
+	defaultCardsLimit   = 10
+	defaultCatalogLimit = 20
+)
+
+// newSchema builds the schema over users, books and cards. The users, the books of a user
+// and the cards of a user are resolved through the loaders of the request, so that a list
+// of users costs one query per relation instead of one per user.
+func newSchema(usersService users.Service, cardsService cards.Service) (graphql.Schema, error) {
+	card := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Card",
+		Description: "A character of the card catalog.",
+		Fields: graphql.Fields{
+			"id":      {Type: graphql.NewNonNull(graphql.Int)},
+			"name":    {Type: graphql.NewNonNull(graphql.String)},
+			"status":  {Type: graphql.NewNonNull(graphql.String)},
+			"species": {Type: graphql.NewNonNull(graphql.String)},
+			"gender":  {Type: graphql.NewNonNull(graphql.String)},
+			"image":   {Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	book := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Book",
+		Description: "A book written by a user.",
+		Fields: graphql.Fields{
+			"id":    {Type: graphql.NewNonNull(graphql.Int)},
+			"title": {Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	user := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"id":   {Type: graphql.NewNonNull(graphql.Int)},
+			"name": {Type: graphql.NewNonNull(graphql.String)},
+			"age":  {Type: graphql.NewNonNull(graphql.Int)},
+			"createdAt": {
+				Type: graphql.NewNonNull(graphql.DateTime),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(users.User).CreatedAt, nil
+				},
+			},
+			"books": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(book))),
+				Description: "The books the user wrote.",
+				Args:        limitArgs(defaultBooksLimit),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, err := count(p, "limit")
+					if err != nil {
+						return nil, err
+					}
+
+					load := loadersFrom(p.Context).books.Load(p.Context, p.Source.(users.User).ID)
+
+					return func() (interface{}, error) {
+						value, err := load()
+						if err != nil {
+							return nil, err
+						}
+
+						list := value.([]books.Book)
+						if len(list) > limit {
+							list = list[:limit]
+						}
+						return list, nil
+					}, nil
+				},
+			},
+			"cards": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(card))),
+				Description: "The cards the user owns.",
+				Args:        limitArgs(defaultCardsLimit),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, err := count(p, "limit")
+					if err != nil {
+						return nil, err
+					}
+
+					load := loadersFrom(p.Context).cards.Load(p.Context, p.Source.(users.User).ID)
+
+					return func() (interface{}, error) {
+						value, err := load()
+						if err != nil {
+							return nil, err
+						}
+
+						list := value.([]cards.Card)
+						if len(list) > limit {
+							list = list[:limit]
+						}
+						return list, nil
+					}, nil
+				},
+			},
+		},
+	})
+
+	book.AddFieldConfig("author", &graphql.Field{
+		Type: user,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return loadersFrom(p.Context).users.Load(p.Context, p.Source.(books.Book).Author), nil
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"user": {
+				Type: user,
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := count(p, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return loadersFrom(p.Context).users.Load(p.Context, uint(id)), nil
+				},
+			},
+			"users": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(user))),
+				Args: graphql.FieldConfigArgument{
+					"limit":  {Type: graphql.Int, DefaultValue: defaultUsersLimit},
+					"offset": {Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, err := count(p, "limit")
+					if err != nil {
+						return nil, err
+					}
+
+					offset, err := count(p, "offset")
+					if err != nil {
+						return nil, err
+					}
+
+					// List reads a whole page when the limit is zero
+					if limit == 0 {
+						return []users.User{}, nil
+					}
+
+					return usersService.List(p.Context, users.ListFilter{
+						Limit:  uint(limit),
+						Offset: uint(offset),
+					})
+				},
+			},
+			"cards": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(card))),
+				Description: "The card catalog, in id order.",
+				Args: graphql.FieldConfigArgument{
+					"limit":  {Type: graphql.Int, DefaultValue: defaultCatalogLimit},
+					"offset": {Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, err := count(p, "limit")
+					if err != nil {
+						return nil, err
+					}
+
+					offset, err := count(p, "offset")
+					if err != nil {
+						return nil, err
+					}
+
+					// List reads a whole page when the limit is zero
+					if limit == 0 {
+						return []cards.Card{}, nil
+					}
+
+					return cardsService.List(p.Context, uint(limit), uint(offset))
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query: query,
+	})
+}
+
+func limitArgs(limit int) graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"limit": {Type: graphql.Int, DefaultValue: limit},
+	}
+}
+
+// count is the int argument name of p, which can't be negative.
+func count(p graphql.ResolveParams, name string) (int, error) {
+	n, _ := p.Args[name].(int)
+	if n < 0 {
+		return 0, fmt.Errorf("%s can't be negative", name)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,103 @@
+package imports
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	authHandler "github.com/johan-ag/testing/cmd/api/auth"
+	"github.com/johan-ag/testing/internal/imports"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+// formats maps the content types of the uploads to their import format.
+var formats = map[string]string{
+	"text/csv":             imports.FormatCSV,
+	"application/x-ndjson": imports.FormatNDJSON,
+	"application/ndjson":   imports.FormatNDJSON,
+}
+
+type handler struct {
+	service imports.Service
+}
+
+func NewHandler(service imports.Service) *handler {
+	return &handler{
+		service,
+	}
+}
+
+// Start accepts a CSV or NDJSON upload, told apart by the format query parameter or else
+// the content type, and answers with the import that loads it in the background.
+func (h *handler) Start(w http.ResponseWriter, r *http.Request) error {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format = formats[mediaType]
+	}
+
+	i, err := h.service.Start(r.Context(), format, r.Body)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/api/users/import/%d", i.ID))
+	return web.EncodeJSON(w, i, http.StatusAccepted)
+}
+
+func (h *handler) Find(w http.ResponseWriter, r *http.Request) error {
+	id, err := web.Params(r).Uint("id")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	i, err := h.service.Find(r.Context(), id)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	return web.EncodeJSON(w, i, http.StatusOK)
+}
+
+// Report downloads the CSV error report of a finished import.
+func (h *handler) Report(w http.ResponseWriter, r *http.Request) error {
+	id, err := web.Params(r).Uint("id")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	report, err := h.service.Report(r.Context(), id)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"users-import-%d-errors.csv\"", id))
+	w.WriteHeader(http.StatusOK)
+	_, err = io.WriteString(w, report)
+	return err
+}
+
+// serviceError maps the imports domain errors to web errors, denials are written as forbidden responses.
+func serviceError(w http.ResponseWriter, err error) error {
+	var denied *authz.DeniedError
+	if errors.As(err, &denied) {
+		return authHandler.EncodeDenied(w, denied)
+	}
+
+	switch {
+	case errors.Is(err, imports.ErrorImportNotFound):
+		return web.NewError(http.StatusNotFound, err.Error())
+	case errors.Is(err, imports.ErrorInvalidFormat):
+		return web.NewError(http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, imports.ErrorUploadTooLarge):
+		return web.NewError(http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, imports.ErrorImportRunning):
+		return web.NewError(http.StatusConflict, err.Error())
+	}
+
+	return web.NewError(http.StatusInternalServerError, err.Error())
+}
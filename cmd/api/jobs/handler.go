@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"errors"
+	"net/http"
+
+	authHandler "github.com/johan-ag/testing/cmd/api/auth"
+	"github.com/johan-ag/testing/internal/jobs"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+type handler struct {
+	service jobs.Service
+}
+
+func NewHandler(service jobs.Service) *handler {
+	return &handler{
+		service,
+	}
+}
+
+// Find returns the status of a job.
+func (h *handler) Find(w http.ResponseWriter, r *http.Request) error {
+	id, err := web.Params(r).Uint("id")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	job, err := h.service.Find(r.Context(), id)
+	var denied *authz.DeniedError
+	if errors.As(err, &denied) {
+		return authHandler.EncodeDenied(w, denied)
+	}
+	if errors.Is(err, jobs.ErrorJobNotFound) {
+		return web.NewError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return web.NewError(http.StatusInternalServerError, err.Error())
+	}
+
+	return web.EncodeJSON(w, job, http.StatusOK)
+}
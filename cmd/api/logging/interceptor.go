@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/johan-ag/testing/internal/audit"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryInterceptor is the gRPC counterpart of Middleware, the request ID travels in the
+// x-request-id metadata and the line is logged with the status code of the call. It must
+// be the first interceptor of the chain.
+func UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	key := strings.ToLower(RequestIDHeader)
+	var sent string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(key); len(values) > 0 {
+			sent = values[0]
+		}
+	}
+	requestID, err := requestIDFrom(sent)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(key, requestID))
+
+	e := &entry{}
+	ctx = context.WithValue(ctx, entryKey{}, e)
+	ctx = audit.WithRequestID(ctx, requestID)
+	ctx = logging.WithFields(ctx, log.String(logging.RequestIDField, requestID))
+
+	res, err := handler(ctx, req)
+	code := status.Code(err)
+
+	fields := []log.Field{
+		log.String("method", info.FullMethod),
+		log.String("code", code.String()),
+		log.Duration("latency", time.Since(start)),
+		log.String(logging.SubjectField, e.subject),
+	}
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		logging.Error(ctx, "rpc served", append(fields, log.Err(err))...)
+	default:
+		logging.Info(ctx, "rpc served", fields...)
+	}
+
+	return res, err
+}
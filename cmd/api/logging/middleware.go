@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/johan-ag/testing/cmd/api/request"
+	"github.com/johan-ag/testing/internal/audit"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/johan-ag/testing/internal/platform/tracing"
+	gonanoid "github.com/matoous/go-nanoid"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+// RequestIDHeader carries the ID that correlates the logs and audit events of a request,
+// it is generated when the client doesn't send a valid one.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength caps the request IDs taken from the clients, they are stored in the
+// audit events.
+const maxRequestIDLength = 64
+
+type entryKey struct{}
+
+// entry collects what the inner middlewares learn about the request.
+type entry struct {
+	subject string
+}
+
+// SetSubject records the principal that made the request in its log line.
+func SetSubject(ctx context.Context, subject string) {
+	if e, ok := ctx.Value(entryKey{}).(*entry); ok {
+		e.subject = subject
+	}
+}
+
+// Middleware assigns or propagates the request ID, puts it in the context logger and the
+// audit events, and logs one line per request once it is served. It must be the first
+// middleware so that it sees the requests rejected by the others.
+func Middleware(next web.Handler) web.Handler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		start := time.Now()
+
+		requestID, err := requestIDFrom(r.Header.Get(RequestIDHeader))
+		if err != nil {
+			return err
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		e := &entry{}
+		ctx := context.WithValue(r.Context(), entryKey{}, e)
+		ctx = audit.WithRequestID(ctx, requestID)
+		ctx = logging.WithFields(ctx, log.String(logging.RequestIDField, requestID))
+		if traceID := tracing.TraceID(ctx); traceID != "" {
+			ctx = logging.WithFields(ctx, log.String(logging.TraceIDField, traceID))
+		}
+
+		recorder := request.NewRecorder(w)
+		err = next(recorder, r.WithContext(ctx))
+		status := recorder.Status(err)
+
+		fields := []log.Field{
+			log.String("method", r.Method),
+			log.String("route", request.Route(r)),
+			log.Int("status", status),
+			log.Duration("latency", time.Since(start)),
+			log.String(logging.SubjectField, e.subject),
+		}
+		if status >= http.StatusInternalServerError {
+			logging.Error(ctx, "request served", append(fields, log.Err(err))...)
+		} else {
+			logging.Info(ctx, "request served", fields...)
+		}
+
+		return err
+	}
+}
+
+// requestIDFrom is the ID sent by the client when it is valid, or else a new one. Only the IDs
+// of up to maxRequestIDLength letters, digits, dashes and underscores are taken, so that
+// they fit in the audit events and can't forge log lines.
+func requestIDFrom(sent string) (string, error) {
+	if validRequestID(sent) {
+		return sent, nil
+	}
+
+	return gonanoid.Nanoid()
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+
+	return true
+}
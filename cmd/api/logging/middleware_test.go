@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johan-ag/testing/internal/audit"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestID         string
+		expectedRequestID func(t *testing.T, id string)
+	}{
+		{
+			name:      "middleware propagates request id",
+			requestID: "abc",
+			expectedRequestID: func(t *testing.T, id string) {
+				require.Equal(t, "abc", id)
+			},
+		},
+		{
+			name:      "middleware replaces too long request ids",
+			requestID: strings.Repeat("a", maxRequestIDLength+1),
+			expectedRequestID: func(t *testing.T, id string) {
+				require.NotEmpty(t, id)
+				require.LessOrEqual(t, len(id), maxRequestIDLength)
+			},
+		},
+		{
+			name:      "middleware replaces request ids with forbidden characters",
+			requestID: "abc\nlevel=error msg=forged",
+			expectedRequestID: func(t *testing.T, id string) {
+				require.NotContains(t, id, "forged")
+			},
+		},
+		{
+			name:      "middleware generates request id",
+			requestID: "",
+			expectedRequestID: func(t *testing.T, id string) {
+				require.NotEmpty(t, id)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var (
+				auditID string
+				fields  []log.Field
+			)
+			next := func(w http.ResponseWriter, r *http.Request) error {
+				SetSubject(r.Context(), "1")
+				auditID = audit.RequestID(r.Context())
+				fields = logging.Fields(r.Context())
+				w.WriteHeader(http.StatusNoContent)
+				return nil
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+			r.Header.Set(RequestIDHeader, tt.requestID)
+			w := httptest.NewRecorder()
+
+			// when
+			err := Middleware(next)(w, r)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, http.StatusNoContent, w.Code)
+
+			id := w.Header().Get(RequestIDHeader)
+			tt.expectedRequestID(t, id)
+			require.Equal(t, id, auditID)
+			require.Equal(t, []log.Field{log.String(logging.RequestIDField, id)}, fields)
+		})
+	}
+}
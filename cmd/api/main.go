@@ -3,16 +3,125 @@ package main
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/johan-ag/testing/api"
+	usersv1 "github.com/johan-ag/testing/api/users/v1"
+	auditHandler "github.com/johan-ag/testing/cmd/api/audit"
+	authMiddleware "github.com/johan-ag/testing/cmd/api/auth"
+	booksHandler "github.com/johan-ag/testing/cmd/api/books"
+	cardsHandler "github.com/johan-ag/testing/cmd/api/cards"
+	graphHandler "github.com/johan-ag/testing/cmd/api/graph"
+	importsHandler "github.com/johan-ag/testing/cmd/api/imports"
+	jobsHandler "github.com/johan-ag/testing/cmd/api/jobs"
+	loggingMiddleware "github.com/johan-ag/testing/cmd/api/logging"
+	metricsHandler "github.com/johan-ag/testing/cmd/api/metrics"
+	openapiHandler "github.com/johan-ag/testing/cmd/api/openapi"
+	rateLimitMiddleware "github.com/johan-ag/testing/cmd/api/ratelimit"
+	searchHandler "github.com/johan-ag/testing/cmd/api/search"
+	sessionMiddleware "github.com/johan-ag/testing/cmd/api/session"
+	tracingMiddleware "github.com/johan-ag/testing/cmd/api/tracing"
 	usersHandler "github.com/johan-ag/testing/cmd/api/users"
+	webhooksHandler "github.com/johan-ag/testing/cmd/api/webhooks"
+	"github.com/johan-ag/testing/internal/audit"
+	"github.com/johan-ag/testing/internal/books"
+	"github.com/johan-ag/testing/internal/cards"
+	"github.com/johan-ag/testing/internal/imports"
+	"github.com/johan-ag/testing/internal/jobs"
+	"github.com/johan-ag/testing/internal/outbox"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/cache"
 	"github.com/johan-ag/testing/internal/platform/database"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/johan-ag/testing/internal/platform/metrics"
+	"github.com/johan-ag/testing/internal/platform/ratelimit"
+	"github.com/johan-ag/testing/internal/platform/schedule"
+	"github.com/johan-ag/testing/internal/platform/tracing"
+	"github.com/johan-ag/testing/internal/search"
 	"github.com/johan-ag/testing/internal/users"
+	"github.com/johan-ag/testing/internal/webhooks"
 	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"github.com/mercadolibre/fury_go-core/pkg/transport/httpclient"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
 	"github.com/mercadolibre/fury_go-platform/pkg/fury"
 	"github.com/mercadolibre/fury_go-toolkit-kvs/pkg/kvs"
+	"google.golang.org/grpc"
 )
 
+const (
+	// serviceName identifies the application in the traces.
+	serviceName = "testing-api"
+
+	primaryDSN = "root:root@/testdb?parseTime=true"
+
+	// stickyWindow covers the replication lag, the reads of a client go to the primary for
+	// that long after it writes.
+	stickyWindow           = 5 * time.Second
+	replicasHealthInterval = 5 * time.Second
+
+	// slowQueryThreshold is the duration over which queries are logged.
+	slowQueryThreshold = 200 * time.Millisecond
+
+	// usersRetention is how long soft deleted users are kept before being purged.
+	usersRetention     = 30 * 24 * time.Hour
+	usersPurgeInterval = time.Hour
+
+	outboxRelayInterval = time.Second
+	outboxMaxAttempts   = 10
+	webhookTimeout      = 5 * time.Second
+
+	webhooksDispatchInterval = time.Second
+	webhooksMaxAttempts      = 8
+
+	// jobsPollInterval is how often the embedded worker looks for jobs.
+	jobsPollInterval = time.Second
+
+	// defaultCardsSyncSchedule is when the card catalog is synced, CARDS_SYNC_SCHEDULE overrides it.
+	defaultCardsSyncSchedule = "*/30 * * * *"
+
+	// usersReconcileSchedule is when the projection of the users in KVS is reconciled.
+	usersReconcileSchedule = "15 * * * *"
+
+	// rateLimitPurgeSchedule is when the expired rate limit buckets are dropped from KVS.
+	rateLimitPurgeSchedule = "*/10 * * * *"
+
+	// defaultGRPCAddr is where the gRPC API listens, GRPC_ADDR overrides it.
+	defaultGRPCAddr = ":9090"
+
+	// authLeeway tolerates the clock skew with the token issuer.
+	authLeeway = 30 * time.Second
+
+	// cacheInvalidationInterval is how often the caches look for the keys invalidated by
+	// the other instances.
+	cacheInvalidationInterval = time.Second
+)
+
+// usersCacheConfig sizes the cache of the users found by id.
+var usersCacheConfig = cache.Config{
+	Size:      10000,
+	LocalTTL:  30 * time.Second,
+	RemoteTTL: 10 * time.Minute,
+}
+
+// defaultRateLimits are the limits by route, RATE_LIMITS overrides them.
+var defaultRateLimits = ratelimit.Config{
+	"POST /api/users":        {Requests: 10, Window: time.Minute},
+	"POST /api/users:batch":  {Requests: 5, Window: time.Minute},
+	"POST /api/users/import": {Requests: 2, Window: time.Minute},
+	"POST /api/books":        {Requests: 10, Window: time.Minute},
+}
+
+// grpcRateLimitRoutes are the routes whose limits the gRPC methods share.
+var grpcRateLimitRoutes = map[string]string{
+	usersv1.UserService_CreateUser_FullMethodName: "POST /api/users",
+}
+
 func main() {
 	if err := run(); err != nil {
 		log.Error(context.Background(), "cannot run application", log.Err(err))
@@ -24,24 +133,291 @@ func run() error {
 		return err
 	}
 
-	db, err := sql.Open("mysql", "root:root@/testdb")
+	provider := tracing.NewProvider(serviceName, tracing.NewLogExporter())
+	defer provider.Shutdown(context.Background())
+
+	primary, replicas, err := openDatabases()
+	if err != nil {
+		return err
+	}
+	router := database.NewRouter(primary, replicas, stickyWindow)
+	go router.Run(jobContext("database_health"), replicasHealthInterval)
+
+	queries := database.New(database.NewTracedDB(database.NewInstrumentedDB(router, slowQueryThreshold)))
+
+	container, err := kvs.NewQueryableClient("container")
+	if err != nil {
+		return err
+	}
+	qkvs := metrics.NewKVSClient("container", tracing.NewKVSClient("container", container))
+
+	usersRepository := users.NewInstrumentedRepository(users.NewRepository(queries))
+	usersProjection := users.NewKVSProjection(qkvs)
+	usersCache := cache.New(users.CacheName, usersCacheConfig, qkvs, cache.NewMySQLBroadcaster(queries))
+	go usersCache.Run(jobContext("users_cache"), cacheInvalidationInterval)
+
+	usersService := users.NewTracedService(users.NewInstrumentedService(users.NewCachedService(users.NewService(users.NewProjectedRepository(usersRepository, usersProjection), usersProjection), usersCache)))
+
+	purgeJob := users.NewPurgeJob(usersRepository, usersRetention)
+	go purgeJob.Run(jobContext("users_purge"), usersPurgeInterval)
+
+	webhooksRepository := webhooks.NewRepository(queries)
+	webhooksService := webhooks.NewService(webhooksRepository, []string{
+		users.EventUserCreated,
+		users.EventUserActivated,
+		users.EventUserDeleted,
+	})
+
+	dispatcher := webhooks.NewDispatcher(webhooksRepository, webhooks.NewClient(webhookTimeout), webhooksMaxAttempts)
+	go dispatcher.Run(jobContext("webhooks_dispatcher"), webhooksDispatchInterval)
+
+	outboxRepository := outbox.NewRepository(queries)
+	publisher := outbox.NewMultiPublisher(newPublisher(), webhooks.NewPublisher(webhooksRepository))
+	relay := outbox.NewRelay(outboxRepository, publisher, outboxMaxAttempts)
+	go relay.Run(jobContext("outbox_relay"), outboxRelayInterval)
+
+	jobsRepository := jobs.NewRepository(queries)
+	jobsService := jobs.NewService(jobsRepository)
+
+	importsService := imports.NewService(imports.NewRepository(queries), usersService, jobsService)
+
+	upstream, err := cards.NewUpstream(tracing.NewClient(httpclient.New()))
+	if err != nil {
+		return err
+	}
+	cardsService := cards.NewService(cards.NewRepository(queries), upstream)
+
+	rateLimits, err := ratelimit.ParseConfig(os.Getenv("RATE_LIMITS"), defaultRateLimits)
+	if err != nil {
+		return err
+	}
+	limiter := ratelimit.NewLimiter(ratelimit.NewKVSStore(qkvs))
+
+	// the jobs and schedules run in this process unless EMBEDDED_WORKER is false, leaving
+	// them to cmd/worker
+	if os.Getenv("EMBEDDED_WORKER") != "false" {
+		worker := jobs.NewWorker(jobsRepository, importsService.Job(), cardsService.Job(), users.NewReconcileJob(usersRepository, usersProjection).Job())
+		go worker.Run(jobContext("jobs_worker"), jobsPollInterval)
+
+		scheduler := schedule.NewScheduler(schedule.NewMySQLLock(queries, instanceID()))
+		if err := scheduler.Add("cards_sync", cardsSyncSchedule(), cards.EnqueueSync(jobsService)); err != nil {
+			return err
+		}
+		if err := scheduler.Add("users_reconcile", usersReconcileSchedule, users.EnqueueReconcile(jobsService)); err != nil {
+			return err
+		}
+		if err := scheduler.Add("ratelimit_purge", rateLimitPurgeSchedule, limiter.Purge); err != nil {
+			return err
+		}
+		go scheduler.Run(jobContext("scheduler"))
+	}
+
+	auditRepository := audit.NewRepository(queries)
+	auditService := audit.NewService(auditRepository)
+
+	booksService := books.NewService(books.NewRepository(queries))
+	searchService := search.NewService(search.NewMySQLIndex(queries))
+
+	_usersHandler := usersHandler.NewHandler(usersService, booksService, cardsService)
+	_importsHandler := importsHandler.NewHandler(importsService)
+	_jobsHandler := jobsHandler.NewHandler(jobsService)
+	_booksHandler := booksHandler.NewHandler(booksService, usersService)
+	_cardsHandler := cardsHandler.NewHandler(cardsService)
+	_searchHandler := searchHandler.NewHandler(searchService)
+	_auditHandler := auditHandler.NewHandler(auditService)
+	_webhooksHandler := webhooksHandler.NewHandler(webhooksService)
+	_graphHandler, err := graphHandler.NewHandler(usersService, booksService, cardsService)
+	if err != nil {
+		return err
+	}
+
+	doc, err := api.OpenAPI()
+	if err != nil {
+		return err
+	}
+	_openapiHandler, err := openapiHandler.NewHandler(doc)
+	if err != nil {
+		return err
+	}
+
+	// the responses are only validated in development, their mismatches are logged
+	validation, err := openapiHandler.NewMiddleware(doc, os.Getenv("OPENAPI_VALIDATE_RESPONSES") == "true")
 	if err != nil {
 		return err
 	}
 
-	queries := database.New(db)
+	validator, err := newValidator()
+	if err != nil {
+		return err
+	}
 
-	qkvs, err := kvs.NewQueryableClient("container")
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		tracingMiddleware.UnaryInterceptor,
+		loggingMiddleware.UnaryInterceptor,
+		metricsHandler.UnaryInterceptor,
+		sessionMiddleware.NewUnaryInterceptor(stickyWindow),
+		authMiddleware.NewUnaryInterceptor(validator),
+		rateLimitMiddleware.NewUnaryInterceptor(limiter, rateLimits, grpcRateLimitRoutes),
+	))
+	usersv1.RegisterUserServiceServer(grpcServer, usersHandler.NewGRPCServer(usersService))
+
+	listener, err := net.Listen("tcp", grpcAddr())
 	if err != nil {
 		return err
 	}
-	usersRepository := users.NewRepository(queries)
-	usersService := users.NewService(usersRepository, qkvs)
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Error(context.Background(), "cannot serve grpc", log.Err(err))
+		}
+	}()
+	defer grpcServer.GracefulStop()
+
+	app.Use(
+		tracingMiddleware.Middleware,
+		loggingMiddleware.Middleware,
+		metricsHandler.Middleware,
+		sessionMiddleware.NewMiddleware(stickyWindow),
+		authMiddleware.NewMiddleware(validator, metricsHandler.Path, openapiHandler.SpecPath, openapiHandler.DocsPath),
+		validation,
+	)
 
-	_usersHandler := usersHandler.NewHandler(usersService)
+	rateLimited := func(route string) web.Middleware {
+		return rateLimitMiddleware.NewMiddleware(limiter, rateLimits, route)
+	}
 
-	app.Post("/api/users", _usersHandler.Save)
+	app.Post("/api/users", _usersHandler.Save, rateLimited("POST /api/users"))
+	app.Post("/api/users:batch", _usersHandler.SaveBatch, rateLimited("POST /api/users:batch"))
+	app.Post("/api/users:batchGet", _usersHandler.BatchGet)
+	app.Get("/api/users", _usersHandler.List)
+	app.Get("/api/users/export", _usersHandler.Export)
+	app.Post("/api/users/import", _importsHandler.Start, rateLimited("POST /api/users/import"))
+	app.Get("/api/users/import/{id}", _importsHandler.Find)
+	app.Get("/api/users/import/{id}/errors", _importsHandler.Report)
 	app.Get("/api/users/{id}", _usersHandler.Find)
+	app.Delete("/api/users/{id}", _usersHandler.Delete)
+	app.Put("/api/users/{id}", _usersHandler.Update)
+	app.Post("/api/users/{id}/restore", _usersHandler.Restore)
+	app.Post("/api/users/{id}/activate", _usersHandler.Activate)
+	app.Put("/api/users/{id}/cards/{card_id}", _cardsHandler.Assign)
+	app.Delete("/api/users/{id}/cards/{card_id}", _cardsHandler.Unassign)
+
+	app.Post("/api/books", _booksHandler.Save, rateLimited("POST /api/books"))
+	app.Get("/api/books/{id}", _booksHandler.Find)
+
+	app.Get("/api/cards", _cardsHandler.List)
+	app.Get("/api/cards/sync", _cardsHandler.LastSync)
+	app.Get("/api/cards/{id}", _cardsHandler.Find)
+
+	app.Get("/api/search", _searchHandler.Search)
+
+	app.Get("/api/jobs/{id}", _jobsHandler.Find)
+
+	app.Get("/api/audit", _auditHandler.List)
+
+	app.Post("/api/webhooks", _webhooksHandler.Subscribe)
+	app.Get("/api/webhooks", _webhooksHandler.List)
+	app.Get("/api/webhooks/{id}", _webhooksHandler.Find)
+	app.Delete("/api/webhooks/{id}", _webhooksHandler.Unsubscribe)
+	app.Get("/api/webhooks/{id}/deliveries", _webhooksHandler.Deliveries)
+	app.Post("/api/webhooks/{id}/deliveries/{delivery_id}/replay", _webhooksHandler.Replay)
+
+	app.Post(graphHandler.Path, _graphHandler.Serve)
+
+	app.Get(metricsHandler.Path, metricsHandler.Handler)
+	app.Get(openapiHandler.SpecPath, _openapiHandler.Spec)
+	app.Get(openapiHandler.DocsPath, _openapiHandler.Docs)
 
 	return app.Run()
 }
+
+// jobContext is the context of the background jobs, their logs carry the job name.
+func jobContext(job string) context.Context {
+	return logging.WithFields(context.Background(), log.String("job", job))
+}
+
+// openDatabases opens the primary database and the read replicas listed in DB_REPLICA_DSNS,
+// separated by commas.
+func openDatabases() (*sql.DB, []database.DBTX, error) {
+	primary, err := sql.Open("mysql", primaryDSN)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := metrics.RegisterDB(primary, "testdb"); err != nil {
+		return nil, nil, err
+	}
+
+	var replicas []database.DBTX
+	for i, dsn := range strings.Split(os.Getenv("DB_REPLICA_DSNS"), ",") {
+		if dsn == "" {
+			continue
+		}
+
+		replica, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if err := metrics.RegisterDB(replica, fmt.Sprintf("testdb_replica_%d", i)); err != nil {
+			return nil, nil, err
+		}
+		replicas = append(replicas, replica)
+	}
+
+	return primary, replicas, nil
+}
+
+// cardsSyncSchedule is the cron expression of the catalog sync.
+func cardsSyncSchedule() string {
+	if expr := os.Getenv("CARDS_SYNC_SCHEDULE"); expr != "" {
+		return expr
+	}
+
+	return defaultCardsSyncSchedule
+}
+
+// grpcAddr is the address of the gRPC API.
+func grpcAddr() string {
+	if addr := os.Getenv("GRPC_ADDR"); addr != "" {
+		return addr
+	}
+
+	return defaultGRPCAddr
+}
+
+// instanceID identifies this process in the schedule locks.
+func instanceID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// newPublisher posts the domain events to OUTBOX_WEBHOOK_URL when it is set and logs them otherwise.
+func newPublisher() outbox.Publisher {
+	url := os.Getenv("OUTBOX_WEBHOOK_URL")
+	if url == "" {
+		return outbox.NewLogPublisher()
+	}
+
+	return outbox.NewWebhookPublisher(url, &http.Client{Timeout: webhookTimeout})
+}
+
+// newValidator validates the bearer tokens issued by AUTH_ISSUER for AUTH_AUDIENCE, HS256
+// tokens are signed with AUTH_HS256_SECRET and RS256 ones with the keys of AUTH_JWKS_FILE.
+func newValidator() (*auth.Validator, error) {
+	config := auth.Config{
+		Issuer:     os.Getenv("AUTH_ISSUER"),
+		Audience:   os.Getenv("AUTH_AUDIENCE"),
+		HMACSecret: []byte(os.Getenv("AUTH_HS256_SECRET")),
+		Leeway:     authLeeway,
+	}
+
+	if path := os.Getenv("AUTH_JWKS_FILE"); path != "" {
+		keys, err := auth.LoadJWKS(path)
+		if err != nil {
+			return nil, err
+		}
+		config.RSAKeys = keys
+	}
+
+	return auth.NewValidator(config), nil
+}
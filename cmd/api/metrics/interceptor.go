@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryInterceptor records the rate, errors and latency of the gRPC calls by method.
+func UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	res, err := handler(ctx, req)
+
+	metrics.ObserveRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+	return res, err
+}
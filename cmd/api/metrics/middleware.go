@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/johan-ag/testing/cmd/api/request"
+	"github.com/johan-ag/testing/internal/platform/metrics"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+// Path serves the metrics, it is public so that scrapers don't need a token.
+const Path = "/metrics"
+
+// Middleware records the rate, errors and latency of the requests by route.
+func Middleware(next web.Handler) web.Handler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		start := time.Now()
+
+		recorder := request.NewRecorder(w)
+		err := next(recorder, r)
+
+		metrics.ObserveRequest(r.Method, request.Route(r), recorder.Status(err), time.Since(start))
+		return err
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler(w http.ResponseWriter, r *http.Request) error {
+	metrics.Handler().ServeHTTP(w, r)
+	return nil
+}
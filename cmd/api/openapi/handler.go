@@ -0,0 +1,64 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Paths of the document and its Swagger UI, they are public.
+const (
+	SpecPath = "/openapi.json"
+	DocsPath = "/docs"
+)
+
+// docsPage renders the Swagger UI over the document served at SpecPath.
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>testing-api</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@4.14.0/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@4.14.0/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "` + SpecPath + `", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+type handler struct {
+	spec []byte
+}
+
+// NewHandler serves doc, it is encoded once up front.
+func NewHandler(doc *openapi3.T) (*handler, error) {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handler{
+		spec,
+	}, nil
+}
+
+// Spec serves the OpenAPI document.
+func (h *handler) Spec(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(h.spec)
+	return err
+}
+
+// Docs serves the Swagger UI page.
+func (h *handler) Docs(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write([]byte(docsPage))
+	return err
+}
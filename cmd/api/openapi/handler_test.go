@@ -0,0 +1,603 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/johan-ag/testing/api"
+	auditHandler "github.com/johan-ag/testing/cmd/api/audit"
+	booksHandler "github.com/johan-ag/testing/cmd/api/books"
+	cardsHandler "github.com/johan-ag/testing/cmd/api/cards"
+	graphHandler "github.com/johan-ag/testing/cmd/api/graph"
+	importsHandler "github.com/johan-ag/testing/cmd/api/imports"
+	jobsHandler "github.com/johan-ag/testing/cmd/api/jobs"
+	metricsHandler "github.com/johan-ag/testing/cmd/api/metrics"
+	searchHandler "github.com/johan-ag/testing/cmd/api/search"
+	usersHandler "github.com/johan-ag/testing/cmd/api/users"
+	webhooksHandler "github.com/johan-ag/testing/cmd/api/webhooks"
+	"github.com/johan-ag/testing/internal/audit"
+	"github.com/johan-ag/testing/internal/books"
+	"github.com/johan-ag/testing/internal/cards"
+	"github.com/johan-ag/testing/internal/imports"
+	"github.com/johan-ag/testing/internal/jobs"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/johan-ag/testing/internal/search"
+	"github.com/johan-ag/testing/internal/users"
+	"github.com/johan-ag/testing/internal/webhooks"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+	"github.com/stretchr/testify/require"
+)
+
+type services struct {
+	users    *users.MockService
+	imports  *imports.MockService
+	books    *books.MockService
+	cards    *cards.MockService
+	search   *search.MockService
+	jobs     *jobs.MockService
+	audit    *audit.MockService
+	webhooks *webhooks.MockService
+}
+
+// newRouter mounts the handlers on the routes of main.
+func newRouter(t *testing.T, doc *openapi3.T, s services) *chi.Mux {
+	docs, err := NewHandler(doc)
+	require.NoError(t, err)
+
+	_users := usersHandler.NewHandler(s.users, s.books, s.cards)
+	_imports := importsHandler.NewHandler(s.imports)
+	_books := booksHandler.NewHandler(s.books, s.users)
+	_cards := cardsHandler.NewHandler(s.cards)
+	_search := searchHandler.NewHandler(s.search)
+	_jobs := jobsHandler.NewHandler(s.jobs)
+	_audit := auditHandler.NewHandler(s.audit)
+	_webhooks := webhooksHandler.NewHandler(s.webhooks)
+	_graph, err := graphHandler.NewHandler(s.users, s.books, s.cards)
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Post("/api/users", serve(_users.Save))
+	r.Post("/api/users:batch", serve(_users.SaveBatch))
+	r.Post("/api/users:batchGet", serve(_users.BatchGet))
+	r.Get("/api/users", serve(_users.List))
+	r.Get("/api/users/export", serve(_users.Export))
+	r.Post("/api/users/import", serve(_imports.Start))
+	r.Get("/api/users/import/{id}", serve(_imports.Find))
+	r.Get("/api/users/import/{id}/errors", serve(_imports.Report))
+	r.Get("/api/users/{id}", serve(_users.Find))
+	r.Delete("/api/users/{id}", serve(_users.Delete))
+	r.Put("/api/users/{id}", serve(_users.Update))
+	r.Post("/api/users/{id}/restore", serve(_users.Restore))
+	r.Post("/api/users/{id}/activate", serve(_users.Activate))
+	r.Put("/api/users/{id}/cards/{card_id}", serve(_cards.Assign))
+	r.Delete("/api/users/{id}/cards/{card_id}", serve(_cards.Unassign))
+	r.Post("/api/books", serve(_books.Save))
+	r.Get("/api/books/{id}", serve(_books.Find))
+	r.Get("/api/cards", serve(_cards.List))
+	r.Get("/api/cards/sync", serve(_cards.LastSync))
+	r.Get("/api/cards/{id}", serve(_cards.Find))
+	r.Get("/api/search", serve(_search.Search))
+	r.Get("/api/jobs/{id}", serve(_jobs.Find))
+	r.Get("/api/audit", serve(_audit.List))
+	r.Post("/api/webhooks", serve(_webhooks.Subscribe))
+	r.Get("/api/webhooks", serve(_webhooks.List))
+	r.Get("/api/webhooks/{id}", serve(_webhooks.Find))
+	r.Delete("/api/webhooks/{id}", serve(_webhooks.Unsubscribe))
+	r.Get("/api/webhooks/{id}/deliveries", serve(_webhooks.Deliveries))
+	r.Post("/api/webhooks/{id}/deliveries/{delivery_id}/replay", serve(_webhooks.Replay))
+	r.Post(graphHandler.Path, serve(_graph.Serve))
+	r.Get(metricsHandler.Path, serve(metricsHandler.Handler))
+	r.Get(SpecPath, serve(docs.Spec))
+	r.Get(DocsPath, serve(docs.Docs))
+
+	return r
+}
+
+// serve writes the errors returned by h as the web framework does.
+func serve(h web.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h(w, r)
+		var webErr *web.Error
+		if errors.As(err, &webErr) {
+			_ = web.EncodeJSON(w, webErr, webErr.Status)
+		}
+	}
+}
+
+func TestSpecMatchesHandlers(t *testing.T) {
+	doc, err := api.OpenAPI()
+	require.NoError(t, err)
+
+	specRouter, err := legacy.NewRouter(doc)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		method       string
+		target       string
+		contentType  string
+		body         string
+		mock         func(s services)
+		expectedCode int
+	}{
+		{
+			name:        "create user",
+			method:      http.MethodPost,
+			target:      "/api/users",
+			contentType: "application/json",
+			body:        `{"name":"name","age":30}`,
+			mock: func(s services) {
+				s.users.EXPECT().Save(gomock.Any(), "name", uint(30)).Return(uint(1), nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:        "create user denied",
+			method:      http.MethodPost,
+			target:      "/api/users",
+			contentType: "application/json",
+			body:        `{"name":"name","age":30}`,
+			mock: func(s services) {
+				s.users.EXPECT().Save(gomock.Any(), "name", uint(30)).Return(uint(0), &authz.DeniedError{Action: "users:create", Reason: authz.ReasonRoleRequired})
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:        "create users in batch",
+			method:      http.MethodPost,
+			target:      "/api/users:batch?mode=best_effort",
+			contentType: "application/json",
+			body:        `[{"name":"name","age":30},{"name":"other","age":20}]`,
+			mock: func(s services) {
+				s.users.EXPECT().SaveBatch(gomock.Any(), gomock.Any(), users.BatchBestEffort).Return([]users.BatchResult{{Index: 0, ID: 1}, {Index: 1, Error: "name taken"}}, nil)
+			},
+			expectedCode: http.StatusMultiStatus,
+		},
+		{
+			name:   "list users",
+			method: http.MethodGet,
+			target: "/api/users?limit=10&offset=0&include_deleted=true",
+			mock: func(s services) {
+				s.users.EXPECT().List(gomock.Any(), users.ListFilter{Limit: 10, IncludeDeleted: true}).Return([]users.User{{ID: 1, Name: "name", Age: 30}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "list users by age",
+			method: http.MethodGet,
+			target: "/api/users?age_gte=30&status=active&limit=10",
+			mock: func(s services) {
+				s.users.EXPECT().ListByAge(gomock.Any(), users.AgeFilter{MinAge: 30, Status: users.StatusActive, Limit: 10}).Return([]users.User{{ID: 1, Name: "name", Age: 30}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "find users by id",
+			method: http.MethodGet,
+			target: "/api/users?ids=2,1,3",
+			mock: func(s services) {
+				s.users.EXPECT().BatchGet(gomock.Any(), []uint{2, 1, 3}).Return(users.BatchGetResult{Users: []users.User{{ID: 2, Name: "name", Age: 30}, {ID: 1, Name: "other", Age: 20}}, Missing: []uint{3}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:        "batch get users",
+			method:      http.MethodPost,
+			target:      "/api/users:batchGet?fields=id,name",
+			contentType: "application/json",
+			body:        `{"ids":[1,2,3]}`,
+			mock: func(s services) {
+				s.users.EXPECT().BatchGet(gomock.Any(), []uint{1, 2, 3}).Return(users.BatchGetResult{
+					Users:   []users.User{{ID: 1, Name: "name", Age: 30}},
+					Missing: []uint{2},
+					Denied:  map[uint]error{3: &authz.DeniedError{Action: "users:read", Reason: authz.ReasonNotOwner}},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "export users",
+			method: http.MethodGet,
+			target: "/api/users/export?format=csv",
+			mock: func(s services) {
+				s.users.EXPECT().Export(gomock.Any(), false, gomock.Any()).DoAndReturn(func(_ context.Context, _ bool, fn func(users.User) error) error {
+					return fn(users.User{ID: 1, Name: "name", Age: 30})
+				})
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:        "start import",
+			method:      http.MethodPost,
+			target:      "/api/users/import",
+			contentType: "text/csv",
+			body:        "name,age\nname,30\n",
+			mock: func(s services) {
+				s.imports.EXPECT().Start(gomock.Any(), imports.FormatCSV, gomock.Any()).Return(imports.Import{ID: 1, Format: imports.FormatCSV, Status: imports.StatusPending, CreatedBy: "admin-1"}, nil)
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name:   "get import",
+			method: http.MethodGet,
+			target: "/api/users/import/1",
+			mock: func(s services) {
+				s.imports.EXPECT().Find(gomock.Any(), uint(1)).Return(imports.Import{ID: 1, Format: imports.FormatCSV, Status: imports.StatusRunning, CreatedBy: "admin-1"}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "get import errors of a running import",
+			method: http.MethodGet,
+			target: "/api/users/import/1/errors",
+			mock: func(s services) {
+				s.imports.EXPECT().Report(gomock.Any(), uint(1)).Return("", imports.ErrorImportRunning)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:   "get user",
+			method: http.MethodGet,
+			target: "/api/users/1",
+			mock: func(s services) {
+				s.users.EXPECT().Find(gomock.Any(), uint(1), false).Return(users.User{ID: 1, Name: "name", Age: 30}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "get user shaped",
+			method: http.MethodGet,
+			target: "/api/users/1?fields=id,name&include=books,cards",
+			mock: func(s services) {
+				s.users.EXPECT().Find(gomock.Any(), uint(1), false).Return(users.User{ID: 1, Name: "name", Age: 30}, nil)
+				s.books.EXPECT().ListByAuthors(gomock.Any(), []uint{1}).Return([]books.Book{{ID: 1, Title: "title", Author: 1}}, nil)
+				s.cards.EXPECT().ListByOwners(gomock.Any(), []uint{1}).Return(map[uint][]cards.Card{}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "get missing user",
+			method: http.MethodGet,
+			target: "/api/users/2",
+			mock: func(s services) {
+				s.users.EXPECT().Find(gomock.Any(), uint(2), false).Return(users.User{}, users.ErrorUserNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:        "update user",
+			method:      http.MethodPut,
+			target:      "/api/users/1",
+			contentType: "application/json",
+			body:        `{"name":"name","age":31}`,
+			mock: func(s services) {
+				s.users.EXPECT().Update(gomock.Any(), uint(1), "name", uint(31)).Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:   "delete user",
+			method: http.MethodDelete,
+			target: "/api/users/1",
+			mock: func(s services) {
+				s.users.EXPECT().Delete(gomock.Any(), uint(1)).Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:   "restore user",
+			method: http.MethodPost,
+			target: "/api/users/1/restore",
+			mock: func(s services) {
+				s.users.EXPECT().Restore(gomock.Any(), uint(1)).Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:        "activate activated user",
+			method:      http.MethodPost,
+			target:      "/api/users/1/activate",
+			contentType: "application/json",
+			body:        `{"code":"abc"}`,
+			mock: func(s services) {
+				s.users.EXPECT().Activate(gomock.Any(), uint(1), "abc").Return(users.ErrorUserAlreadyActivated)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:        "create book",
+			method:      http.MethodPost,
+			target:      "/api/books",
+			contentType: "application/json",
+			body:        `{"title":"title","author":1}`,
+			mock: func(s services) {
+				s.books.EXPECT().Save(gomock.Any(), "title", uint(1)).Return(uint(1), nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "get book",
+			method: http.MethodGet,
+			target: "/api/books/1",
+			mock: func(s services) {
+				s.books.EXPECT().Find(gomock.Any(), uint(1)).Return(books.Book{ID: 1, Title: "title", Author: 1}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "get book with its author",
+			method: http.MethodGet,
+			target: "/api/books/1?fields=title,author&include=author",
+			mock: func(s services) {
+				s.books.EXPECT().Find(gomock.Any(), uint(1)).Return(books.Book{ID: 1, Title: "title", Author: 1}, nil)
+				s.users.EXPECT().FindMany(gomock.Any(), []uint{1}).Return([]users.User{{ID: 1, Name: "name", Age: 30}}, nil, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "assign card",
+			method: http.MethodPut,
+			target: "/api/users/1/cards/5",
+			mock: func(s services) {
+				s.cards.EXPECT().Assign(gomock.Any(), uint(1), uint(5)).Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:   "unassign card not owned",
+			method: http.MethodDelete,
+			target: "/api/users/1/cards/5",
+			mock: func(s services) {
+				s.cards.EXPECT().Unassign(gomock.Any(), uint(1), uint(5)).Return(cards.ErrorCardNotOwned)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "list cards shaped",
+			method: http.MethodGet,
+			target: "/api/cards?limit=2&fields=id,name",
+			mock: func(s services) {
+				s.cards.EXPECT().List(gomock.Any(), uint(2), uint(0)).Return([]cards.Card{{ID: 1, Name: "Rick Sanchez"}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "get missing card",
+			method: http.MethodGet,
+			target: "/api/cards/9",
+			mock: func(s services) {
+				s.cards.EXPECT().Find(gomock.Any(), uint(9)).Return(cards.Card{}, cards.ErrorCardNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "get last card sync",
+			method: http.MethodGet,
+			target: "/api/cards/sync",
+			mock: func(s services) {
+				s.cards.EXPECT().LastRun(gomock.Any()).Return(cards.Run{ID: 1, Status: "completed", Added: 2}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "search",
+			method: http.MethodGet,
+			target: "/api/search?q=hary&limit=10",
+			mock: func(s services) {
+				s.search.EXPECT().Search(gomock.Any(), "hary", uint(10)).Return([]search.Hit{{Type: search.TypeUser, ID: 1, Text: "harry", Highlight: "<em>harry</em>", Score: 0.9}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "get job",
+			method: http.MethodGet,
+			target: "/api/jobs/1",
+			mock: func(s services) {
+				s.jobs.EXPECT().Find(gomock.Any(), uint(1)).Return(jobs.Job{ID: 1, Type: "users.import", Status: "queued"}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "list audit events",
+			method: http.MethodGet,
+			target: "/api/audit?entity=user&id=1",
+			mock: func(s services) {
+				s.audit.EXPECT().List(gomock.Any(), "user", "1").Return([]audit.Event{{ID: 1, Entity: "user", EntityID: "1", Action: "create"}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:        "subscribe webhook",
+			method:      http.MethodPost,
+			target:      "/api/webhooks",
+			contentType: "application/json",
+			body:        `{"url":"https://example.com/hook","event_types":["user.created"]}`,
+			mock: func(s services) {
+				s.webhooks.EXPECT().Subscribe(gomock.Any(), "https://example.com/hook", []string{"user.created"}).Return(webhooks.Subscription{ID: 1, URL: "https://example.com/hook", EventTypes: []string{"user.created"}, Secret: "secret"}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "list webhooks",
+			method: http.MethodGet,
+			target: "/api/webhooks",
+			mock: func(s services) {
+				s.webhooks.EXPECT().List(gomock.Any()).Return(nil, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "get webhook",
+			method: http.MethodGet,
+			target: "/api/webhooks/1",
+			mock: func(s services) {
+				s.webhooks.EXPECT().Find(gomock.Any(), uint(1)).Return(webhooks.Subscription{ID: 1, URL: "https://example.com/hook", EventTypes: []string{"user.created"}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "unsubscribe webhook",
+			method: http.MethodDelete,
+			target: "/api/webhooks/1",
+			mock: func(s services) {
+				s.webhooks.EXPECT().Unsubscribe(gomock.Any(), uint(1)).Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:   "list webhook deliveries",
+			method: http.MethodGet,
+			target: "/api/webhooks/1/deliveries",
+			mock: func(s services) {
+				s.webhooks.EXPECT().Deliveries(gomock.Any(), uint(1)).Return([]webhooks.Delivery{{ID: 1, SubscriptionID: 1, EventID: 1, EventType: "user.created", Payload: json.RawMessage(`{"id":1}`), Status: "delivered"}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "replay webhook delivery",
+			method: http.MethodPost,
+			target: "/api/webhooks/1/deliveries/1/replay",
+			mock: func(s services) {
+				s.webhooks.EXPECT().Replay(gomock.Any(), uint(1), uint(1)).Return(webhooks.Delivery{ID: 1, SubscriptionID: 1, EventID: 1, EventType: "user.created", Payload: json.RawMessage(`{"id":1}`), Status: "pending"}, nil)
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name:        "query graphql",
+			method:      http.MethodPost,
+			target:      graphHandler.Path,
+			contentType: "application/json",
+			body:        `{"query":"{ user(id: 1) { name books { title } } }"}`,
+			mock: func(s services) {
+				s.users.EXPECT().FindMany(gomock.Any(), []uint{1}).Return([]users.User{{ID: 1, Name: "name", Age: 30}}, nil, nil)
+				s.books.EXPECT().ListByAuthors(gomock.Any(), []uint{1}).Return([]books.Book{{ID: 1, Title: "title", Author: 1}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "query graphql too complex",
+			method:       http.MethodPost,
+			target:       graphHandler.Path,
+			contentType:  "application/json",
+			body:         `{"query":"{ users(limit: 100) { books(limit: 100) { title } } }"}`,
+			mock:         func(s services) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "get metrics",
+			method:       http.MethodGet,
+			target:       metricsHandler.Path,
+			mock:         func(s services) {},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "get openapi document",
+			method:       http.MethodGet,
+			target:       SpecPath,
+			mock:         func(s services) {},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "get docs",
+			method:       http.MethodGet,
+			target:       DocsPath,
+			mock:         func(s services) {},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	exercised := map[string]bool{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			s := services{
+				users:    users.NewMockService(ctrl),
+				imports:  imports.NewMockService(ctrl),
+				books:    books.NewMockService(ctrl),
+				cards:    cards.NewMockService(ctrl),
+				search:   search.NewMockService(ctrl),
+				jobs:     jobs.NewMockService(ctrl),
+				audit:    audit.NewMockService(ctrl),
+				webhooks: webhooks.NewMockService(ctrl),
+			}
+			tt.mock(s)
+			router := newRouter(t, doc, s)
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			route, pathParams, err := specRouter.FindRoute(req)
+			require.NoError(t, err)
+			exercised[route.Operation.OperationID] = true
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+					IncludeResponseStatus: true,
+				},
+			}
+			require.NoError(t, openapi3filter.ValidateRequest(context.Background(), input))
+
+			// when
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			// then
+			require.Equal(t, tt.expectedCode, rr.Code, rr.Body.String())
+
+			err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 rr.Code,
+				Header:                 rr.Header(),
+				Body:                   io.NopCloser(rr.Body),
+				Options:                input.Options,
+			})
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("every operation has an example", func(t *testing.T) {
+		for path, item := range doc.Paths {
+			for method, operation := range item.Operations() {
+				require.True(t, exercised[operation.OperationID], "%s %s has no example", method, path)
+			}
+		}
+	})
+}
+
+func TestSpecCoversRoutes(t *testing.T) {
+	// given
+	doc, err := api.OpenAPI()
+	require.NoError(t, err)
+
+	router := newRouter(t, doc, services{})
+
+	// when
+	err = chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		item := doc.Paths.Find(route)
+		if item == nil || item.GetOperation(method) == nil {
+			return errors.New(method + " " + route + " is not in the spec")
+		}
+		return nil
+	})
+
+	// then
+	require.NoError(t, err)
+}
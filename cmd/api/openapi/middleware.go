@@ -0,0 +1,249 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+// maxCapturedBody caps the response bodies kept for validation, larger ones such as the
+// exports only get their status and headers checked.
+const maxCapturedBody = 1 << 20
+
+func init() {
+	// the streamed and html bodies are checked as opaque strings
+	openapi3filter.RegisterBodyDecoder("application/x-ndjson", openapi3filter.FileBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("application/ndjson", openapi3filter.FileBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("text/html", openapi3filter.FileBodyDecoder)
+}
+
+// Violation is a part of the request that doesn't match the document. In is path, query,
+// header or body and Name the parameter or, for bodies, the dotted path of the field.
+type Violation struct {
+	In     string `json:"in"`
+	Name   string `json:"name,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// validationError is the body of the requests rejected by the middleware.
+type validationError struct {
+	Status  int         `json:"status"`
+	Message string      `json:"message"`
+	Errors  []Violation `json:"errors"`
+}
+
+// NewMiddleware validates the path, query params and JSON bodies of the requests against
+// doc before they reach the handlers, and answers the invalid ones with a 400 listing every
+// violation. The routes missing from doc go through. Uploads are not read, their size is
+// up to the handlers.
+//
+// When validateResponses is set the responses are validated too and their mismatches are
+// logged, it is meant for development to catch the drift between doc and the handlers.
+func NewMiddleware(doc *openapi3.T, validateResponses bool) (web.Middleware, error) {
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next web.Handler) web.Handler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			route, pathParams, err := router.FindRoute(r)
+			var routeErr *routers.RouteError
+			if errors.As(err, &routeErr) {
+				return next(w, r)
+			}
+			if err != nil {
+				return err
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options(r),
+			}
+			if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				return web.EncodeJSON(w, validationError{
+					Status:  http.StatusBadRequest,
+					Message: "request does not match the api specification",
+					Errors:  violations(err),
+				}, http.StatusBadRequest)
+			}
+
+			if !validateResponses {
+				return next(w, r)
+			}
+
+			c := &capture{ResponseWriter: w, status: http.StatusOK}
+			err = next(c, r)
+			if mismatch := validateResponse(input, c, err); mismatch != nil {
+				logging.Warn(r.Context(), "response does not match the api specification",
+					log.String("method", r.Method),
+					log.String("route", route.Path),
+					log.Err(mismatch),
+				)
+			}
+
+			return err
+		}
+	}, nil
+}
+
+func options(r *http.Request) *openapi3filter.Options {
+	return &openapi3filter.Options{
+		ExcludeRequestBody:    !isJSON(r.Header.Get("Content-Type")),
+		IncludeResponseStatus: true,
+		MultiError:            true,
+		SkipSettingDefaults:   true,
+		// the auth middleware already authenticated the request
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+}
+
+// validateResponse checks the response written to c, or the web error the framework will
+// write when the handler returned err.
+func validateResponse(input *openapi3filter.RequestValidationInput, c *capture, err error) error {
+	status, header, body := c.status, c.Header(), c.body.Bytes()
+
+	var webErr *web.Error
+	switch {
+	case errors.As(err, &webErr):
+		status = webErr.Status
+		header = http.Header{"Content-Type": []string{"application/json"}}
+		body, _ = json.Marshal(webErr)
+	case err != nil:
+		return nil
+	}
+
+	options := *input.Options
+	options.ExcludeResponseBody = c.truncated
+
+	return openapi3filter.ValidateResponse(input.Request.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 status,
+		Header:                 header,
+		Body:                   io.NopCloser(bytes.NewReader(body)),
+		Options:                &options,
+	})
+}
+
+// violations lists the parameters and body fields of err, as returned by ValidateRequest.
+func violations(err error) []Violation {
+	if multi, ok := err.(openapi3.MultiError); ok {
+		var list []Violation
+		for _, e := range multi {
+			list = append(list, violations(e)...)
+		}
+		return list
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return []Violation{{In: "request", Reason: err.Error()}}
+	}
+
+	if requestErr.Parameter != nil {
+		return []Violation{{
+			In:     requestErr.Parameter.In,
+			Name:   requestErr.Parameter.Name,
+			Reason: reason(requestErr),
+		}}
+	}
+
+	var list []Violation
+	for _, schemaErr := range schemaErrors(requestErr.Err) {
+		list = append(list, Violation{
+			In:     "body",
+			Name:   strings.Join(schemaErr.JSONPointer(), "."),
+			Reason: schemaErr.Reason,
+		})
+	}
+	if len(list) == 0 {
+		list = append(list, Violation{In: "body", Reason: reason(requestErr)})
+	}
+
+	return list
+}
+
+func reason(err *openapi3filter.RequestError) string {
+	var schemaErr *openapi3.SchemaError
+	switch {
+	case errors.As(err.Err, &schemaErr):
+		return schemaErr.Reason
+	case err.Err != nil:
+		return err.Err.Error()
+	}
+
+	return err.Reason
+}
+
+func schemaErrors(err error) []*openapi3.SchemaError {
+	if multi, ok := err.(openapi3.MultiError); ok {
+		var list []*openapi3.SchemaError
+		for _, e := range multi {
+			list = append(list, schemaErrors(e)...)
+		}
+		return list
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return []*openapi3.SchemaError{schemaErr}
+	}
+
+	return nil
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/json"
+}
+
+// capture passes the response through and keeps a copy of up to maxCapturedBody bytes.
+type capture struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (c *capture) WriteHeader(status int) {
+	c.status = status
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *capture) Write(b []byte) (int, error) {
+	if !c.truncated {
+		if c.body.Len()+len(b) > maxCapturedBody {
+			c.truncated = true
+			c.body.Reset()
+		} else {
+			c.body.Write(b)
+		}
+	}
+
+	return c.ResponseWriter.Write(b)
+}
+
+// Flush keeps the streamed responses flowing.
+func (c *capture) Flush() {
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (c *capture) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
@@ -0,0 +1,190 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/johan-ag/testing/api"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		target             string
+		contentType        string
+		body               string
+		expectedCalled     bool
+		expectedCode       int
+		expectedViolations []Violation
+	}{
+		{
+			name:           "valid request goes through",
+			method:         http.MethodPost,
+			target:         "/api/users",
+			contentType:    "application/json",
+			body:           `{"name":"name","age":30}`,
+			expectedCalled: true,
+			expectedCode:   http.StatusOK,
+		},
+		{
+			name:         "invalid path param is rejected",
+			method:       http.MethodGet,
+			target:       "/api/users/abc",
+			expectedCode: http.StatusBadRequest,
+			expectedViolations: []Violation{
+				{In: "path", Name: "id", Reason: `value abc: an invalid integer: invalid syntax`},
+			},
+		},
+		{
+			name:         "invalid query params are rejected",
+			method:       http.MethodGet,
+			target:       "/api/users/export?format=xml&include_deleted=maybe",
+			expectedCode: http.StatusBadRequest,
+			expectedViolations: []Violation{
+				{In: "query", Name: "format", Reason: `value is not one of the allowed values ["csv","ndjson"]`},
+				{In: "query", Name: "include_deleted", Reason: `value maybe: an invalid boolean: invalid syntax`},
+			},
+		},
+		{
+			name:         "invalid body fields are rejected",
+			method:       http.MethodPut,
+			target:       "/api/users/1",
+			contentType:  "application/json",
+			body:         `{"name":"","age":200}`,
+			expectedCode: http.StatusBadRequest,
+			expectedViolations: []Violation{
+				{In: "body", Name: "name", Reason: "minimum string length is 1"},
+				{In: "body", Name: "age", Reason: "number must be at most 150"},
+			},
+		},
+		{
+			name:           "uploads are not read",
+			method:         http.MethodPost,
+			target:         "/api/users/import",
+			contentType:    "text/csv",
+			body:           "name,age\n",
+			expectedCalled: true,
+			expectedCode:   http.StatusOK,
+		},
+		{
+			name:           "routes missing from the document go through",
+			method:         http.MethodGet,
+			target:         "/api/unknown",
+			expectedCalled: true,
+			expectedCode:   http.StatusOK,
+		},
+	}
+
+	doc, err := api.OpenAPI()
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			middleware, err := NewMiddleware(doc, false)
+			require.NoError(t, err)
+
+			var called bool
+			handler := middleware(func(w http.ResponseWriter, r *http.Request) error {
+				called = true
+				_, err := io.ReadAll(r.Body)
+				return err
+			})
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rr := httptest.NewRecorder()
+
+			// when
+			err = handler(rr, req)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedCalled, called)
+			require.Equal(t, tt.expectedCode, rr.Code)
+
+			if tt.expectedViolations != nil {
+				var body validationError
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+				require.ElementsMatch(t, tt.expectedViolations, body.Errors)
+			}
+		})
+	}
+}
+
+func TestMiddlewareValidatesResponses(t *testing.T) {
+	tests := []struct {
+		name             string
+		handler          web.Handler
+		expectedMismatch bool
+	}{
+		{
+			name: "documented response",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return web.EncodeJSON(w, map[string]interface{}{"id": 1, "type": "users.import", "status": "queued", "attempts": 0, "run_at": "2024-01-01T00:00:00Z", "created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-01T00:00:00Z"}, http.StatusOK)
+			},
+		},
+		{
+			name: "documented web error",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return web.NewError(http.StatusNotFound, "job not found")
+			},
+		},
+		{
+			name: "undocumented status",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return web.EncodeJSON(w, map[string]interface{}{"id": 1, "type": "users.import", "status": "queued", "attempts": 0, "run_at": "2024-01-01T00:00:00Z", "created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-01T00:00:00Z"}, http.StatusCreated)
+			},
+			expectedMismatch: true,
+		},
+		{
+			name: "body missing required fields",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return web.EncodeJSON(w, map[string]interface{}{"id": 1}, http.StatusOK)
+			},
+			expectedMismatch: true,
+		},
+	}
+
+	doc, err := api.OpenAPI()
+	require.NoError(t, err)
+
+	router, err := legacy.NewRouter(doc)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			req := httptest.NewRequest(http.MethodGet, "/api/jobs/1", nil)
+			route, pathParams, err := router.FindRoute(req)
+			require.NoError(t, err)
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options(req),
+			}
+
+			c := &capture{ResponseWriter: httptest.NewRecorder(), status: http.StatusOK}
+			err = tt.handler(c, req)
+
+			// when
+			mismatch := validateResponse(input, c, err)
+
+			// then
+			require.Equal(t, tt.expectedMismatch, mismatch != nil, "%v", mismatch)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/johan-ag/testing/internal/platform/ratelimit"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// NewUnaryInterceptor is the gRPC counterpart of NewMiddleware. routes maps the full method
+// names to the route whose limit and buckets they share, so that a client gets the same
+// quota over HTTP and gRPC. The methods without a route aren't limited.
+func NewUnaryInterceptor(limiter *ratelimit.Limiter, config ratelimit.Config, routes map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		route, ok := routes[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		limit, ok := config[route]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		var addr string
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			addr = p.Addr.String()
+		}
+
+		result, err := limiter.Allow(ctx, route, clientFrom(ctx, addr), limit)
+		if err != nil {
+			logging.Warn(ctx, "cannot apply rate limit", log.String("route", route), log.Err(err))
+			return handler(ctx, req)
+		}
+
+		md := metadata.Pairs(
+			"ratelimit-limit", strconv.Itoa(result.Limit),
+			"ratelimit-remaining", strconv.Itoa(result.Remaining),
+			"ratelimit-reset", strconv.Itoa(int(result.Reset/time.Second)),
+		)
+		if !result.Allowed {
+			md.Set("retry-after", strconv.Itoa(int(result.RetryAfter/time.Second)))
+		}
+		_ = grpc.SetHeader(ctx, md)
+
+		if !result.Allowed {
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+
+		return handler(ctx, req)
+	}
+}
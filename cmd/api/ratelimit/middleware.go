@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/johan-ag/testing/internal/platform/ratelimit"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+// NewMiddleware limits the requests to route by client with the limit configured for it,
+// the routes without one aren't limited. It must run after the auth middleware so that
+// clients are told apart by their principal. The requests are let through when the store
+// fails, so that the limits never take the API down.
+func NewMiddleware(limiter *ratelimit.Limiter, config ratelimit.Config, route string) web.Middleware {
+	limit, ok := config[route]
+
+	return func(next web.Handler) web.Handler {
+		if !ok {
+			return next
+		}
+
+		return func(w http.ResponseWriter, r *http.Request) error {
+			result, err := limiter.Allow(r.Context(), route, client(r), limit)
+			if err != nil {
+				logging.Warn(r.Context(), "cannot apply rate limit", log.String("route", route), log.Err(err))
+				return next(w, r)
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(int(result.Reset/time.Second)))
+
+			if !result.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(result.RetryAfter/time.Second)))
+				return web.NewError(http.StatusTooManyRequests, "rate limit exceeded")
+			}
+
+			return next(w, r)
+		}
+	}
+}
+
+// client identifies the caller by the subject of its principal, which the auth middleware
+// has verified, or else by its IP. Nothing the client sends unverified picks the bucket,
+// so that it can't get a fresh one on each request.
+func client(r *http.Request) string {
+	return clientFrom(r.Context(), r.RemoteAddr)
+}
+
+// clientFrom identifies the caller of ctx, addr is the remote address of its connection.
+func clientFrom(ctx context.Context, addr string) string {
+	if principal, ok := auth.PrincipalFrom(ctx); ok && principal.Subject != "" {
+		return "sub:" + principal.Subject
+	}
+
+	ip, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		ip = addr
+	}
+
+	return "ip:" + ip
+}
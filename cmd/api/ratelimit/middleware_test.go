@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/ratelimit"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMiddleware(t *testing.T) {
+	// given
+	route := "POST /api/users"
+	config := ratelimit.Config{route: {Requests: 1, Window: time.Minute}}
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore())
+
+	next := func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusCreated)
+		return nil
+	}
+	handler := NewMiddleware(limiter, config, route)(next)
+
+	request := func(subject string) (*httptest.ResponseRecorder, error) {
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: subject})
+		r := httptest.NewRequest(http.MethodPost, "/api/users", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		return w, handler(w, r)
+	}
+
+	// when
+	first, firstErr := request("1")
+	second, secondErr := request("1")
+	other, otherErr := request("2")
+
+	// then
+	require.NoError(t, firstErr)
+	require.Equal(t, http.StatusCreated, first.Code)
+	require.Equal(t, "1", first.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "0", first.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "60", first.Header().Get("RateLimit-Reset"))
+
+	var webErr *web.Error
+	require.True(t, errors.As(secondErr, &webErr))
+	require.Equal(t, http.StatusTooManyRequests, webErr.Status)
+	require.Equal(t, "60", second.Header().Get("Retry-After"))
+
+	require.NoError(t, otherErr)
+	require.Equal(t, http.StatusCreated, other.Code)
+}
+
+func TestClient(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      *auth.Principal
+		apiKey         string
+		expectedClient string
+	}{
+		{
+			name:           "client of a principal",
+			principal:      &auth.Principal{Subject: "1"},
+			apiKey:         "random",
+			expectedClient: "sub:1",
+		},
+		{
+			name:           "client without principal ignores the api key",
+			apiKey:         "random",
+			expectedClient: "ip:192.0.2.1",
+		},
+		{
+			name:           "client of a principal without subject",
+			principal:      &auth.Principal{},
+			expectedClient: "ip:192.0.2.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			r := httptest.NewRequest(http.MethodPost, "/api/users", nil)
+			r.Header.Set("X-API-Key", tt.apiKey)
+			if tt.principal != nil {
+				r = r.WithContext(auth.WithPrincipal(r.Context(), *tt.principal))
+			}
+
+			// when
+			c := client(r)
+
+			// then
+			require.Equal(t, tt.expectedClient, c)
+		})
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	// given
+	route := "POST /api/users"
+	config := ratelimit.Config{route: {Requests: 1, Window: time.Minute}}
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore())
+	routes := map[string]string{"/users.v1.UserService/CreateUser": route}
+	interceptor := NewUnaryInterceptor(limiter, config, routes)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "created", nil
+	}
+	call := func(method string) error {
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "1"})
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	// when
+	firstErr := call("/users.v1.UserService/CreateUser")
+	secondErr := call("/users.v1.UserService/CreateUser")
+	otherErr := call("/users.v1.UserService/GetUser")
+
+	// then
+	require.NoError(t, firstErr)
+	require.Equal(t, codes.ResourceExhausted, status.Code(secondErr))
+	require.NoError(t, otherErr)
+}
@@ -0,0 +1,69 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+// Unmatched is the route of the requests that matched no pattern.
+const Unmatched = "unmatched"
+
+// Route returns the pattern that matched r, or Unmatched, so that the paths the clients
+// make up don't turn into metric labels. It is only known once the router has served the
+// request.
+func Route(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+
+	return Unmatched
+}
+
+// Recorder remembers the status written by the handlers.
+type Recorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func NewRecorder(w http.ResponseWriter) *Recorder {
+	return &Recorder{
+		w,
+		http.StatusOK,
+	}
+}
+
+func (rec *Recorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// Flush passes the flushes of the streamed responses through to the wrapped writer.
+func (rec *Recorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the wrapped writer.
+func (rec *Recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// Status returns the status of the response, err is the one returned by the handler and
+// is written by the web framework once the middlewares return.
+func (rec *Recorder) Status(err error) int {
+	var webErr *web.Error
+	switch {
+	case errors.As(err, &webErr):
+		return webErr.Status
+	case err != nil:
+		return http.StatusInternalServerError
+	}
+
+	return rec.status
+}
@@ -0,0 +1,25 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorderFlush(t *testing.T) {
+	// given
+	w := httptest.NewRecorder()
+	var rec http.ResponseWriter = NewRecorder(w)
+
+	// when
+	f, ok := rec.(http.Flusher)
+	if ok {
+		f.Flush()
+	}
+
+	// then
+	assert.True(t, ok)
+	assert.True(t, w.Flushed)
+}
@@ -0,0 +1,133 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrorUnknownField    = errors.New("unknown field")
+	ErrorUnknownRelation = errors.New("unknown relation")
+)
+
+// Shape is the sparse fieldset and the relations to embed asked for by a request with
+// ?fields=id,name and ?include=books,cards. The zero Shape keeps the whole resource.
+type Shape struct {
+	fields  []string
+	include map[string]bool
+}
+
+// Parse reads the shape of r. The fields must be JSON fields of resource, a struct, and
+// the relations to include some of relations.
+func Parse(r *http.Request, resource interface{}, relations ...string) (Shape, error) {
+	var shape Shape
+
+	if fields := list(r.URL.Query().Get("fields")); len(fields) > 0 {
+		known := jsonFields(reflect.TypeOf(resource))
+		for _, field := range fields {
+			if !known[field] {
+				return Shape{}, fmt.Errorf("%w: %q", ErrorUnknownField, field)
+			}
+		}
+		shape.fields = fields
+	}
+
+	for _, relation := range list(r.URL.Query().Get("include")) {
+		if !contains(relations, relation) {
+			return Shape{}, fmt.Errorf("%w: %q", ErrorUnknownRelation, relation)
+		}
+		if shape.include == nil {
+			shape.include = map[string]bool{}
+		}
+		shape.include[relation] = true
+	}
+
+	return shape, nil
+}
+
+// Includes tells whether relation was asked to be embedded.
+func (s Shape) Includes(relation string) bool {
+	return s.include[relation]
+}
+
+// Apply trims resource to the fields of s and embeds the related resources, by relation.
+// resource is returned as is when s keeps it whole.
+func (s Shape) Apply(resource interface{}, related map[string]interface{}) (interface{}, error) {
+	if s.fields == nil && len(related) == 0 {
+		return resource, nil
+	}
+
+	raw, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+
+	shaped := make(map[string]interface{}, len(object)+len(related))
+	for field, value := range object {
+		if s.fields == nil || contains(s.fields, field) {
+			shaped[field] = value
+		}
+	}
+	for relation, value := range related {
+		shaped[relation] = value
+	}
+
+	return shaped, nil
+}
+
+// jsonFields are the names of the JSON fields of t, a struct or a pointer to one.
+func jsonFields(t reflect.Type) map[string]bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	fields := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		fields[name] = true
+	}
+
+	return fields
+}
+
+// list splits a comma separated param, skipping the empty items.
+func list(param string) []string {
+	var items []string
+	for _, item := range strings.Split(param, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
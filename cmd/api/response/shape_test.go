@@ -0,0 +1,75 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type resource struct {
+	ID     uint   `json:"id"`
+	Name   string `json:"name"`
+	Age    uint   `json:"age,omitempty"`
+	Secret string `json:"-"`
+}
+
+func TestShape(t *testing.T) {
+	tests := []struct {
+		name          string
+		target        string
+		related       map[string]interface{}
+		expectedBody  string
+		expectedError error
+	}{
+		{
+			name:         "whole resource by default",
+			target:       "/",
+			expectedBody: `{"id":1,"name":"name","age":30}`,
+		},
+		{
+			name:         "sparse fieldset",
+			target:       "/?fields=id,age",
+			expectedBody: `{"id":1,"age":30}`,
+		},
+		{
+			name:         "embedded relations",
+			target:       "/?fields=name&include=books",
+			related:      map[string]interface{}{"books": []int{1, 2}},
+			expectedBody: `{"name":"name","books":[1,2]}`,
+		},
+		{
+			name:          "unknown field",
+			target:        "/?fields=id,secret",
+			expectedError: ErrorUnknownField,
+		},
+		{
+			name:          "unknown relation",
+			target:        "/?include=cards",
+			expectedError: ErrorUnknownRelation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			r := httptest.NewRequest("GET", tt.target, nil)
+
+			// when
+			shape, err := Parse(r, resource{}, "books")
+
+			// then
+			require.ErrorIs(t, err, tt.expectedError)
+			if err != nil {
+				return
+			}
+
+			body, err := shape.Apply(resource{ID: 1, Name: "name", Age: 30, Secret: "secret"}, tt.related)
+			require.NoError(t, err)
+			raw, err := json.Marshal(body)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.expectedBody, string(raw))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package search
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	authHandler "github.com/johan-ag/testing/cmd/api/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/johan-ag/testing/internal/search"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+type handler struct {
+	service search.Service
+}
+
+func NewHandler(service search.Service) *handler {
+	return &handler{
+		service,
+	}
+}
+
+// Search returns the users and books matching ?q=, the most relevant first.
+func (h *handler) Search(w http.ResponseWriter, r *http.Request) error {
+	var limit uint
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return web.NewError(http.StatusBadRequest, err.Error())
+		}
+		limit = uint(n)
+	}
+
+	hits, err := h.service.Search(r.Context(), r.URL.Query().Get("q"), limit)
+	var denied *authz.DeniedError
+	if errors.As(err, &denied) {
+		return authHandler.EncodeDenied(w, denied)
+	}
+	if errors.Is(err, search.ErrorQueryTooShort) {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return web.NewError(http.StatusInternalServerError, err.Error())
+	}
+
+	return web.EncodeJSON(w, hits, http.StatusOK)
+}
@@ -0,0 +1,122 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/database"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Header carries the read-your-writes marker of the clients, in unix milliseconds. The
+// responses to the requests that write set it, and the clients send it back so that their
+// reads go to the primary until then, whichever instance serves them.
+const Header = "X-Read-Primary-Until"
+
+// NewMiddleware routes the statements of each request as the ones of the session the client
+// sends back in Header. The marker is capped at window from now, so that a forged one can't
+// keep a client on the primary.
+func NewMiddleware(window time.Duration) web.Middleware {
+	return func(next web.Handler) web.Handler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			sent := primaryUntil(r.Header.Get(Header), window)
+			session := database.NewSession(sent)
+
+			sw := &writer{w, session, sent, false}
+			err := next(sw, r.WithContext(database.WithSession(r.Context(), session)))
+
+			// the errors are written once the middlewares return
+			sw.mark()
+			return err
+		}
+	}
+}
+
+// NewUnaryInterceptor is the gRPC counterpart of NewMiddleware, the marker travels in the
+// x-read-primary-until metadata.
+func NewUnaryInterceptor(window time.Duration) grpc.UnaryServerInterceptor {
+	key := strings.ToLower(Header)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var value string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(key); len(values) > 0 {
+				value = values[0]
+			}
+		}
+
+		sent := primaryUntil(value, window)
+		session := database.NewSession(sent)
+
+		res, err := handler(database.WithSession(ctx, session), req)
+
+		if until := session.PrimaryUntil(); until.After(sent) {
+			_ = grpc.SetHeader(ctx, metadata.Pairs(key, format(until)))
+		}
+
+		return res, err
+	}
+}
+
+// primaryUntil parses the marker sent by a client, the invalid ones are ignored.
+func primaryUntil(value string, window time.Duration) time.Time {
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	until := time.UnixMilli(millis)
+	if max := time.Now().Add(window); until.After(max) {
+		return max
+	}
+
+	return until
+}
+
+func format(until time.Time) string {
+	return strconv.FormatInt(until.UnixMilli(), 10)
+}
+
+// writer sets Header before the response is written when the request wrote.
+type writer struct {
+	http.ResponseWriter
+	session *database.Session
+	sent    time.Time
+	marked  bool
+}
+
+func (w *writer) WriteHeader(status int) {
+	w.mark()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	w.mark()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *writer) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *writer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *writer) mark() {
+	if w.marked {
+		return
+	}
+	w.marked = true
+
+	if until := w.session.PrimaryUntil(); until.After(w.sent) {
+		w.Header().Set(Header, format(until))
+	}
+}
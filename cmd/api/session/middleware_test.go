@@ -0,0 +1,85 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/database"
+	"github.com/stretchr/testify/require"
+)
+
+// primary takes the writes routed to it.
+type primary struct {
+	database.DBTX
+}
+
+func (primary) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+func TestMiddleware(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		sent     string
+		write    bool
+		expected func(t *testing.T, marker string)
+	}{
+		{
+			name:  "middleware marks the requests that write",
+			write: true,
+			expected: func(t *testing.T, marker string) {
+				millis, err := strconv.ParseInt(marker, 10, 64)
+				require.NoError(t, err)
+				require.True(t, time.UnixMilli(millis).After(now))
+			},
+		},
+		{
+			name: "middleware doesn't mark the reads",
+			sent: strconv.FormatInt(now.Add(time.Second).UnixMilli(), 10),
+			expected: func(t *testing.T, marker string) {
+				require.Empty(t, marker)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			router := database.NewRouter(primary{}, nil, 5*time.Second)
+			next := func(w http.ResponseWriter, r *http.Request) error {
+				if tt.write {
+					_, _ = router.ExecContext(r.Context(), "UPDATE users SET name = ?", "name")
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return nil
+			}
+
+			r := httptest.NewRequest(http.MethodPut, "/api/users/1", nil)
+			r.Header.Set(Header, tt.sent)
+			w := httptest.NewRecorder()
+
+			// when
+			err := NewMiddleware(5*time.Second)(next)(w, r)
+
+			// then
+			require.NoError(t, err)
+			tt.expected(t, w.Header().Get(Header))
+		})
+	}
+}
+
+func TestPrimaryUntil(t *testing.T) {
+	window := 5 * time.Second
+	soon := time.Now().Add(time.Second).Truncate(time.Millisecond)
+
+	require.Equal(t, soon.UnixMilli(), primaryUntil(format(soon), window).UnixMilli())
+	require.False(t, primaryUntil(format(time.Now().Add(time.Hour)), window).After(time.Now().Add(window)))
+	require.True(t, primaryUntil("forged", window).IsZero())
+}
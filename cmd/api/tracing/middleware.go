@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/johan-ag/testing/cmd/api/request"
+	"github.com/johan-ag/testing/internal/platform/tracing"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware serves each request in a server span, continuing the trace of the client
+// when it sends the W3C trace context. It must be the first middleware so that the others
+// log and run within the span.
+func Middleware(next web.Handler) web.Handler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Start(ctx, "HTTP "+r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest("", "", r)...),
+		)
+		defer span.End()
+
+		recorder := request.NewRecorder(w)
+		err := next(recorder, r.WithContext(ctx))
+		status := recorder.Status(err)
+
+		route := request.Route(r)
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(
+			semconv.HTTPRouteKey.String(route),
+			semconv.HTTPStatusCodeKey.Int(status),
+		)
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindServer))
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		return err
+	}
+}
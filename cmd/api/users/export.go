@@ -0,0 +1,136 @@
+package users
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/johan-ag/testing/internal/users"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+// exportFlushEvery is the number of users written between flushes of the export stream.
+const exportFlushEvery = 500
+
+// exportColumns is the header of the CSV exports.
+var exportColumns = []string{"id", "name", "age", "created_at", "updated_at", "activated_at", "deleted_at"}
+
+// Export streams every user as CSV or NDJSON, given by the format query parameter. The
+// users are written as they are read so the response is never held in memory; an error
+// after the first user can only cut the stream short.
+func (h *handler) Export(w http.ResponseWriter, r *http.Request) error {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+
+	includeDeleted, err := queryBool(r, "include_deleted")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	buffered := bufio.NewWriter(w)
+	var (
+		contentType string
+		header      func() error
+		write       func(users.User) error
+		flush       func() error
+	)
+	switch format {
+	case "csv":
+		writer := csv.NewWriter(buffered)
+		contentType = "text/csv"
+		header = func() error { return writer.Write(exportColumns) }
+		write = func(u users.User) error { return writer.Write(csvRecord(u)) }
+		flush = func() error {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+			return buffered.Flush()
+		}
+	case "ndjson":
+		encoder := json.NewEncoder(buffered)
+		contentType = "application/x-ndjson"
+		header = func() error { return nil }
+		write = func(u users.User) error { return encoder.Encode(u) }
+		flush = buffered.Flush
+	default:
+		return web.NewError(http.StatusBadRequest, "format must be csv or ndjson")
+	}
+
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", "attachment; filename=\"users."+format+"\"")
+		w.WriteHeader(http.StatusOK)
+		return header()
+	}
+
+	written := 0
+	err = h.service.Export(r.Context(), includeDeleted, func(u users.User) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		if err := write(u); err != nil {
+			return err
+		}
+
+		written++
+		if written%exportFlushEvery != 0 {
+			return nil
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+
+		return nil
+	})
+	if err != nil && !started {
+		return serviceError(w, err)
+	}
+	if err != nil {
+		logging.Error(r.Context(), "users export cut short", log.Int("written", written), log.Err(err))
+		return nil
+	}
+
+	if !started {
+		if err := start(); err != nil {
+			return err
+		}
+	}
+
+	return flush()
+}
+
+func csvRecord(u users.User) []string {
+	return []string{
+		strconv.FormatUint(uint64(u.ID), 10),
+		u.Name,
+		strconv.FormatUint(uint64(u.Age), 10),
+		u.CreatedAt.Format(time.RFC3339),
+		u.UpdatedAt.Format(time.RFC3339),
+		formatTime(u.ActivatedAt),
+		formatTime(u.DeletedAt),
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,131 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	usersv1 "github.com/johan-ag/testing/api/users/v1"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/johan-ag/testing/internal/users"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcServer serves the users.v1.UserService with the same service as the HTTP handler.
+type grpcServer struct {
+	usersv1.UnimplementedUserServiceServer
+	service users.Service
+}
+
+func NewGRPCServer(service users.Service) *grpcServer {
+	return &grpcServer{
+		service: service,
+	}
+}
+
+func (s *grpcServer) CreateUser(ctx context.Context, req *usersv1.CreateUserRequest) (*usersv1.CreateUserResponse, error) {
+	id, err := s.service.Save(ctx, req.GetName(), uint(req.GetAge()))
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &usersv1.CreateUserResponse{Id: uint64(id)}, nil
+}
+
+func (s *grpcServer) GetUser(ctx context.Context, req *usersv1.GetUserRequest) (*usersv1.User, error) {
+	user, err := s.service.Find(ctx, uint(req.GetId()), req.GetIncludeDeleted())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return toProto(user), nil
+}
+
+func (s *grpcServer) ListUsers(ctx context.Context, req *usersv1.ListUsersRequest) (*usersv1.ListUsersResponse, error) {
+	list, err := s.service.List(ctx, users.ListFilter{
+		Limit:          uint(req.GetLimit()),
+		Offset:         uint(req.GetOffset()),
+		IncludeDeleted: req.GetIncludeDeleted(),
+	})
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	res := &usersv1.ListUsersResponse{Users: make([]*usersv1.User, 0, len(list))}
+	for _, user := range list {
+		res.Users = append(res.Users, toProto(user))
+	}
+
+	return res, nil
+}
+
+func (s *grpcServer) UpdateUser(ctx context.Context, req *usersv1.UpdateUserRequest) (*emptypb.Empty, error) {
+	if err := s.service.Update(ctx, uint(req.GetId()), req.GetName(), uint(req.GetAge())); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *grpcServer) DeleteUser(ctx context.Context, req *usersv1.DeleteUserRequest) (*emptypb.Empty, error) {
+	if err := s.service.Delete(ctx, uint(req.GetId())); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// statusError maps the users domain errors to gRPC status codes, as serviceError does for HTTP.
+// The unexpected errors are logged and reported as a generic internal error, their
+// messages aren't meant for the clients.
+func statusError(ctx context.Context, err error) error {
+	var denied *authz.DeniedError
+	if errors.As(err, &denied) {
+		if denied.Reason == authz.ReasonUnauthenticated {
+			return status.Error(codes.Unauthenticated, denied.Error())
+		}
+		return status.Error(codes.PermissionDenied, denied.Error())
+	}
+
+	switch {
+	case errors.Is(err, users.ErrorUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, users.ErrorInvalidName),
+		errors.Is(err, users.ErrorInvalidAge):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, users.ErrorUserAlreadyActivated):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	logging.Error(ctx, "cannot serve users rpc", log.Err(err))
+	return status.Error(codes.Internal, "internal error")
+}
+
+func toProto(user users.User) *usersv1.User {
+	return &usersv1.User{
+		Id:          uint64(user.ID),
+		Name:        user.Name,
+		Age:         uint32(user.Age),
+		CreatedAt:   timestamppb.New(user.CreatedAt),
+		UpdatedAt:   timestamppb.New(user.UpdatedAt),
+		ActivatedAt: optionalTimestamp(user.ActivatedAt),
+		DeletedAt:   optionalTimestamp(user.DeletedAt),
+	}
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,212 @@
+package users
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	usersv1 "github.com/johan-ag/testing/api/users/v1"
+	authHandler "github.com/johan-ag/testing/cmd/api/auth"
+	requestLogging "github.com/johan-ag/testing/cmd/api/logging"
+	metricsHandler "github.com/johan-ag/testing/cmd/api/metrics"
+	rateLimitHandler "github.com/johan-ag/testing/cmd/api/ratelimit"
+	tracingHandler "github.com/johan-ag/testing/cmd/api/tracing"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/johan-ag/testing/internal/platform/ratelimit"
+	"github.com/johan-ag/testing/internal/users"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var grpcSecret = []byte("secret")
+
+// newGRPCClient serves service through bufconn with the interceptors of main.
+func newGRPCClient(t *testing.T, service users.Service) usersv1.UserServiceClient {
+	listener := bufconn.Listen(1024 * 1024)
+
+	validator := auth.NewValidator(auth.Config{
+		Issuer:     "issuer",
+		Audience:   "users-api",
+		HMACSecret: grpcSecret,
+	})
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore())
+	limits := ratelimit.Config{"POST /api/users": {Requests: 10, Window: time.Minute}}
+	routes := map[string]string{usersv1.UserService_CreateUser_FullMethodName: "POST /api/users"}
+
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		tracingHandler.UnaryInterceptor,
+		requestLogging.UnaryInterceptor,
+		metricsHandler.UnaryInterceptor,
+		authHandler.NewUnaryInterceptor(validator),
+		rateLimitHandler.NewUnaryInterceptor(limiter, limits, routes),
+	))
+	usersv1.RegisterUserServiceServer(server, NewGRPCServer(service))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return usersv1.NewUserServiceClient(conn)
+}
+
+func withToken(t *testing.T, ctx context.Context) context.Context {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "admin-1",
+		"iss":   "issuer",
+		"aud":   "users-api",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{auth.RoleAdmin},
+	}).SignedString(grpcSecret)
+	require.NoError(t, err)
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func TestGRPCServer(t *testing.T) {
+	createdAt := time.Date(2022, 8, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		authorized      bool
+		mockService     func(service *users.MockService)
+		call            func(ctx context.Context, client usersv1.UserServiceClient) error
+		expectedCode    codes.Code
+		expectedMessage string
+	}{
+		{
+			name:       "create user",
+			authorized: true,
+			mockService: func(service *users.MockService) {
+				service.EXPECT().Save(gomock.Any(), "name", uint(30)).Return(uint(7), nil)
+			},
+			call: func(ctx context.Context, client usersv1.UserServiceClient) error {
+				res, err := client.CreateUser(ctx, &usersv1.CreateUserRequest{Name: "name", Age: 30})
+				if err == nil {
+					require.Equal(t, uint64(7), res.GetId())
+				}
+				return err
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:       "get user",
+			authorized: true,
+			mockService: func(service *users.MockService) {
+				service.EXPECT().Find(gomock.Any(), uint(7), false).Return(users.User{ID: 7, Name: "name", Age: 30, CreatedAt: createdAt}, nil)
+			},
+			call: func(ctx context.Context, client usersv1.UserServiceClient) error {
+				user, err := client.GetUser(ctx, &usersv1.GetUserRequest{Id: 7})
+				if err == nil {
+					require.Equal(t, "name", user.GetName())
+					require.True(t, user.GetCreatedAt().AsTime().Equal(createdAt))
+					require.Nil(t, user.GetDeletedAt())
+				}
+				return err
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:       "get missing user",
+			authorized: true,
+			mockService: func(service *users.MockService) {
+				service.EXPECT().Find(gomock.Any(), uint(7), true).Return(users.User{}, users.ErrorUserNotFound)
+			},
+			call: func(ctx context.Context, client usersv1.UserServiceClient) error {
+				_, err := client.GetUser(ctx, &usersv1.GetUserRequest{Id: 7, IncludeDeleted: true})
+				return err
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:       "list users",
+			authorized: true,
+			mockService: func(service *users.MockService) {
+				service.EXPECT().List(gomock.Any(), users.ListFilter{Limit: 10, Offset: 20}).Return([]users.User{{ID: 1}, {ID: 2}}, nil)
+			},
+			call: func(ctx context.Context, client usersv1.UserServiceClient) error {
+				res, err := client.ListUsers(ctx, &usersv1.ListUsersRequest{Limit: 10, Offset: 20})
+				if err == nil {
+					require.Len(t, res.GetUsers(), 2)
+				}
+				return err
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:       "update denied",
+			authorized: true,
+			mockService: func(service *users.MockService) {
+				service.EXPECT().Update(gomock.Any(), uint(7), "name", uint(30)).Return(&authz.DeniedError{Action: "users:update", Reason: authz.ReasonNotOwner})
+			},
+			call: func(ctx context.Context, client usersv1.UserServiceClient) error {
+				_, err := client.UpdateUser(ctx, &usersv1.UpdateUserRequest{Id: 7, Name: "name", Age: 30})
+				return err
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:       "delete fails",
+			authorized: true,
+			mockService: func(service *users.MockService) {
+				service.EXPECT().Delete(gomock.Any(), uint(7)).Return(users.ErrorSavingToDB)
+			},
+			call: func(ctx context.Context, client usersv1.UserServiceClient) error {
+				_, err := client.DeleteUser(ctx, &usersv1.DeleteUserRequest{Id: 7})
+				return err
+			},
+			expectedCode:    codes.Internal,
+			expectedMessage: "internal error",
+		},
+		{
+			name:        "call without token",
+			authorized:  false,
+			mockService: func(service *users.MockService) {},
+			call: func(ctx context.Context, client usersv1.UserServiceClient) error {
+				_, err := client.DeleteUser(ctx, &usersv1.DeleteUserRequest{Id: 7})
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			service := users.NewMockService(ctrl)
+			tt.mockService(service)
+
+			client := newGRPCClient(t, service)
+
+			ctx := context.Background()
+			if tt.authorized {
+				ctx = withToken(t, ctx)
+			}
+
+			// when
+			err := tt.call(ctx, client)
+
+			// then
+			require.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedMessage != "" {
+				require.Equal(t, tt.expectedMessage, status.Convert(err).Message())
+			}
+		})
+	}
+}
@@ -1,19 +1,38 @@
 package users
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
+	authHandler "github.com/johan-ag/testing/cmd/api/auth"
+	"github.com/johan-ag/testing/cmd/api/response"
+	"github.com/johan-ag/testing/internal/books"
+	"github.com/johan-ag/testing/internal/cards"
+	"github.com/johan-ag/testing/internal/platform/authz"
 	"github.com/johan-ag/testing/internal/users"
 	"github.com/mercadolibre/fury_go-core/pkg/web"
 )
 
+// Relations of the users that Find and List embed on ?include=.
+const (
+	relationBooks = "books"
+	relationCards = "cards"
+)
+
 type handler struct {
 	service users.Service
+	books   books.Service
+	cards   cards.Service
 }
 
-func NewHandler(service users.Service) *handler {
+func NewHandler(service users.Service, booksService books.Service, cardsService cards.Service) *handler {
 	return &handler{
 		service,
+		booksService,
+		cardsService,
 	}
 }
 
@@ -27,6 +46,13 @@ func (h *handler) Save(w http.ResponseWriter, r *http.Request) error {
 	}
 
 	id, err := h.service.Save(r.Context(), user.Name, user.Age)
+	var denied *authz.DeniedError
+	if errors.As(err, &denied) {
+		return authHandler.EncodeDenied(w, denied)
+	}
+	if errors.Is(err, users.ErrorInvalidName) || errors.Is(err, users.ErrorInvalidAge) {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
 	if err != nil {
 		err = web.NewError(http.StatusInternalServerError, "error to save user")
 		return web.EncodeJSON(w, err, http.StatusInternalServerError)
@@ -35,16 +61,423 @@ func (h *handler) Save(w http.ResponseWriter, r *http.Request) error {
 	return web.EncodeJSON(w, id, http.StatusCreated)
 }
 
+// SaveBatch creates up to users.MaxBatchSize users at once. By default the whole batch is
+// rejected when any user is invalid, mode=best_effort saves the valid ones instead and
+// answers with a multi-status listing the outcome of each.
+func (h *handler) SaveBatch(w http.ResponseWriter, r *http.Request) error {
+	mode := users.BatchMode(r.URL.Query().Get("mode"))
+	if mode == "" {
+		mode = users.BatchAllOrNothing
+	}
+
+	var batch []users.NewUser
+	if err := web.DecodeJSON(r, &batch); err != nil {
+		return web.NewError(http.StatusBadRequest, "error to read body")
+	}
+
+	results, err := h.service.SaveBatch(r.Context(), batch, mode)
+	if errors.Is(err, users.ErrorInvalidBatch) {
+		return web.EncodeJSON(w, results, http.StatusUnprocessableEntity)
+	}
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	status := http.StatusCreated
+	for _, result := range results {
+		if result.Error != "" {
+			status = http.StatusMultiStatus
+			break
+		}
+	}
+
+	return web.EncodeJSON(w, results, status)
+}
+
 func (h *handler) Find(w http.ResponseWriter, r *http.Request) error {
 	id, err := web.Params(r).Uint("id")
 	if err != nil {
 		return web.NewError(http.StatusBadRequest, err.Error())
 	}
 
-	user, err := h.service.Find(r.Context(), id)
+	includeDeleted, err := queryBool(r, "include_deleted")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	shape, err := response.Parse(r, users.User{}, relationBooks, relationCards)
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	user, err := h.service.Find(r.Context(), id, includeDeleted)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	related, err := h.related(r.Context(), shape, []uint{user.ID})
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	body, err := shape.Apply(user, related[user.ID])
+	if err != nil {
+		return err
+	}
+
+	return web.EncodeJSON(w, body, http.StatusOK)
+}
+
+func (h *handler) List(w http.ResponseWriter, r *http.Request) error {
+	// the users asked for by id are looked up instead of listing a page
+	if r.URL.Query().Has("ids") {
+		ids, err := queryUints(r, "ids")
+		if err != nil {
+			return web.NewError(http.StatusBadRequest, err.Error())
+		}
+
+		for _, key := range []string{"limit", "offset", "include_deleted", "age_gte", "status"} {
+			if r.URL.Query().Has(key) {
+				return web.NewError(http.StatusBadRequest, "ids can't be combined with "+key)
+			}
+		}
+
+		return h.batchGet(w, r, ids)
+	}
+
+	includeDeleted, err := queryBool(r, "include_deleted")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	limit, err := queryUint(r, "limit")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	offset, err := queryUint(r, "offset")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	minAge, err := queryUint(r, "age_gte")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+	status := r.URL.Query().Get("status")
+
+	shape, err := response.Parse(r, users.User{}, relationBooks, relationCards)
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	// the users filtered by age or status are listed from their projection in KVS, which
+	// leaves the deleted ones out
+	var list []users.User
+	if r.URL.Query().Has("age_gte") || status != "" {
+		if includeDeleted {
+			return web.NewError(http.StatusBadRequest, "include_deleted can't be combined with age_gte or status")
+		}
+
+		list, err = h.service.ListByAge(r.Context(), users.AgeFilter{
+			MinAge: minAge,
+			Status: status,
+			Limit:  limit,
+			Offset: offset,
+		})
+	} else {
+		list, err = h.service.List(r.Context(), users.ListFilter{
+			Limit:          limit,
+			Offset:         offset,
+			IncludeDeleted: includeDeleted,
+		})
+	}
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	ids := make([]uint, len(list))
+	for i, user := range list {
+		ids[i] = user.ID
+	}
+
+	related, err := h.related(r.Context(), shape, ids)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	body := make([]interface{}, len(list))
+	for i, user := range list {
+		if body[i], err = shape.Apply(user, related[user.ID]); err != nil {
+			return err
+		}
+	}
+
+	return web.EncodeJSON(w, body, http.StatusOK)
+}
+
+type batchGetRequest struct {
+	IDs []uint `json:"ids"`
+}
+
+type batchGetResponse struct {
+	Users   []interface{} `json:"users"`
+	Missing []uint        `json:"missing"`
+	Denied  []deniedUser  `json:"denied"`
+}
+
+// deniedUser tells why the user of ID can't be read.
+type deniedUser struct {
+	ID     uint   `json:"id"`
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+}
+
+// BatchGet finds up to users.MaxBatchGetSize users by id at once, for the lists of ids
+// too long for GET /api/users?ids=.
+func (h *handler) BatchGet(w http.ResponseWriter, r *http.Request) error {
+	var req batchGetRequest
+	if err := web.DecodeJSON(r, &req); err != nil {
+		return web.NewError(http.StatusBadRequest, "error to read body")
+	}
+
+	return h.batchGet(w, r, req.IDs)
+}
+
+// batchGet encodes the users of ids found in the order they were asked for, and apart the
+// ids of the ones that weren't and the ones the caller can't read.
+func (h *handler) batchGet(w http.ResponseWriter, r *http.Request, ids []uint) error {
+	shape, err := response.Parse(r, users.User{}, relationBooks, relationCards)
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.service.BatchGet(r.Context(), ids)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	found := make([]uint, len(result.Users))
+	for i, user := range result.Users {
+		found[i] = user.ID
+	}
+
+	related, err := h.related(r.Context(), shape, found)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	body := batchGetResponse{
+		Users:   make([]interface{}, len(result.Users)),
+		Missing: result.Missing,
+		Denied:  []deniedUser{},
+	}
+	for i, user := range result.Users {
+		if body.Users[i], err = shape.Apply(user, related[user.ID]); err != nil {
+			return err
+		}
+	}
+	for _, id := range ids {
+		err, ok := result.Denied[id]
+		if !ok {
+			continue
+		}
+		// the repeated ids are denied once
+		delete(result.Denied, id)
+
+		var denied *authz.DeniedError
+		if !errors.As(err, &denied) {
+			return serviceError(w, err)
+		}
+		body.Denied = append(body.Denied, deniedUser{id, denied.Action, denied.Reason})
+	}
+
+	return web.EncodeJSON(w, body, http.StatusOK)
+}
+
+// related loads the relations shape includes for the users of ids, by user and relation.
+// Each relation is loaded for all the users at once.
+func (h *handler) related(ctx context.Context, shape response.Shape, ids []uint) (map[uint]map[string]interface{}, error) {
+	related := make(map[uint]map[string]interface{}, len(ids))
+	if len(ids) == 0 {
+		return related, nil
+	}
+
+	if shape.Includes(relationBooks) {
+		list, err := h.books.ListByAuthors(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		byAuthor := make(map[uint][]books.Book, len(ids))
+		for _, book := range list {
+			byAuthor[book.Author] = append(byAuthor[book.Author], book)
+		}
+		for _, id := range ids {
+			list := byAuthor[id]
+			if list == nil {
+				list = []books.Book{}
+			}
+			embed(related, id, relationBooks, list)
+		}
+	}
+
+	if shape.Includes(relationCards) {
+		byOwner, err := h.cards.ListByOwners(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			list := byOwner[id]
+			if list == nil {
+				list = []cards.Card{}
+			}
+			embed(related, id, relationCards, list)
+		}
+	}
+
+	return related, nil
+}
+
+func embed(related map[uint]map[string]interface{}, id uint, relation string, value interface{}) {
+	if related[id] == nil {
+		related[id] = map[string]interface{}{}
+	}
+	related[id][relation] = value
+}
+
+func (h *handler) Delete(w http.ResponseWriter, r *http.Request) error {
+	id, err := web.Params(r).Uint("id")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		return serviceError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *handler) Restore(w http.ResponseWriter, r *http.Request) error {
+	id, err := web.Params(r).Uint("id")
 	if err != nil {
-		return web.NewError(http.StatusInternalServerError, err.Error())
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.service.Restore(r.Context(), id); err != nil {
+		return serviceError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *handler) Update(w http.ResponseWriter, r *http.Request) error {
+	id, err := web.Params(r).Uint("id")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var user users.User
+	if err := web.DecodeJSON(r, &user); err != nil {
+		return web.NewError(http.StatusBadRequest, "error to read body")
+	}
+
+	if err := h.service.Update(r.Context(), id, user.Name, user.Age); err != nil {
+		return serviceError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+type activateRequest struct {
+	Code string `json:"code"`
+}
+
+func (h *handler) Activate(w http.ResponseWriter, r *http.Request) error {
+	id, err := web.Params(r).Uint("id")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var req activateRequest
+	if err := web.DecodeJSON(r, &req); err != nil {
+		return web.NewError(http.StatusBadRequest, "error to read body")
+	}
+
+	if err := h.service.Activate(r.Context(), id, req.Code); err != nil {
+		return serviceError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// serviceError maps the users domain errors to web errors, denials are written as forbidden responses.
+func serviceError(w http.ResponseWriter, err error) error {
+	var denied *authz.DeniedError
+	if errors.As(err, &denied) {
+		return authHandler.EncodeDenied(w, denied)
+	}
+
+	switch {
+	case errors.Is(err, users.ErrorUserNotFound):
+		return web.NewError(http.StatusNotFound, err.Error())
+	case errors.Is(err, users.ErrorInvalidActivationCode),
+		errors.Is(err, users.ErrorEmptyBatch),
+		errors.Is(err, users.ErrorInvalidBatchMode),
+		errors.Is(err, users.ErrorInvalidStatus),
+		errors.Is(err, users.ErrorInvalidName),
+		errors.Is(err, users.ErrorInvalidAge),
+		errors.Is(err, users.ErrorNoIDs),
+		errors.Is(err, users.ErrorTooManyIDs):
+		return web.NewError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, users.ErrorBatchTooLarge):
+		return web.NewError(http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, users.ErrorUserAlreadyActivated):
+		return web.NewError(http.StatusConflict, err.Error())
+	}
+
+	return web.NewError(http.StatusInternalServerError, err.Error())
+}
+
+func queryBool(r *http.Request, key string) (bool, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+func queryUint(r *http.Request, key string) (uint, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseUint(value, 10, 32)
+	return uint(n), err
+}
+
+// queryUints parses a comma separated list of ids.
+func queryUints(r *http.Request, key string) ([]uint, error) {
+	var list []uint
+	for _, value := range strings.Split(r.URL.Query().Get(key), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, uint(n))
 	}
 
-	return web.EncodeJSON(w, user, http.StatusCreated)
+	return list, nil
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/johan-ag/testing/internal/platform/auth"
 	"github.com/johan-ag/testing/internal/platform/database"
 	"github.com/johan-ag/testing/internal/platform/kvs"
 	"github.com/johan-ag/testing/internal/users"
@@ -21,6 +22,7 @@ func TestHandlerSave(t *testing.T) {
 	tests := []struct {
 		name             string
 		createRepository func(queries *database.Queries) users.Repository
+		principal        *auth.Principal
 		body             string
 		expectedCode     int
 	}{
@@ -29,14 +31,33 @@ func TestHandlerSave(t *testing.T) {
 			createRepository: func(queries *database.Queries) users.Repository {
 				return users.NewRepository(queries)
 			},
+			principal:    &auth.Principal{Subject: "100", Roles: []string{auth.RoleAdmin}},
 			body:         `{"name":"name", "age": 30}`,
 			expectedCode: http.StatusCreated,
 		},
+		{
+			name: "save handler test service principal",
+			createRepository: func(queries *database.Queries) users.Repository {
+				return users.NewRepository(queries)
+			},
+			principal:    &auth.Principal{Subject: "svc", Roles: []string{auth.RoleService}},
+			body:         `{"name":"name", "age": 30}`,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "save handler test forbidden without principal",
+			createRepository: func(queries *database.Queries) users.Repository {
+				return users.NewRepository(queries)
+			},
+			body:         `{"name":"name", "age": 30}`,
+			expectedCode: http.StatusForbidden,
+		},
 		{
 			name: "save handler test bad request",
 			createRepository: func(queries *database.Queries) users.Repository {
 				return users.NewRepository(queries)
 			},
+			principal:    &auth.Principal{Subject: "100", Roles: []string{auth.RoleAdmin}},
 			body:         ``,
 			expectedCode: http.StatusBadRequest,
 		},
@@ -57,11 +78,14 @@ func TestHandlerSave(t *testing.T) {
 			qkvs := kvs.NewMockQueryableClient(ctrl)
 
 			repository := tt.createRepository(queries)
-			service := users.NewService(repository, qkvs)
+			service := users.NewService(repository, users.NewKVSProjection(qkvs))
 
-			handler := NewHandler(service)
+			handler := NewHandler(service, nil, nil)
 
 			req := httptest.NewRequest("POST", "/api/users?siteId=Soysite", bytes.NewReader([]byte(tt.body)))
+			if tt.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), *tt.principal))
+			}
 			rr := httptest.NewRecorder()
 
 			// when
@@ -0,0 +1,129 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+
+	authHandler "github.com/johan-ag/testing/cmd/api/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/johan-ag/testing/internal/webhooks"
+	"github.com/mercadolibre/fury_go-core/pkg/web"
+)
+
+type handler struct {
+	service webhooks.Service
+}
+
+func NewHandler(service webhooks.Service) *handler {
+	return &handler{
+		service,
+	}
+}
+
+type subscribeRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+}
+
+func (h *handler) Subscribe(w http.ResponseWriter, r *http.Request) error {
+	var req subscribeRequest
+	if err := web.DecodeJSON(r, &req); err != nil {
+		return web.NewError(http.StatusBadRequest, "error to read body")
+	}
+
+	subscription, err := h.service.Subscribe(r.Context(), req.URL, req.EventTypes)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	return web.EncodeJSON(w, subscription, http.StatusCreated)
+}
+
+func (h *handler) List(w http.ResponseWriter, r *http.Request) error {
+	subscriptions, err := h.service.List(r.Context())
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	return web.EncodeJSON(w, subscriptions, http.StatusOK)
+}
+
+func (h *handler) Find(w http.ResponseWriter, r *http.Request) error {
+	id, err := web.Params(r).Uint("id")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	subscription, err := h.service.Find(r.Context(), id)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	return web.EncodeJSON(w, subscription, http.StatusOK)
+}
+
+func (h *handler) Unsubscribe(w http.ResponseWriter, r *http.Request) error {
+	id, err := web.Params(r).Uint("id")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.service.Unsubscribe(r.Context(), id); err != nil {
+		return serviceError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *handler) Deliveries(w http.ResponseWriter, r *http.Request) error {
+	id, err := web.Params(r).Uint("id")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	deliveries, err := h.service.Deliveries(r.Context(), id)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	return web.EncodeJSON(w, deliveries, http.StatusOK)
+}
+
+func (h *handler) Replay(w http.ResponseWriter, r *http.Request) error {
+	id, err := web.Params(r).Uint("id")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	deliveryID, err := web.Params(r).Uint("delivery_id")
+	if err != nil {
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	delivery, err := h.service.Replay(r.Context(), id, deliveryID)
+	if err != nil {
+		return serviceError(w, err)
+	}
+
+	return web.EncodeJSON(w, delivery, http.StatusAccepted)
+}
+
+// serviceError maps the webhooks domain errors to web errors.
+func serviceError(w http.ResponseWriter, err error) error {
+	var denied *authz.DeniedError
+	if errors.As(err, &denied) {
+		return authHandler.EncodeDenied(w, denied)
+	}
+
+	switch {
+	case errors.Is(err, webhooks.ErrorSubscriptionNotFound), errors.Is(err, webhooks.ErrorDeliveryNotFound):
+		return web.NewError(http.StatusNotFound, err.Error())
+	case errors.Is(err, webhooks.ErrorInvalidURL),
+		errors.Is(err, webhooks.ErrorPrivateAddress),
+		errors.Is(err, webhooks.ErrorInvalidEventTypes):
+		return web.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	return web.NewError(http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/johan-ag/testing/internal/cards"
+	"github.com/johan-ag/testing/internal/imports"
+	"github.com/johan-ag/testing/internal/jobs"
+	"github.com/johan-ag/testing/internal/platform/database"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/johan-ag/testing/internal/platform/metrics"
+	"github.com/johan-ag/testing/internal/platform/ratelimit"
+	"github.com/johan-ag/testing/internal/platform/schedule"
+	"github.com/johan-ag/testing/internal/platform/tracing"
+	"github.com/johan-ag/testing/internal/users"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"github.com/mercadolibre/fury_go-core/pkg/transport/httpclient"
+	"github.com/mercadolibre/fury_go-toolkit-kvs/pkg/kvs"
+)
+
+const (
+	// serviceName identifies the worker in the traces.
+	serviceName = "testing-worker"
+
+	primaryDSN = "root:root@/testdb?parseTime=true"
+
+	// slowQueryThreshold is the duration over which queries are logged.
+	slowQueryThreshold = 200 * time.Millisecond
+
+	// pollInterval is how often the worker looks for jobs.
+	pollInterval = time.Second
+
+	// defaultCardsSyncSchedule is when the card catalog is synced, CARDS_SYNC_SCHEDULE overrides it.
+	defaultCardsSyncSchedule = "*/30 * * * *"
+
+	// usersReconcileSchedule is when the projection of the users in KVS is reconciled.
+	usersReconcileSchedule = "15 * * * *"
+
+	// rateLimitPurgeSchedule is when the expired rate limit buckets are dropped from KVS.
+	rateLimitPurgeSchedule = "*/10 * * * *"
+)
+
+// main runs the background jobs apart from the api, which must then be started with
+// EMBEDDED_WORKER=false. It stops on SIGINT or SIGTERM once the running jobs return.
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(logging.WithFields(ctx, log.String("job", "jobs_worker"))); err != nil {
+		log.Error(context.Background(), "cannot run worker", log.Err(err))
+	}
+}
+
+func run(ctx context.Context) error {
+	provider := tracing.NewProvider(serviceName, tracing.NewLogExporter())
+	defer provider.Shutdown(context.Background())
+
+	primary, err := sql.Open("mysql", primaryDSN)
+	if err != nil {
+		return err
+	}
+	defer primary.Close()
+
+	if err := metrics.RegisterDB(primary, "testdb"); err != nil {
+		return err
+	}
+
+	queries := database.New(database.NewTracedDB(database.NewInstrumentedDB(primary, slowQueryThreshold)))
+
+	container, err := kvs.NewQueryableClient("container")
+	if err != nil {
+		return err
+	}
+	qkvs := metrics.NewKVSClient("container", tracing.NewKVSClient("container", container))
+
+	usersRepository := users.NewInstrumentedRepository(users.NewRepository(queries))
+	usersProjection := users.NewKVSProjection(qkvs)
+	usersService := users.NewTracedService(users.NewInstrumentedService(users.NewService(users.NewProjectedRepository(usersRepository, usersProjection), usersProjection)))
+
+	jobsRepository := jobs.NewRepository(queries)
+	jobsService := jobs.NewService(jobsRepository)
+
+	importsService := imports.NewService(imports.NewRepository(queries), usersService, jobsService)
+
+	upstream, err := cards.NewUpstream(tracing.NewClient(httpclient.New()))
+	if err != nil {
+		return err
+	}
+	cardsService := cards.NewService(cards.NewRepository(queries), upstream)
+
+	scheduler := schedule.NewScheduler(schedule.NewMySQLLock(queries, instanceID()))
+	if err := scheduler.Add("cards_sync", cardsSyncSchedule(), cards.EnqueueSync(jobsService)); err != nil {
+		return err
+	}
+	if err := scheduler.Add("users_reconcile", usersReconcileSchedule, users.EnqueueReconcile(jobsService)); err != nil {
+		return err
+	}
+	if err := scheduler.Add("ratelimit_purge", rateLimitPurgeSchedule, ratelimit.NewLimiter(ratelimit.NewKVSStore(qkvs)).Purge); err != nil {
+		return err
+	}
+	go scheduler.Run(ctx)
+
+	jobs.NewWorker(jobsRepository, importsService.Job(), cardsService.Job(), users.NewReconcileJob(usersRepository, usersProjection).Job()).Run(ctx, pollInterval)
+	return nil
+}
+
+// cardsSyncSchedule is the cron expression of the catalog sync.
+func cardsSyncSchedule() string {
+	if expr := os.Getenv("CARDS_SYNC_SCHEDULE"); expr != "" {
+		return expr
+	}
+
+	return defaultCardsSyncSchedule
+}
+
+// instanceID identifies this process in the schedule locks.
+func instanceID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
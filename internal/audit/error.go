@@ -0,0 +1,10 @@
+package audit
+
+import (
+	"errors"
+)
+
+var (
+	ErrorMissingEntity = errors.New("entity and id are required")
+	ErrorSavingToDB    = errors.New("error saving audit event to db")
+)
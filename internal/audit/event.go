@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+)
+
+const (
+	ActionCreate   = "create"
+	ActionUpdate   = "update"
+	ActionDelete   = "delete"
+	ActionRestore  = "restore"
+	ActionActivate = "activate"
+)
+
+// anonymous is the actor recorded when the context doesn't carry one.
+const anonymous = "anonymous"
+
+// Change holds the value of a field before and after a mutation.
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Diff maps each changed field, by its json name, to its change.
+type Diff map[string]Change
+
+// NewDiff compares the json representation of before and after and keeps the fields that
+// changed. A nil before or after stands for an entity that didn't exist.
+func NewDiff(before, after interface{}) (Diff, error) {
+	b, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := Diff{}
+	for name, value := range b {
+		if !reflect.DeepEqual(value, a[name]) {
+			diff[name] = Change{Before: value, After: a[name]}
+		}
+	}
+	for name, value := range a {
+		if _, ok := b[name]; !ok {
+			diff[name] = Change{After: value}
+		}
+	}
+
+	return diff, nil
+}
+
+func fields(v interface{}) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil() {
+		return m, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// NewEvent builds the event of action over the entity, taking the actor and the request
+// ID from ctx.
+func NewEvent(ctx context.Context, entity, entityID, action string, before, after interface{}) (Event, error) {
+	diff, err := NewDiff(before, after)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		Actor:     Actor(ctx),
+		RequestID: RequestID(ctx),
+		Diff:      diff,
+	}, nil
+}
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+)
+
+// WithActor returns a copy of ctx carrying who performs the mutations.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor returns the actor carried by ctx, anonymous if there is none.
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+
+	return anonymous
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request that triggers the mutations.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID carried by ctx.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
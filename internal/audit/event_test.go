@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type entity struct {
+	Name string `json:"name"`
+	Age  uint   `json:"age"`
+}
+
+func TestNewDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before interface{}
+		after  interface{}
+		want   Diff
+	}{
+		{
+			name:   "diff of a created entity",
+			before: nil,
+			after:  entity{Name: "name", Age: 30},
+			want: Diff{
+				"name": {After: "name"},
+				"age":  {After: float64(30)},
+			},
+		},
+		{
+			name:   "diff keeps only changed fields",
+			before: entity{Name: "name", Age: 30},
+			after:  entity{Name: "name", Age: 31},
+			want: Diff{
+				"age": {Before: float64(30), After: float64(31)},
+			},
+		},
+		{
+			name:   "diff of an unchanged entity",
+			before: entity{Name: "name", Age: 30},
+			after:  entity{Name: "name", Age: 30},
+			want:   Diff{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got, err := NewDiff(tt.before, tt.after)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	// given
+	ctx := WithRequestID(WithActor(context.Background(), "admin"), "request-id")
+
+	// when
+	event, err := NewEvent(ctx, "user", "1", ActionUpdate, entity{Age: 1}, entity{Age: 2})
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "admin", event.Actor)
+	require.Equal(t, "request-id", event.RequestID)
+	require.Equal(t, Diff{"age": {Before: float64(1), After: float64(2)}}, event.Diff)
+}
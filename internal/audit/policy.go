@@ -0,0 +1,18 @@
+package audit
+
+import (
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+)
+
+// actionList is the action of reading the audit trail of an entity.
+const actionList = "audit:list"
+
+// entityName names the audit trails in the authorization decisions.
+const entityName = "audit_event"
+
+// policy lets only admins read the audit trails, whose diffs carry the data of the
+// entities before and after each change.
+var policy = authz.Policy{
+	actionList: {authz.HasRole(auth.RoleAdmin)},
+}
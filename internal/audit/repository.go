@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/database"
+)
+
+type Repository interface {
+	Save(ctx context.Context, event Event) error
+	List(ctx context.Context, entity, entityID string) ([]Event, error)
+}
+
+type Event struct {
+	ID        uint      `json:"id"`
+	Entity    string    `json:"entity"`
+	EntityID  string    `json:"entity_id"`
+	Action    string    `json:"action"`
+	Actor     string    `json:"actor"`
+	RequestID string    `json:"request_id"`
+	Diff      Diff      `json:"diff"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewRepository returns an audit repository over queries, bind queries to a transaction
+// with database.Queries.ExecTx to save the event along with the change it describes.
+func NewRepository(queries *database.Queries) *repository {
+	return &repository{
+		queries,
+		time.Now,
+	}
+}
+
+type repository struct {
+	queries *database.Queries
+	now     func() time.Time
+}
+
+func (r *repository) Save(ctx context.Context, event Event) error {
+	diff, err := json.Marshal(event.Diff)
+	if err != nil {
+		return err
+	}
+
+	err = r.queries.SaveAuditEvent(ctx, database.SaveAuditEventParams{
+		Entity:    event.Entity,
+		EntityID:  event.EntityID,
+		Action:    event.Action,
+		Actor:     event.Actor,
+		RequestID: event.RequestID,
+		Diff:      diff,
+		CreatedAt: r.now().UTC(),
+	})
+	if err != nil {
+		return ErrorSavingToDB
+	}
+
+	return nil
+}
+
+func (r *repository) List(ctx context.Context, entity, entityID string) ([]Event, error) {
+	rows, err := r.queries.ListAuditEvents(ctx, database.ListAuditEventsParams{
+		Entity:   entity,
+		EntityID: entityID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(rows))
+	for _, e := range rows {
+		var diff Diff
+		if err := json.Unmarshal(e.Diff, &diff); err != nil {
+			return nil, err
+		}
+
+		events = append(events, Event{
+			ID:        uint(e.ID),
+			Entity:    e.Entity,
+			EntityID:  e.EntityID,
+			Action:    e.Action,
+			Actor:     e.Actor,
+			RequestID: e.RequestID,
+			Diff:      diff,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	return events, nil
+}
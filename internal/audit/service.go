@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/johan-ag/testing/internal/platform/authz"
+)
+
+type Service interface {
+	List(ctx context.Context, entity, entityID string) ([]Event, error)
+}
+
+//go:generate mockgen -destination=./mocks.go -package=audit github.com/johan-ag/testing/internal/audit Repository,Service
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) *service {
+	return &service{
+		repository,
+	}
+}
+
+// List returns the audit trail of the entity, oldest event first.
+func (s *service) List(ctx context.Context, entity, entityID string) ([]Event, error) {
+	if err := policy.Authorize(ctx, actionList, authz.Resource{Type: entityName}); err != nil {
+		return nil, err
+	}
+
+	if entity == "" || entityID == "" {
+		return nil, ErrorMissingEntity
+	}
+
+	return s.repository.List(ctx, entity, entityID)
+}
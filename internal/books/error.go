@@ -0,0 +1,11 @@
+package books
+
+import (
+	"errors"
+)
+
+var (
+	ErrorFindLastInsertedID = errors.New("error to find the last inserted id")
+	ErrorSavingToDB         = errors.New("error saving to db")
+	ErrorBookNotFound       = errors.New("book not found")
+)
@@ -0,0 +1,34 @@
+package books
+
+import (
+	"strconv"
+
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+)
+
+// Actions authorized by policy.
+const (
+	actionCreate = "books:create"
+	actionRead   = "books:read"
+)
+
+// policy lets any caller read books, admins create them for anyone and the users create
+// the ones they author.
+var policy = authz.Policy{
+	actionCreate: {authz.HasRole(auth.RoleAdmin), authz.IsOwner()},
+	actionRead:   {authz.Authenticated()},
+}
+
+// resource is the book id as seen by policy, books are owned by their author.
+func resource(id, author uint) authz.Resource {
+	r := authz.Resource{
+		Type:    entityName,
+		OwnerID: strconv.FormatUint(uint64(author), 10),
+	}
+	if id != 0 {
+		r.ID = strconv.FormatUint(uint64(id), 10)
+	}
+
+	return r
+}
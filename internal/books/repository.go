@@ -0,0 +1,92 @@
+package books
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/johan-ag/testing/internal/platform/database"
+)
+
+type Repository interface {
+	Save(ctx context.Context, title string, author uint) (uint, error)
+	Find(ctx context.Context, id uint) (Book, error)
+	ListByAuthors(ctx context.Context, authors []uint) ([]Book, error)
+}
+
+// Book is written by the user whose ID is Author.
+type Book struct {
+	ID     uint   `json:"id"`
+	Title  string `json:"title"`
+	Author uint   `json:"author"`
+}
+
+// entityName names books in the authorization policy.
+const entityName = "book"
+
+func NewRepository(queries *database.Queries) *repository {
+	return &repository{
+		queries,
+	}
+}
+
+type repository struct {
+	queries *database.Queries
+}
+
+func (r *repository) Save(ctx context.Context, title string, author uint) (uint, error) {
+	result, err := r.queries.SaveBook(ctx, database.SaveBookParams{
+		Title:  title,
+		Author: int32(author),
+	})
+	if err != nil {
+		return 0, ErrorSavingToDB
+	}
+
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return 0, ErrorFindLastInsertedID
+	}
+
+	return uint(lastInsertID), nil
+}
+
+func (r *repository) Find(ctx context.Context, id uint) (Book, error) {
+	b, err := r.queries.FindBook(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Book{}, ErrorBookNotFound
+	}
+	if err != nil {
+		return Book{}, err
+	}
+
+	return Book{
+		ID:     uint(b.ID),
+		Title:  b.Title,
+		Author: uint(b.Author),
+	}, nil
+}
+
+// ListByAuthors returns the books written by any of authors, in id order.
+func (r *repository) ListByAuthors(ctx context.Context, authors []uint) ([]Book, error) {
+	keys := make([]int32, len(authors))
+	for i, author := range authors {
+		keys[i] = int32(author)
+	}
+
+	rows, err := r.queries.ListBooksByAuthors(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	books := make([]Book, 0, len(rows))
+	for _, b := range rows {
+		books = append(books, Book{
+			ID:     uint(b.ID),
+			Title:  b.Title,
+			Author: uint(b.Author),
+		})
+	}
+
+	return books, nil
+}
@@ -0,0 +1,63 @@
+package books
+
+import (
+	"context"
+)
+
+type Service interface {
+	Save(ctx context.Context, title string, author uint) (uint, error)
+	Find(ctx context.Context, id uint) (Book, error)
+	ListByAuthors(ctx context.Context, authors []uint) ([]Book, error)
+}
+
+//go:generate mockgen -destination=./mocks.go -package=books github.com/johan-ag/testing/internal/books Repository,Service
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) *service {
+	return &service{
+		repository,
+	}
+}
+
+func (s *service) Save(ctx context.Context, title string, author uint) (uint, error) {
+	if err := policy.Authorize(ctx, actionCreate, resource(0, author)); err != nil {
+		return 0, err
+	}
+
+	return s.repository.Save(ctx, title, author)
+}
+
+func (s *service) Find(ctx context.Context, id uint) (Book, error) {
+	book, err := s.repository.Find(ctx, id)
+	if err != nil {
+		return Book{}, err
+	}
+
+	if err := policy.Authorize(ctx, actionRead, resource(book.ID, book.Author)); err != nil {
+		return Book{}, err
+	}
+
+	return book, nil
+}
+
+// ListByAuthors returns the books written by any of authors at once.
+func (s *service) ListByAuthors(ctx context.Context, authors []uint) ([]Book, error) {
+	if len(authors) == 0 {
+		return nil, nil
+	}
+
+	list, err := s.repository.ListByAuthors(ctx, authors)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, book := range list {
+		if err := policy.Authorize(ctx, actionRead, resource(book.ID, book.Author)); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
@@ -0,0 +1,73 @@
+package books
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceSave(t *testing.T) {
+	tests := []struct {
+		name              string
+		executeBeforeTest func(ctx context.Context, r *MockRepository)
+		principal         auth.Principal
+		author            uint
+		expectedError     error
+	}{
+		{
+			name: "save service test admin",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {
+				r.
+					EXPECT().
+					Save(gomock.Eq(ctx), gomock.Eq("title"), gomock.Eq(uint(2))).
+					Return(uint(1), nil)
+			},
+			principal:     auth.Principal{Subject: "1", Roles: []string{auth.RoleAdmin}},
+			author:        2,
+			expectedError: nil,
+		},
+		{
+			name: "save service test author",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {
+				r.
+					EXPECT().
+					Save(gomock.Eq(ctx), gomock.Eq("title"), gomock.Eq(uint(2))).
+					Return(uint(1), nil)
+			},
+			principal:     auth.Principal{Subject: "2", Roles: []string{auth.RoleUser}},
+			author:        2,
+			expectedError: nil,
+		},
+		{
+			name:              "save service test denied to another author",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {},
+			principal:         auth.Principal{Subject: "3", Roles: []string{auth.RoleUser}},
+			author:            2,
+			expectedError:     authz.ErrorDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx := auth.WithPrincipal(context.Background(), tt.principal)
+			ctrl := gomock.NewController(t)
+			repository := NewMockRepository(ctrl)
+
+			tt.executeBeforeTest(ctx, repository)
+
+			service := NewService(repository)
+
+			// when
+			_, err := service.Save(ctx, "title", tt.author)
+
+			// then
+			require.True(t, errors.Is(err, tt.expectedError))
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package cards
+
+import (
+	"errors"
+)
+
+var (
+	ErrorUpstream      = errors.New("unexpected response from the cards upstream")
+	ErrorNeverSynced   = errors.New("cards were never synced")
+	ErrorSavingToDB    = errors.New("error saving cards to db")
+	ErrorCardNotFound  = errors.New("card not found")
+	ErrorCardNotOwned  = errors.New("card not owned by the user")
+	ErrorOwnerNotFound = errors.New("owner not found")
+
+	ErrorRunInterrupted = errors.New("the sync was interrupted")
+)
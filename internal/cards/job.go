@@ -0,0 +1,40 @@
+package cards
+
+import (
+	"context"
+	"time"
+
+	"github.com/johan-ag/testing/internal/jobs"
+	"github.com/johan-ag/testing/internal/platform/schedule"
+)
+
+// JobType is the type of the jobs that sync the catalog.
+const JobType = "cards.sync"
+
+// syncTimeout bounds a sync, the runs still running after it were interrupted.
+const syncTimeout = time.Minute
+
+// Job is the definition of the sync jobs, one runs at a time per worker.
+func (s *service) Job() jobs.Definition {
+	return jobs.Definition{
+		Type:        JobType,
+		Handler:     s.Handle,
+		Concurrency: 1,
+		Timeout:     syncTimeout,
+		MaxAttempts: 3,
+	}
+}
+
+// Handle syncs the catalog.
+func (s *service) Handle(ctx context.Context, job jobs.Job) error {
+	_, err := s.Sync(ctx)
+	return err
+}
+
+// EnqueueSync is the scheduled work that queues a sync job, the workers run it.
+func EnqueueSync(jobsService jobs.Service) schedule.Func {
+	return func(ctx context.Context) error {
+		_, err := jobsService.Enqueue(ctx, JobType, nil)
+		return err
+	}
+}
@@ -0,0 +1,376 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/johan-ag/testing/internal/cards (interfaces: Repository,Service,Upstream)
+
+// Package cards is a generated GoMock package.
+package cards
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Assign mocks base method.
+func (m *MockRepository) Assign(arg0 context.Context, arg1, arg2 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Assign", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Assign indicates an expected call of Assign.
+func (mr *MockRepositoryMockRecorder) Assign(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Assign", reflect.TypeOf((*MockRepository)(nil).Assign), arg0, arg1, arg2)
+}
+
+// FailStaleRuns mocks base method.
+func (m *MockRepository) FailStaleRuns(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailStaleRuns", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailStaleRuns indicates an expected call of FailStaleRuns.
+func (mr *MockRepositoryMockRecorder) FailStaleRuns(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailStaleRuns", reflect.TypeOf((*MockRepository)(nil).FailStaleRuns), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockRepository) Find(arg0 context.Context, arg1 uint) (Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockRepositoryMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockRepository)(nil).Find), arg0, arg1)
+}
+
+// FinishRun mocks base method.
+func (m *MockRepository) FinishRun(arg0 context.Context, arg1 Run) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishRun", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishRun indicates an expected call of FinishRun.
+func (mr *MockRepositoryMockRecorder) FinishRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishRun", reflect.TypeOf((*MockRepository)(nil).FinishRun), arg0, arg1)
+}
+
+// Hashes mocks base method.
+func (m *MockRepository) Hashes(arg0 context.Context) (map[uint]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hashes", arg0)
+	ret0, _ := ret[0].(map[uint]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Hashes indicates an expected call of Hashes.
+func (mr *MockRepositoryMockRecorder) Hashes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hashes", reflect.TypeOf((*MockRepository)(nil).Hashes), arg0)
+}
+
+// LastPage mocks base method.
+func (m *MockRepository) LastPage(arg0 context.Context) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastPage", arg0)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastPage indicates an expected call of LastPage.
+func (mr *MockRepositoryMockRecorder) LastPage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastPage", reflect.TypeOf((*MockRepository)(nil).LastPage), arg0)
+}
+
+// LastRun mocks base method.
+func (m *MockRepository) LastRun(arg0 context.Context) (Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastRun", arg0)
+	ret0, _ := ret[0].(Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastRun indicates an expected call of LastRun.
+func (mr *MockRepositoryMockRecorder) LastRun(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastRun", reflect.TypeOf((*MockRepository)(nil).LastRun), arg0)
+}
+
+// List mocks base method.
+func (m *MockRepository) List(arg0 context.Context, arg1, arg2 uint) ([]Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRepositoryMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepository)(nil).List), arg0, arg1, arg2)
+}
+
+// ListByOwners mocks base method.
+func (m *MockRepository) ListByOwners(arg0 context.Context, arg1 []uint) (map[uint][]Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByOwners", arg0, arg1)
+	ret0, _ := ret[0].(map[uint][]Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByOwners indicates an expected call of ListByOwners.
+func (mr *MockRepositoryMockRecorder) ListByOwners(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByOwners", reflect.TypeOf((*MockRepository)(nil).ListByOwners), arg0, arg1)
+}
+
+// StartRun mocks base method.
+func (m *MockRepository) StartRun(arg0 context.Context) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartRun", arg0)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartRun indicates an expected call of StartRun.
+func (mr *MockRepositoryMockRecorder) StartRun(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartRun", reflect.TypeOf((*MockRepository)(nil).StartRun), arg0)
+}
+
+// Unassign mocks base method.
+func (m *MockRepository) Unassign(arg0 context.Context, arg1, arg2 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unassign", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unassign indicates an expected call of Unassign.
+func (mr *MockRepositoryMockRecorder) Unassign(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unassign", reflect.TypeOf((*MockRepository)(nil).Unassign), arg0, arg1, arg2)
+}
+
+// Upsert mocks base method.
+func (m *MockRepository) Upsert(arg0 context.Context, arg1 Card) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockRepositoryMockRecorder) Upsert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockRepository)(nil).Upsert), arg0, arg1)
+}
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// Assign mocks base method.
+func (m *MockService) Assign(arg0 context.Context, arg1, arg2 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Assign", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Assign indicates an expected call of Assign.
+func (mr *MockServiceMockRecorder) Assign(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Assign", reflect.TypeOf((*MockService)(nil).Assign), arg0, arg1, arg2)
+}
+
+// Find mocks base method.
+func (m *MockService) Find(arg0 context.Context, arg1 uint) (Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockServiceMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockService)(nil).Find), arg0, arg1)
+}
+
+// LastRun mocks base method.
+func (m *MockService) LastRun(arg0 context.Context) (Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastRun", arg0)
+	ret0, _ := ret[0].(Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastRun indicates an expected call of LastRun.
+func (mr *MockServiceMockRecorder) LastRun(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastRun", reflect.TypeOf((*MockService)(nil).LastRun), arg0)
+}
+
+// List mocks base method.
+func (m *MockService) List(arg0 context.Context, arg1, arg2 uint) ([]Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockServiceMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockService)(nil).List), arg0, arg1, arg2)
+}
+
+// ListByOwners mocks base method.
+func (m *MockService) ListByOwners(arg0 context.Context, arg1 []uint) (map[uint][]Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByOwners", arg0, arg1)
+	ret0, _ := ret[0].(map[uint][]Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByOwners indicates an expected call of ListByOwners.
+func (mr *MockServiceMockRecorder) ListByOwners(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByOwners", reflect.TypeOf((*MockService)(nil).ListByOwners), arg0, arg1)
+}
+
+// Sync mocks base method.
+func (m *MockService) Sync(arg0 context.Context) (Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", arg0)
+	ret0, _ := ret[0].(Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockServiceMockRecorder) Sync(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockService)(nil).Sync), arg0)
+}
+
+// Unassign mocks base method.
+func (m *MockService) Unassign(arg0 context.Context, arg1, arg2 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unassign", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unassign indicates an expected call of Unassign.
+func (mr *MockServiceMockRecorder) Unassign(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unassign", reflect.TypeOf((*MockService)(nil).Unassign), arg0, arg1, arg2)
+}
+
+// MockUpstream is a mock of Upstream interface.
+type MockUpstream struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpstreamMockRecorder
+}
+
+// MockUpstreamMockRecorder is the mock recorder for MockUpstream.
+type MockUpstreamMockRecorder struct {
+	mock *MockUpstream
+}
+
+// NewMockUpstream creates a new mock instance.
+func NewMockUpstream(ctrl *gomock.Controller) *MockUpstream {
+	mock := &MockUpstream{ctrl: ctrl}
+	mock.recorder = &MockUpstreamMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpstream) EXPECT() *MockUpstreamMockRecorder {
+	return m.recorder
+}
+
+// Characters mocks base method.
+func (m *MockUpstream) Characters(arg0 context.Context, arg1 uint) (Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Characters", arg0, arg1)
+	ret0, _ := ret[0].(Page)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Characters indicates an expected call of Characters.
+func (mr *MockUpstreamMockRecorder) Characters(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Characters", reflect.TypeOf((*MockUpstream)(nil).Characters), arg0, arg1)
+}
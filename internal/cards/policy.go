@@ -0,0 +1,34 @@
+package cards
+
+import (
+	"strconv"
+
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+)
+
+// Actions authorized by policy.
+const (
+	actionReadSync = "cards:read_sync"
+	actionList     = "cards:list"
+	actionRead     = "cards:read"
+	actionAssign   = "cards:assign"
+)
+
+// policy lets admins and service accounts follow the catalog sync, anyone authenticated
+// browse the catalog, admins and the users read the cards users own, and admins assign
+// them.
+var policy = authz.Policy{
+	actionReadSync: {authz.HasRole(auth.RoleAdmin), authz.HasRole(auth.RoleService)},
+	actionList:     {authz.Authenticated()},
+	actionRead:     {authz.HasRole(auth.RoleAdmin), authz.IsOwner()},
+	actionAssign:   {authz.HasRole(auth.RoleAdmin)},
+}
+
+// resource is the cards of the user owner as seen by policy.
+func resource(owner uint) authz.Resource {
+	return authz.Resource{
+		Type:    "user_cards",
+		OwnerID: strconv.FormatUint(uint64(owner), 10),
+	}
+}
@@ -1,23 +1,325 @@
-package books
+package cards
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/database"
 )
 
 type Repository interface {
-	Save(title string, author string) error
-	Find(id string) (Book, error)
+	Hashes(ctx context.Context) (map[uint]string, error)
+	Upsert(ctx context.Context, card Card) error
+	StartRun(ctx context.Context) (uint, error)
+	FinishRun(ctx context.Context, run Run) error
+	FailStaleRuns(ctx context.Context, startedBefore time.Time) (int64, error)
+	LastRun(ctx context.Context) (Run, error)
+	LastPage(ctx context.Context) (uint, error)
+	Find(ctx context.Context, id uint) (Card, error)
+	List(ctx context.Context, limit, offset uint) ([]Card, error)
+	Assign(ctx context.Context, owner, id uint) error
+	Unassign(ctx context.Context, owner, id uint) error
+	ListByOwners(ctx context.Context, owners []uint) (map[uint][]Card, error)
+}
+
+// Card is a character of the upstream catalog.
+type Card struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Species string `json:"species"`
+	Gender  string `json:"gender"`
+	Image   string `json:"image"`
+}
+
+// Hash fingerprints the content of the card to tell whether it changed upstream.
+func (c Card) Hash() string {
+	raw, _ := json.Marshal(c)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// Run statuses.
+const (
+	RunRunning   = "running"
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+)
+
+// Run is a sync of the catalog, with how many cards it added and updated and the last
+// upstream page it read.
+type Run struct {
+	ID         uint       `json:"id"`
+	Status     string     `json:"status"`
+	Added      uint       `json:"added"`
+	Updated    uint       `json:"updated"`
+	DurationMs int64      `json:"duration_ms"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	LastPage   uint       `json:"last_page"`
+}
+
+func NewRepository(queries *database.Queries) *repository {
+	return &repository{
+		queries,
+		time.Now,
+	}
+}
+
+type repository struct {
+	queries *database.Queries
+	now     func() time.Time
+}
+
+// Hashes returns the hash of every card by id.
+func (r *repository) Hashes(ctx context.Context) (map[uint]string, error) {
+	rows, err := r.queries.ListCardHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[uint]string, len(rows))
+	for _, row := range rows {
+		hashes[uint(row.ID)] = row.Hash
+	}
+
+	return hashes, nil
+}
+
+// Upsert saves the card, replacing the one with the same id.
+func (r *repository) Upsert(ctx context.Context, card Card) error {
+	now := r.now().UTC()
+
+	err := r.queries.UpsertCard(ctx, database.UpsertCardParams{
+		ID:        int32(card.ID),
+		Name:      card.Name,
+		Status:    card.Status,
+		Species:   card.Species,
+		Gender:    card.Gender,
+		Image:     card.Image,
+		Hash:      card.Hash(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return ErrorSavingToDB
+	}
+
+	return nil
+}
+
+func (r *repository) StartRun(ctx context.Context) (uint, error) {
+	result, err := r.queries.SaveCardSyncRun(ctx, r.now().UTC())
+	if err != nil {
+		return 0, ErrorSavingToDB
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(id), nil
+}
+
+func (r *repository) FinishRun(ctx context.Context, run Run) error {
+	return r.queries.FinishCardSyncRun(ctx, database.FinishCardSyncRunParams{
+		Status:     run.Status,
+		Added:      int32(run.Added),
+		Updated:    int32(run.Updated),
+		DurationMs: run.DurationMs,
+		Error:      truncate(run.Error),
+		FinishedAt: sql.NullTime{Time: r.now().UTC(), Valid: true},
+		LastPage:   int32(run.LastPage),
+		ID:         int32(run.ID),
+	})
+}
+
+// FailStaleRuns closes as failed the runs still running that started before
+// startedBefore, left behind by the syncs that crashed.
+func (r *repository) FailStaleRuns(ctx context.Context, startedBefore time.Time) (int64, error) {
+	result, err := r.queries.FailStaleCardSyncRuns(ctx, database.FailStaleCardSyncRunsParams{
+		Error:      ErrorRunInterrupted.Error(),
+		FinishedAt: sql.NullTime{Time: r.now().UTC(), Valid: true},
+		StartedAt:  startedBefore.UTC(),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// LastRun returns the latest sync run, it fails with ErrorNeverSynced when there is none.
+func (r *repository) LastRun(ctx context.Context) (Run, error) {
+	row, err := r.queries.FindLastCardSyncRun(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Run{}, ErrorNeverSynced
+	}
+	if err != nil {
+		return Run{}, err
+	}
+
+	run := Run{
+		ID:         uint(row.ID),
+		Status:     row.Status,
+		Added:      uint(row.Added),
+		Updated:    uint(row.Updated),
+		DurationMs: row.DurationMs,
+		Error:      row.Error,
+		StartedAt:  row.StartedAt,
+		LastPage:   uint(row.LastPage),
+	}
+
+	if row.FinishedAt.Valid {
+		finishedAt := row.FinishedAt.Time
+		run.FinishedAt = &finishedAt
+	}
+
+	return run, nil
 }
 
-type Book struct {
+// LastPage returns the last upstream page read by the latest sync that succeeded, zero
+// when none did.
+func (r *repository) LastPage(ctx context.Context) (uint, error) {
+	page, err := r.queries.FindLastSucceededCardSyncRunPage(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(page), nil
+}
+
+// Find returns the card of id, it fails with ErrorCardNotFound when the catalog hasn't it.
+func (r *repository) Find(ctx context.Context, id uint) (Card, error) {
+	row, err := r.queries.FindCard(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Card{}, ErrorCardNotFound
+	}
+	if err != nil {
+		return Card{}, err
+	}
+
+	return card(row), nil
+}
+
+// List returns a page of the catalog in id order.
+func (r *repository) List(ctx context.Context, limit, offset uint) ([]Card, error) {
+	rows, err := r.queries.ListCards(ctx, database.ListCardsParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Card, len(rows))
+	for i, row := range rows {
+		list[i] = card(row)
+	}
+
+	return list, nil
 }
 
-type repository struct{}
+// Assign gives the card of id to the user owner, it fails with ErrorOwnerNotFound when the user
+// doesn't exist or is deleted.
+func (r *repository) Assign(ctx context.Context, owner, id uint) error {
+	if _, err := r.queries.FindUser(ctx, int32(owner)); errors.Is(err, sql.ErrNoRows) {
+		return ErrorOwnerNotFound
+	} else if err != nil {
+		return err
+	}
+
+	err := r.queries.AssignCard(ctx, database.AssignCardParams{
+		UserID:    int32(owner),
+		CardID:    int32(id),
+		CreatedAt: r.now().UTC(),
+	})
+	if err != nil {
+		return ErrorSavingToDB
+	}
 
-func (r *repository) Save(ctx context.Context, title string, author string) error {
 	return nil
 }
 
-func (r *repository) Find(id uint) {
+// Unassign takes the card of id from the user owner, it fails with ErrorCardNotOwned when the
+// user hasn't it.
+func (r *repository) Unassign(ctx context.Context, owner, id uint) error {
+	result, err := r.queries.UnassignCard(ctx, database.UnassignCardParams{
+		UserID: int32(owner),
+		CardID: int32(id),
+	})
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrorCardNotOwned
+	}
+
+	return nil
+}
+
+// ListByOwners returns the cards owned by each of owners, the owners without cards are
+// left out.
+func (r *repository) ListByOwners(ctx context.Context, owners []uint) (map[uint][]Card, error) {
+	keys := make([]int32, len(owners))
+	for i, owner := range owners {
+		keys[i] = int32(owner)
+	}
+
+	rows, err := r.queries.ListCardsByOwners(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make(map[uint][]Card, len(owners))
+	for _, row := range rows {
+		owner := uint(row.UserID)
+		cards[owner] = append(cards[owner], Card{
+			ID:      uint(row.ID),
+			Name:    row.Name,
+			Status:  row.Status,
+			Species: row.Species,
+			Gender:  row.Gender,
+			Image:   row.Image,
+		})
+	}
+
+	return cards, nil
+}
+
+func card(row database.Card) Card {
+	return Card{
+		ID:      uint(row.ID),
+		Name:    row.Name,
+		Status:  row.Status,
+		Species: row.Species,
+		Gender:  row.Gender,
+		Image:   row.Image,
+	}
+}
+
+// maxErrorLength is the size of the error column.
+const maxErrorLength = 1000
+
+func truncate(s string) string {
+	if len(s) > maxErrorLength {
+		return s[:maxErrorLength]
+	}
 
+	return s
 }
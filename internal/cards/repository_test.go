@@ -1 +1,18 @@
-package books
+package cards
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardHash(t *testing.T) {
+	card := Card{ID: 1, Name: "Rick Sanchez", Status: "Alive", Species: "Human", Gender: "Male"}
+
+	changed := card
+	changed.Status = "Dead"
+
+	require.Equal(t, card.Hash(), card.Hash())
+	require.NotEqual(t, card.Hash(), changed.Hash())
+	require.Len(t, card.Hash(), 64)
+}
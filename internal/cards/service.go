@@ -1,33 +1,193 @@
-package books
+package cards
 
 import (
 	"context"
-	"fmt"
+	"time"
 
-	"github.com/mercadolibre/fury_go-core/pkg/rusty"
-	"github.com/mercadolibre/fury_go-core/pkg/transport/httpclient"
-)
-
-var (
-	apiEndpoint = "https://rickandmortyapi.com/api"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
 )
 
 type Service interface {
-	Save()
-	Find()
+	Sync(ctx context.Context) (Run, error)
+	LastRun(ctx context.Context) (Run, error)
+	List(ctx context.Context, limit, offset uint) ([]Card, error)
+	Find(ctx context.Context, id uint) (Card, error)
+	Assign(ctx context.Context, owner, id uint) error
+	Unassign(ctx context.Context, owner, id uint) error
+	ListByOwners(ctx context.Context, owners []uint) (map[uint][]Card, error)
 }
 
+// maxListLimit caps the page size of List.
+const maxListLimit = 100
+
+//go:generate mockgen -destination=./mocks.go -package=cards github.com/johan-ag/testing/internal/cards Repository,Service,Upstream
 type service struct {
+	repository Repository
+	upstream   Upstream
+	now        func() time.Time
+}
+
+func NewService(repository Repository, upstream Upstream) *service {
+	return &service{
+		repository,
+		upstream,
+		time.Now,
+	}
 }
 
-func (s *service) Save() {
-	endpoint, err := rusty.NewEndpoint(httpclient.New(), apiEndpoint)
+// Sync walks the upstream characters from the last page read by the last sync that
+// succeeded, and saves the ones that are new or changed, the unchanged ones aren't
+// written. The upstream lists the characters by id, so that the ones added since are on
+// that page or after it. The runs left running by the syncs that crashed are closed as
+// failed first, and this run is recorded whatever its outcome.
+func (s *service) Sync(ctx context.Context) (Run, error) {
+	start := s.now()
+
+	stale, err := s.repository.FailStaleRuns(ctx, start.Add(-syncTimeout))
 	if err != nil {
-		fmt.Println(err)
+		return Run{}, err
+	}
+	if stale > 0 {
+		logging.Warn(ctx, "cards sync runs interrupted", log.Int64("count", stale))
+	}
+
+	id, err := s.repository.StartRun(ctx)
+	if err != nil {
+		return Run{}, err
+	}
+
+	run := Run{ID: id, Status: RunSucceeded, StartedAt: start}
+	syncErr := s.sync(ctx, &run)
+	if syncErr != nil {
+		run.Status, run.Error = RunFailed, syncErr.Error()
+	}
+	run.DurationMs = s.now().Sub(start).Milliseconds()
+
+	if err := s.repository.FinishRun(ctx, run); err != nil {
+		return Run{}, err
 	}
-	endpoint.Get(context.Background())
+
+	fields := []log.Field{log.Uint("added", run.Added), log.Uint("updated", run.Updated), log.Uint("last_page", run.LastPage), log.Int64("duration_ms", run.DurationMs)}
+	if syncErr != nil {
+		logging.Error(ctx, "cards sync failed", append(fields, log.Err(syncErr))...)
+		return run, syncErr
+	}
+
+	logging.Info(ctx, "cards synced", fields...)
+	return run, nil
 }
 
-func (s *service) Find() {
+func (s *service) sync(ctx context.Context, run *Run) error {
+	page, err := s.repository.LastPage(ctx)
+	if err != nil {
+		return err
+	}
+	if page == 0 {
+		page = 1
+	}
+
+	hashes, err := s.repository.Hashes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for ; ; page++ {
+		p, err := s.upstream.Characters(ctx, page)
+		if err != nil {
+			return err
+		}
+
+		for _, card := range p.Cards {
+			hash, known := hashes[card.ID]
+			if known && hash == card.Hash() {
+				continue
+			}
+
+			if err := s.repository.Upsert(ctx, card); err != nil {
+				return err
+			}
+
+			if known {
+				run.Updated++
+			} else {
+				run.Added++
+			}
+		}
+
+		run.LastPage = page
+		if !p.Next {
+			return nil
+		}
+	}
+}
+
+// LastRun returns the status of the latest sync.
+func (s *service) LastRun(ctx context.Context) (Run, error) {
+	if err := policy.Authorize(ctx, actionReadSync, authz.Resource{Type: "card_sync_run"}); err != nil {
+		return Run{}, err
+	}
+
+	return s.repository.LastRun(ctx)
+}
+
+// List returns a page of the catalog in id order.
+func (s *service) List(ctx context.Context, limit, offset uint) ([]Card, error) {
+	if err := policy.Authorize(ctx, actionList, authz.Resource{Type: "card"}); err != nil {
+		return nil, err
+	}
+
+	if limit == 0 || limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	return s.repository.List(ctx, limit, offset)
+}
+
+// Find returns the card of id from the catalog.
+func (s *service) Find(ctx context.Context, id uint) (Card, error) {
+	if err := policy.Authorize(ctx, actionList, authz.Resource{Type: "card"}); err != nil {
+		return Card{}, err
+	}
+
+	return s.repository.Find(ctx, id)
+}
+
+// Assign gives the card of id to the user owner, assigning it again changes nothing.
+func (s *service) Assign(ctx context.Context, owner, id uint) error {
+	if err := policy.Authorize(ctx, actionAssign, resource(owner)); err != nil {
+		return err
+	}
+
+	if _, err := s.repository.Find(ctx, id); err != nil {
+		return err
+	}
+
+	return s.repository.Assign(ctx, owner, id)
+}
+
+// Unassign takes the card of id from the user owner.
+func (s *service) Unassign(ctx context.Context, owner, id uint) error {
+	if err := policy.Authorize(ctx, actionAssign, resource(owner)); err != nil {
+		return err
+	}
+
+	return s.repository.Unassign(ctx, owner, id)
+}
+
+// ListByOwners returns the cards owned by each of owners at once. The caller must be
+// allowed to read the cards of every one of them.
+func (s *service) ListByOwners(ctx context.Context, owners []uint) (map[uint][]Card, error) {
+	for _, owner := range owners {
+		if err := policy.Authorize(ctx, actionRead, resource(owner)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(owners) == 0 {
+		return map[uint][]Card{}, nil
+	}
 
+	return s.repository.ListByOwners(ctx, owners)
 }
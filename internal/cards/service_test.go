@@ -1 +1,152 @@
-package books
+package cards
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceSync(t *testing.T) {
+	rick := Card{ID: 1, Name: "Rick Sanchez", Status: "Alive"}
+	morty := Card{ID: 2, Name: "Morty Smith", Status: "Alive"}
+	summer := Card{ID: 3, Name: "Summer Smith", Status: "Alive"}
+	failure := errors.New("upstream down")
+
+	tests := []struct {
+		name              string
+		executeBeforeTest func(r *MockRepository, u *MockUpstream)
+		expectedRun       Run
+		expectedError     error
+	}{
+		{
+			name: "saves the new and changed cards only",
+			executeBeforeTest: func(r *MockRepository, u *MockUpstream) {
+				stale := morty
+				stale.Status = "Dead"
+
+				r.EXPECT().FailStaleRuns(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+				r.EXPECT().StartRun(gomock.Any()).Return(uint(5), nil)
+				r.EXPECT().LastPage(gomock.Any()).Return(uint(0), nil)
+				r.EXPECT().Hashes(gomock.Any()).Return(map[uint]string{1: rick.Hash(), 2: stale.Hash()}, nil)
+				u.EXPECT().Characters(gomock.Any(), uint(1)).Return(Page{Cards: []Card{rick, morty}, Next: true}, nil)
+				u.EXPECT().Characters(gomock.Any(), uint(2)).Return(Page{Cards: []Card{summer}}, nil)
+				r.EXPECT().Upsert(gomock.Any(), morty).Return(nil)
+				r.EXPECT().Upsert(gomock.Any(), summer).Return(nil)
+				r.EXPECT().FinishRun(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedRun: Run{ID: 5, Status: RunSucceeded, Added: 1, Updated: 1, LastPage: 2},
+		},
+		{
+			name: "resumes from the last page of the last sync and closes the interrupted runs",
+			executeBeforeTest: func(r *MockRepository, u *MockUpstream) {
+				r.EXPECT().FailStaleRuns(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+				r.EXPECT().StartRun(gomock.Any()).Return(uint(7), nil)
+				r.EXPECT().LastPage(gomock.Any()).Return(uint(2), nil)
+				r.EXPECT().Hashes(gomock.Any()).Return(map[uint]string{1: rick.Hash(), 2: morty.Hash()}, nil)
+				u.EXPECT().Characters(gomock.Any(), uint(2)).Return(Page{Cards: []Card{summer}}, nil)
+				r.EXPECT().Upsert(gomock.Any(), summer).Return(nil)
+				r.EXPECT().FinishRun(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedRun: Run{ID: 7, Status: RunSucceeded, Added: 1, LastPage: 2},
+		},
+		{
+			name: "records the failed runs",
+			executeBeforeTest: func(r *MockRepository, u *MockUpstream) {
+				r.EXPECT().FailStaleRuns(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+				r.EXPECT().StartRun(gomock.Any()).Return(uint(6), nil)
+				r.EXPECT().LastPage(gomock.Any()).Return(uint(0), nil)
+				r.EXPECT().Hashes(gomock.Any()).Return(map[uint]string{}, nil)
+				u.EXPECT().Characters(gomock.Any(), uint(1)).Return(Page{Cards: []Card{rick}, Next: true}, nil)
+				r.EXPECT().Upsert(gomock.Any(), rick).Return(nil)
+				u.EXPECT().Characters(gomock.Any(), uint(2)).Return(Page{}, failure)
+				r.EXPECT().FinishRun(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedRun:   Run{ID: 6, Status: RunFailed, Added: 1, Error: "upstream down", LastPage: 1},
+			expectedError: failure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			r := NewMockRepository(ctrl)
+			u := NewMockUpstream(ctrl)
+			tt.executeBeforeTest(r, u)
+			s := NewService(r, u)
+
+			// when
+			run, err := s.Sync(context.Background())
+
+			// then
+			require.ErrorIs(t, err, tt.expectedError)
+			run.StartedAt, run.DurationMs = tt.expectedRun.StartedAt, 0
+			require.Equal(t, tt.expectedRun, run)
+		})
+	}
+}
+
+func TestServiceAssign(t *testing.T) {
+	adminCtx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "100", Roles: []string{auth.RoleAdmin}})
+	ownerCtx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "1", Roles: []string{auth.RoleUser}})
+	var denied *authz.DeniedError
+
+	tests := []struct {
+		name              string
+		ctx               context.Context
+		executeBeforeTest func(r *MockRepository)
+		expectedError     error
+		expectedDenied    bool
+	}{
+		{
+			name: "admins assign the cards of the catalog",
+			ctx:  adminCtx,
+			executeBeforeTest: func(r *MockRepository) {
+				r.EXPECT().Find(gomock.Any(), uint(5)).Return(Card{ID: 5}, nil)
+				r.EXPECT().Assign(gomock.Any(), uint(1), uint(5)).Return(nil)
+			},
+		},
+		{
+			name: "cards out of the catalog aren't assigned",
+			ctx:  adminCtx,
+			executeBeforeTest: func(r *MockRepository) {
+				r.EXPECT().Find(gomock.Any(), uint(5)).Return(Card{}, ErrorCardNotFound)
+			},
+			expectedError: ErrorCardNotFound,
+		},
+		{
+			name:           "users don't assign cards to themselves",
+			ctx:            ownerCtx,
+			expectedDenied: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			r := NewMockRepository(ctrl)
+			if tt.executeBeforeTest != nil {
+				tt.executeBeforeTest(r)
+			}
+			s := NewService(r, NewMockUpstream(ctrl))
+
+			// when
+			err := s.Assign(tt.ctx, 1, 5)
+
+			// then
+			if tt.expectedDenied {
+				require.ErrorAs(t, err, &denied)
+				return
+			}
+			require.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package cards
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mercadolibre/fury_go-core/pkg/rusty"
+)
+
+var (
+	apiEndpoint = "https://rickandmortyapi.com/api"
+)
+
+// Upstream is the api the catalog is synced from.
+type Upstream interface {
+	// Characters returns a page of the characters, the first page is 1.
+	Characters(ctx context.Context, page uint) (Page, error)
+}
+
+// Page is a page of characters, Next tells whether another one follows.
+type Page struct {
+	Cards []Card
+	Next  bool
+}
+
+// NewUpstream returns the Rick and Morty api client, requests are sent through requester.
+func NewUpstream(requester rusty.Requester) (*upstream, error) {
+	endpoint, err := rusty.NewEndpoint(requester, apiEndpoint+"/character")
+	if err != nil {
+		return nil, err
+	}
+
+	return &upstream{
+		endpoint,
+	}, nil
+}
+
+type upstream struct {
+	endpoint *rusty.Endpoint
+}
+
+type charactersResponse struct {
+	Info struct {
+		Next *string `json:"next"`
+	} `json:"info"`
+	Results []Card `json:"results"`
+}
+
+func (u *upstream) Characters(ctx context.Context, page uint) (Page, error) {
+	response, err := u.endpoint.Get(ctx, rusty.WithQuery("page", strconv.FormatUint(uint64(page), 10)))
+	if err != nil {
+		return Page{}, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return Page{}, fmt.Errorf("%w: status %d", ErrorUpstream, response.StatusCode)
+	}
+
+	var body charactersResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		return Page{}, fmt.Errorf("%w: %v", ErrorUpstream, err)
+	}
+
+	return Page{
+		Cards: body.Results,
+		Next:  body.Info.Next != nil,
+	}, nil
+}
@@ -0,0 +1,18 @@
+package imports
+
+import (
+	"errors"
+)
+
+var (
+	ErrorImportNotFound    = errors.New("user import not found")
+	ErrorImportRunning     = errors.New("user import hasn't finished yet")
+	ErrorImportInterrupted = errors.New("user import was interrupted, the users saved so far are kept")
+	ErrorInvalidFormat     = errors.New("import format must be csv or ndjson")
+	ErrorMissingColumns    = errors.New("csv header must have name and age columns")
+	ErrorMalformedRow      = errors.New("malformed row")
+	ErrorInvalidAge        = errors.New("age must be a non negative integer")
+	ErrorSavingToDB        = errors.New("error saving import to db")
+	ErrorUploadTooLarge    = errors.New("upload is too large")
+	ErrorUploadNotFound    = errors.New("user import upload not found")
+)
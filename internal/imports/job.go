@@ -0,0 +1,95 @@
+package imports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/johan-ag/testing/internal/jobs"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+// JobType is the type of the jobs that run the imports.
+const JobType = "users.import"
+
+// jobPayload locates the stored upload of an import.
+type jobPayload struct {
+	ImportID uint   `json:"import_id"`
+	Format   string `json:"format"`
+	Size     int64  `json:"size"`
+}
+
+// Job is the definition of the import jobs. A retried job doesn't import its upload again,
+// the rows saved by the previous attempt would be duplicated.
+func (s *service) Job() jobs.Definition {
+	return jobs.Definition{
+		Type:        JobType,
+		Handler:     s.Handle,
+		Concurrency: 2,
+		Timeout:     time.Minute,
+		MaxAttempts: 2,
+	}
+}
+
+// Handle runs the import of job. A pending import is run, one left running by a previous
+// attempt is marked as interrupted and a finished one keeps its outcome. The imports that
+// fail are recorded as failed, the job is only retried when that can't be recorded.
+func (s *service) Handle(ctx context.Context, job jobs.Job) error {
+	var payload jobPayload
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+
+	i, err := s.repository.Find(ctx, payload.ImportID)
+	if err != nil {
+		return err
+	}
+	defer s.discard(ctx, i.ID)
+
+	switch i.Status {
+	case StatusCompleted, StatusFailed:
+		return nil
+	case StatusRunning:
+		logging.Warn(ctx, "user import interrupted", log.Uint("import_id", i.ID))
+		return s.repository.Finish(ctx, i.ID, StatusFailed, i.Progress, ErrorImportInterrupted.Error(), "")
+	}
+
+	upload := &partReader{
+		ctx:        ctx,
+		repository: s.repository,
+		id:         i.ID,
+		parts:      uint((payload.Size + uploadPartSize - 1) / uploadPartSize),
+	}
+
+	return s.run(ctx, i.ID, payload.Format, upload, payload.Size)
+}
+
+// partReader reads the parts of the upload of an import in order.
+type partReader struct {
+	ctx        context.Context
+	repository Repository
+	id         uint
+	parts      uint
+	next       uint
+	content    []byte
+}
+
+func (r *partReader) Read(p []byte) (int, error) {
+	for len(r.content) == 0 {
+		if r.next == r.parts {
+			return 0, io.EOF
+		}
+
+		content, err := r.repository.FindPart(r.ctx, r.id, r.next)
+		if err != nil {
+			return 0, err
+		}
+		r.content = content
+		r.next++
+	}
+
+	n := copy(p, r.content)
+	r.content = r.content[n:]
+	return n, nil
+}
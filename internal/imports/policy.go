@@ -0,0 +1,33 @@
+package imports
+
+import (
+	"strconv"
+
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+)
+
+// Actions authorized by policy.
+const (
+	actionCreate = "user_imports:create"
+	actionRead   = "user_imports:read"
+)
+
+// entityName names user imports in the authorization decisions.
+const entityName = "user_import"
+
+// policy lets the principals that can create users import them, and read the imports they
+// started. Admins read every import.
+var policy = authz.Policy{
+	actionCreate: {authz.HasRole(auth.RoleAdmin), authz.HasRole(auth.RoleService)},
+	actionRead:   {authz.HasRole(auth.RoleAdmin), authz.IsOwner()},
+}
+
+// resource is the import as seen by policy, it is owned by the principal that started it.
+func resource(i Import) authz.Resource {
+	return authz.Resource{
+		Type:    entityName,
+		ID:      strconv.FormatUint(uint64(i.ID), 10),
+		OwnerID: i.CreatedBy,
+	}
+}
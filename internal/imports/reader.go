@@ -0,0 +1,154 @@
+package imports
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/johan-ag/testing/internal/users"
+)
+
+// Upload formats.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// maxLineSize caps the length of the lines of NDJSON uploads.
+const maxLineSize = 1 << 20
+
+// Row is the user read from a line of an upload, Err tells why it can't be imported.
+type Row struct {
+	Line int
+	User users.NewUser
+	Err  error
+}
+
+type rowReader interface {
+	// Next returns the next row or io.EOF after the last one, any other error means the
+	// upload can't be read any further.
+	Next() (Row, error)
+}
+
+// newRowReader reads the rows of r in format. CSV uploads start with a header naming the
+// name and age columns, NDJSON ones have a user object per line.
+func newRowReader(format string, r io.Reader) (rowReader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		return &ndjsonReader{scanner, 0}, nil
+	}
+
+	return nil, ErrorInvalidFormat
+}
+
+type csvReader struct {
+	reader *csv.Reader
+	name   int
+	age    int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrorMissingColumns
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	name, hasName := columns["name"]
+	age, hasAge := columns["age"]
+	if !hasName || !hasAge {
+		return nil, ErrorMissingColumns
+	}
+
+	return &csvReader{reader, name, age}, nil
+}
+
+func (r *csvReader) Next() (Row, error) {
+	record, err := r.reader.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return Row{Line: parseErr.StartLine, Err: fmt.Errorf("%w: %v", ErrorMalformedRow, parseErr.Err)}, nil
+	}
+	if err != nil {
+		return Row{}, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	row := Row{Line: line}
+
+	if len(record) <= r.name || len(record) <= r.age {
+		row.Err = fmt.Errorf("%w: expected %d fields, got %d", ErrorMalformedRow, maxInt(r.name, r.age)+1, len(record))
+		return row, nil
+	}
+
+	age, err := strconv.ParseUint(strings.TrimSpace(record[r.age]), 10, 32)
+	if err != nil {
+		row.Err = ErrorInvalidAge
+		return row, nil
+	}
+
+	row.User = users.NewUser{
+		Name: strings.TrimSpace(record[r.name]),
+		Age:  uint(age),
+	}
+
+	return row, nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *ndjsonReader) Next() (Row, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var user users.NewUser
+		if err := json.Unmarshal(line, &user); err != nil {
+			return Row{Line: r.line, Err: fmt.Errorf("%w: %v", ErrorMalformedRow, err)}, nil
+		}
+
+		return Row{Line: r.line, User: user}, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return Row{}, err
+	}
+
+	return Row{}, io.EOF
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,88 @@
+package imports
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/johan-ag/testing/internal/users"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowReader(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        string
+		upload        string
+		expectedRows  []Row
+		expectedError error
+	}{
+		{
+			name:   "csv with columns in any order",
+			format: FormatCSV,
+			upload: "Age, Name\n30,ana\n\nx,bob\n40\n",
+			expectedRows: []Row{
+				{Line: 2, User: users.NewUser{Name: "ana", Age: 30}},
+				{Line: 4, Err: ErrorInvalidAge},
+				{Line: 5, Err: ErrorMalformedRow},
+			},
+		},
+		{
+			name:          "csv without the age column",
+			format:        FormatCSV,
+			upload:        "name\nana\n",
+			expectedError: ErrorMissingColumns,
+		},
+		{
+			name:   "ndjson skips blank lines",
+			format: FormatNDJSON,
+			upload: "{\"name\":\"ana\",\"age\":30}\n\n{\"name\":\"bob\",\"age\":-1}\n{\"name\":\"eve\",\"age\":20,\"random\":\"X\"}",
+			expectedRows: []Row{
+				{Line: 1, User: users.NewUser{Name: "ana", Age: 30}},
+				{Line: 3, Err: ErrorMalformedRow},
+				{Line: 4, User: users.NewUser{Name: "eve", Age: 20}},
+			},
+		},
+		{
+			name:          "unknown format",
+			format:        "xml",
+			expectedError: ErrorInvalidFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reader, err := newRowReader(tt.format, strings.NewReader(tt.upload))
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			// when
+			var rows []Row
+			for {
+				row, err := reader.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				require.NoError(t, err)
+				rows = append(rows, row)
+			}
+
+			// then
+			require.Len(t, rows, len(tt.expectedRows))
+			for i, expected := range tt.expectedRows {
+				require.Equal(t, expected.Line, rows[i].Line)
+				require.Equal(t, expected.User, rows[i].User)
+				if expected.Err == nil {
+					require.NoError(t, rows[i].Err)
+				} else {
+					require.ErrorIs(t, rows[i].Err, expected.Err)
+				}
+			}
+		})
+	}
+}
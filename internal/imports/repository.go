@@ -0,0 +1,185 @@
+package imports
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/database"
+)
+
+type Repository interface {
+	Save(ctx context.Context, format string, createdBy string) (uint, error)
+	Find(ctx context.Context, id uint) (Import, error)
+	UpdateProgress(ctx context.Context, id uint, progress Progress) error
+	Finish(ctx context.Context, id uint, status string, progress Progress, failure string, report string) error
+	SavePart(ctx context.Context, id uint, part uint, content []byte) error
+	FindPart(ctx context.Context, id uint, part uint) ([]byte, error)
+	DeleteParts(ctx context.Context, id uint) error
+}
+
+// Import statuses.
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// Progress counts the rows of an import read so far, Percent is the share of the upload read.
+type Progress struct {
+	ProcessedRows uint `json:"processed_rows"`
+	FailedRows    uint `json:"failed_rows"`
+	Percent       uint `json:"percent"`
+}
+
+// Import is a bulk load of users from an uploaded file. The error report lists the rows
+// that couldn't be imported, it is complete once the import finished.
+type Import struct {
+	ID          uint       `json:"id"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	CreatedBy   string     `json:"created_by"`
+	Progress    Progress   `json:"progress"`
+	Error       string     `json:"error,omitempty"`
+	ErrorReport string     `json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// Finished reports whether the import completed or failed.
+func (i Import) Finished() bool {
+	return i.Status == StatusCompleted || i.Status == StatusFailed
+}
+
+func NewRepository(queries *database.Queries) *repository {
+	return &repository{
+		queries,
+		time.Now,
+	}
+}
+
+type repository struct {
+	queries *database.Queries
+	now     func() time.Time
+}
+
+func (r *repository) Save(ctx context.Context, format string, createdBy string) (uint, error) {
+	now := r.now().UTC()
+
+	result, err := r.queries.SaveUserImport(ctx, database.SaveUserImportParams{
+		Format:    format,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return 0, ErrorSavingToDB
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(id), nil
+}
+
+func (r *repository) Find(ctx context.Context, id uint) (Import, error) {
+	i, err := r.queries.FindUserImport(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Import{}, ErrorImportNotFound
+	}
+	if err != nil {
+		return Import{}, err
+	}
+
+	return toImport(i), nil
+}
+
+// UpdateProgress marks the import as running with the given progress.
+func (r *repository) UpdateProgress(ctx context.Context, id uint, progress Progress) error {
+	return r.queries.UpdateUserImportProgress(ctx, database.UpdateUserImportProgressParams{
+		ProcessedRows: int32(progress.ProcessedRows),
+		FailedRows:    int32(progress.FailedRows),
+		Progress:      int32(progress.Percent),
+		UpdatedAt:     r.now().UTC(),
+		ID:            int32(id),
+	})
+}
+
+// Finish records the final status and progress of the import along with its error report,
+// failure tells why a failed import stopped.
+func (r *repository) Finish(ctx context.Context, id uint, status string, progress Progress, failure string, report string) error {
+	now := r.now().UTC()
+
+	return r.queries.FinishUserImport(ctx, database.FinishUserImportParams{
+		Status:        status,
+		ProcessedRows: int32(progress.ProcessedRows),
+		FailedRows:    int32(progress.FailedRows),
+		Progress:      int32(progress.Percent),
+		Error:         failure,
+		ErrorReport:   report,
+		UpdatedAt:     now,
+		FinishedAt:    sql.NullTime{Time: now, Valid: true},
+		ID:            int32(id),
+	})
+}
+
+// SavePart stores the part-th part of the upload of the import.
+func (r *repository) SavePart(ctx context.Context, id uint, part uint, content []byte) error {
+	err := r.queries.SaveUserImportPart(ctx, database.SaveUserImportPartParams{
+		ImportID: int32(id),
+		Part:     int32(part),
+		Content:  content,
+	})
+	if err != nil {
+		return ErrorSavingToDB
+	}
+
+	return nil
+}
+
+func (r *repository) FindPart(ctx context.Context, id uint, part uint) ([]byte, error) {
+	content, err := r.queries.FindUserImportPart(ctx, database.FindUserImportPartParams{
+		ImportID: int32(id),
+		Part:     int32(part),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrorUploadNotFound
+	}
+
+	return content, err
+}
+
+// DeleteParts drops the upload of the import, once it ran it isn't read again.
+func (r *repository) DeleteParts(ctx context.Context, id uint) error {
+	return r.queries.DeleteUserImportParts(ctx, int32(id))
+}
+
+func toImport(i database.UserImport) Import {
+	imp := Import{
+		ID:        uint(i.ID),
+		Format:    i.Format,
+		Status:    i.Status,
+		CreatedBy: i.CreatedBy,
+		Progress: Progress{
+			ProcessedRows: uint(i.ProcessedRows),
+			FailedRows:    uint(i.FailedRows),
+			Percent:       uint(i.Progress),
+		},
+		Error:       i.Error,
+		ErrorReport: i.ErrorReport,
+		CreatedAt:   i.CreatedAt,
+		UpdatedAt:   i.UpdatedAt,
+	}
+
+	if i.FinishedAt.Valid {
+		finishedAt := i.FinishedAt.Time
+		imp.FinishedAt = &finishedAt
+	}
+
+	return imp
+}
@@ -0,0 +1,297 @@
+package imports
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/johan-ag/testing/internal/jobs"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/johan-ag/testing/internal/users"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+type Service interface {
+	Start(ctx context.Context, format string, upload io.Reader) (Import, error)
+	Find(ctx context.Context, id uint) (Import, error)
+	Report(ctx context.Context, id uint) (string, error)
+}
+
+const (
+	// MaxUploadSize caps the bytes of an upload.
+	MaxUploadSize = 100 << 20
+
+	// chunkSize is the number of rows saved at a time, the progress is updated after each chunk.
+	chunkSize = 100
+
+	// maxReportRows caps the rows listed by the error report, the rest are only counted.
+	maxReportRows = 10000
+
+	// uploadPartSize is the size of the parts the uploads are stored in.
+	uploadPartSize = 1 << 20
+)
+
+//go:generate mockgen -destination=./mocks.go -package=imports github.com/johan-ag/testing/internal/imports Repository,Service
+type service struct {
+	repository Repository
+	users      users.Service
+	jobs       jobs.Service
+}
+
+// NewService returns an imports service that stores the uploads in the database and saves
+// the users through usersService from the jobs it enqueues.
+func NewService(repository Repository, usersService users.Service, jobsService jobs.Service) *service {
+	return &service{
+		repository,
+		usersService,
+		jobsService,
+	}
+}
+
+// Start stores the upload and enqueues the job that imports it, the import is returned
+// pending so that its progress can be polled with Find.
+func (s *service) Start(ctx context.Context, format string, upload io.Reader) (Import, error) {
+	if err := policy.Authorize(ctx, actionCreate, authz.Resource{Type: entityName}); err != nil {
+		return Import{}, err
+	}
+
+	if format != FormatCSV && format != FormatNDJSON {
+		return Import{}, ErrorInvalidFormat
+	}
+
+	principal, _ := auth.PrincipalFrom(ctx)
+	id, err := s.repository.Save(ctx, format, principal.Subject)
+	if err != nil {
+		return Import{}, err
+	}
+
+	size, err := s.store(ctx, id, upload)
+	if err == nil {
+		_, err = s.jobs.Enqueue(ctx, JobType, jobPayload{id, format, size})
+	}
+	if err != nil {
+		if err := s.repository.Finish(ctx, id, StatusFailed, Progress{}, err.Error(), ""); err != nil {
+			logging.Error(ctx, "cannot finish user import", log.Uint("import_id", id), log.Err(err))
+		}
+		s.discard(ctx, id)
+		return Import{}, err
+	}
+
+	logging.Info(ctx, "user import started", log.Uint("import_id", id), log.Int64("bytes", size))
+	return s.repository.Find(ctx, id)
+}
+
+func (s *service) Find(ctx context.Context, id uint) (Import, error) {
+	i, err := s.repository.Find(ctx, id)
+	if err != nil {
+		return Import{}, err
+	}
+
+	if err := policy.Authorize(ctx, actionRead, resource(i)); err != nil {
+		return Import{}, err
+	}
+
+	return i, nil
+}
+
+// Report returns the error report of the import as CSV with the line and error of each
+// row that couldn't be imported.
+func (s *service) Report(ctx context.Context, id uint) (string, error) {
+	i, err := s.Find(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if !i.Finished() {
+		return "", ErrorImportRunning
+	}
+
+	return i.ErrorReport, nil
+}
+
+// store saves upload in parts of uploadPartSize bytes and returns its size.
+func (s *service) store(ctx context.Context, id uint, upload io.Reader) (int64, error) {
+	limited := io.LimitReader(upload, MaxUploadSize+1)
+	buffer := make([]byte, uploadPartSize)
+	var size int64
+
+	for part := uint(0); ; part++ {
+		n, err := io.ReadFull(limited, buffer)
+		if errors.Is(err, io.EOF) {
+			return size, nil
+		}
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, err
+		}
+
+		size += int64(n)
+		if size > MaxUploadSize {
+			return 0, ErrorUploadTooLarge
+		}
+
+		if err := s.repository.SavePart(ctx, id, part, buffer[:n]); err != nil {
+			return 0, err
+		}
+		if n < len(buffer) {
+			return size, nil
+		}
+	}
+}
+
+// discard drops the upload of the import, the failures are only logged since the import
+// doesn't read it again.
+func (s *service) discard(ctx context.Context, id uint) {
+	if err := s.repository.DeleteParts(ctx, id); err != nil {
+		logging.Warn(ctx, "cannot delete user import upload", log.Uint("import_id", id), log.Err(err))
+	}
+}
+
+// run imports the rows of upload in chunks, recording the progress after each one. The
+// rows that can't be read or saved are added to the error report instead of stopping
+// the import, which only fails when the upload or the database can't be read at all. The
+// failure is recorded on the import, the error returned is the one recording it.
+func (s *service) run(ctx context.Context, id uint, format string, upload io.Reader, size int64) error {
+	counter := &countingReader{reader: upload}
+	report := newReport()
+	var progress Progress
+
+	if err := s.repository.UpdateProgress(ctx, id, progress); err != nil {
+		return err
+	}
+
+	flush := func(chunk []Row) error {
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		batch := make([]users.NewUser, len(chunk))
+		for i, row := range chunk {
+			batch[i] = row.User
+		}
+
+		results, err := s.users.SaveBatch(ctx, batch, users.BatchBestEffort)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			progress.ProcessedRows++
+			if result.Error != "" {
+				progress.FailedRows++
+				report.add(chunk[result.Index].Line, result.Error)
+			}
+		}
+
+		return nil
+	}
+
+	runErr := func() error {
+		reader, err := newRowReader(format, counter)
+		if err != nil {
+			return err
+		}
+
+		chunk := make([]Row, 0, chunkSize)
+		for {
+			row, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				return flush(chunk)
+			}
+			if err != nil {
+				return err
+			}
+
+			if row.Err != nil {
+				progress.ProcessedRows++
+				progress.FailedRows++
+				report.add(row.Line, row.Err.Error())
+				continue
+			}
+
+			chunk = append(chunk, row)
+			if len(chunk) < chunkSize {
+				continue
+			}
+
+			if err := flush(chunk); err != nil {
+				return err
+			}
+			chunk = chunk[:0]
+
+			if size > 0 {
+				progress.Percent = uint(counter.read * 100 / size)
+			}
+			if err := s.repository.UpdateProgress(ctx, id, progress); err != nil {
+				logging.Warn(ctx, "cannot update user import progress", log.Err(err))
+			}
+		}
+	}()
+
+	status, failure := StatusCompleted, ""
+	if runErr != nil {
+		status, failure = StatusFailed, runErr.Error()
+		logging.Error(ctx, "user import failed", log.Err(runErr))
+	} else {
+		progress.Percent = 100
+	}
+
+	if err := s.repository.Finish(ctx, id, status, progress, failure, report.String()); err != nil {
+		return err
+	}
+
+	logging.Info(ctx, "user import finished", log.String("status", status),
+		log.Uint("processed_rows", progress.ProcessedRows), log.Uint("failed_rows", progress.FailedRows))
+	return nil
+}
+
+// report is the CSV listing the rows that couldn't be imported.
+type report struct {
+	buffer  bytes.Buffer
+	writer  *csv.Writer
+	rows    int
+	omitted int
+}
+
+func newReport() *report {
+	r := &report{}
+	r.writer = csv.NewWriter(&r.buffer)
+	_ = r.writer.Write([]string{"line", "error"})
+
+	return r
+}
+
+func (r *report) add(line int, reason string) {
+	if r.rows == maxReportRows {
+		r.omitted++
+		return
+	}
+
+	r.rows++
+	_ = r.writer.Write([]string{strconv.Itoa(line), reason})
+}
+
+func (r *report) String() string {
+	if r.omitted > 0 {
+		_ = r.writer.Write([]string{"", strconv.Itoa(r.omitted) + " more rows failed"})
+	}
+	r.writer.Flush()
+
+	return r.buffer.String()
+}
+
+// countingReader counts the bytes read from reader.
+type countingReader struct {
+	reader io.Reader
+	read   int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	return n, err
+}
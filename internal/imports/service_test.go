@@ -0,0 +1,320 @@
+package imports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johan-ag/testing/internal/jobs"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/johan-ag/testing/internal/users"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	serviceCtx = auth.WithPrincipal(context.Background(), auth.Principal{Subject: "svc", Roles: []string{auth.RoleService}})
+	otherCtx   = auth.WithPrincipal(context.Background(), auth.Principal{Subject: "1", Roles: []string{auth.RoleUser}})
+
+	errDBDown = errors.New("db down")
+)
+
+func TestServiceRun(t *testing.T) {
+	upload := "name,age\nana,30\nbob,x\n,20\n"
+
+	tests := []struct {
+		name              string
+		executeBeforeTest func(r *MockRepository, u *users.MockService)
+		expectedError     error
+	}{
+		{
+			name: "reports the rows that can't be read or saved",
+			executeBeforeTest: func(r *MockRepository, u *users.MockService) {
+				r.
+					EXPECT().
+					UpdateProgress(gomock.Any(), uint(3), Progress{}).
+					Return(nil)
+				u.
+					EXPECT().
+					SaveBatch(gomock.Any(), []users.NewUser{{Name: "ana", Age: 30}, {Name: "", Age: 20}}, users.BatchBestEffort).
+					Return([]users.BatchResult{{Index: 0, ID: 7}, {Index: 1, Error: users.ErrorInvalidName.Error()}}, nil)
+				r.
+					EXPECT().
+					Finish(gomock.Any(), uint(3), StatusCompleted, Progress{ProcessedRows: 3, FailedRows: 2, Percent: 100}, "",
+						"line,error\n3,age must be a non negative integer\n4,name must have between 1 and 50 characters\n").
+					Return(nil)
+			},
+		},
+		{
+			name: "records the failure when the users can't be saved",
+			executeBeforeTest: func(r *MockRepository, u *users.MockService) {
+				r.
+					EXPECT().
+					UpdateProgress(gomock.Any(), uint(3), Progress{}).
+					Return(nil)
+				u.
+					EXPECT().
+					SaveBatch(gomock.Any(), gomock.Any(), users.BatchBestEffort).
+					Return(nil, errDBDown)
+				r.
+					EXPECT().
+					Finish(gomock.Any(), uint(3), StatusFailed, Progress{ProcessedRows: 1, FailedRows: 1}, "db down", "line,error\n3,age must be a non negative integer\n").
+					Return(nil)
+			},
+		},
+		{
+			name: "fails when the failure can't be recorded",
+			executeBeforeTest: func(r *MockRepository, u *users.MockService) {
+				r.
+					EXPECT().
+					UpdateProgress(gomock.Any(), uint(3), Progress{}).
+					Return(nil)
+				u.
+					EXPECT().
+					SaveBatch(gomock.Any(), gomock.Any(), users.BatchBestEffort).
+					Return(nil, errDBDown)
+				r.
+					EXPECT().
+					Finish(gomock.Any(), uint(3), StatusFailed, gomock.Any(), "db down", gomock.Any()).
+					Return(errDBDown)
+			},
+			expectedError: errDBDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			r := NewMockRepository(ctrl)
+			u := users.NewMockService(ctrl)
+			tt.executeBeforeTest(r, u)
+			s := NewService(r, u, nil)
+
+			// when
+			err := s.run(serviceCtx, 3, FormatCSV, strings.NewReader(upload), int64(len(upload)))
+
+			// then
+			require.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestServiceReport(t *testing.T) {
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		status         string
+		expectedReport string
+		expectedError  error
+	}{
+		{
+			name:           "report of a finished import",
+			ctx:            serviceCtx,
+			status:         StatusCompleted,
+			expectedReport: "line,error\n",
+		},
+		{
+			name:          "import still running",
+			ctx:           serviceCtx,
+			status:        StatusRunning,
+			expectedError: ErrorImportRunning,
+		},
+		{
+			name:          "import of someone else",
+			ctx:           otherCtx,
+			status:        StatusCompleted,
+			expectedError: authz.ErrorDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			r := NewMockRepository(ctrl)
+			r.
+				EXPECT().
+				Find(gomock.Any(), uint(3)).
+				Return(Import{ID: 3, Status: tt.status, CreatedBy: "svc", ErrorReport: "line,error\n"}, nil)
+			s := NewService(r, nil, nil)
+
+			// when
+			report, err := s.Report(tt.ctx, 3)
+
+			// then
+			require.ErrorIs(t, err, tt.expectedError)
+			require.Equal(t, tt.expectedReport, report)
+		})
+	}
+}
+
+func TestServiceStart(t *testing.T) {
+	upload := "name,age\nana,30\n"
+
+	tests := []struct {
+		name              string
+		executeBeforeTest func(r *MockRepository, j *jobs.MockService)
+		expectedError     error
+	}{
+		{
+			name: "stores the upload and enqueues its job",
+			executeBeforeTest: func(r *MockRepository, j *jobs.MockService) {
+				r.
+					EXPECT().
+					Save(gomock.Any(), FormatCSV, "svc").
+					Return(uint(3), nil)
+				r.
+					EXPECT().
+					SavePart(gomock.Any(), uint(3), uint(0), []byte(upload)).
+					Return(nil)
+				j.
+					EXPECT().
+					Enqueue(gomock.Any(), JobType, jobPayload{3, FormatCSV, int64(len(upload))}).
+					Return(jobs.Job{}, nil)
+				r.
+					EXPECT().
+					Find(gomock.Any(), uint(3)).
+					Return(Import{ID: 3, Status: StatusPending}, nil)
+			},
+		},
+		{
+			name: "fails the import when its job can't be enqueued",
+			executeBeforeTest: func(r *MockRepository, j *jobs.MockService) {
+				r.
+					EXPECT().
+					Save(gomock.Any(), FormatCSV, "svc").
+					Return(uint(3), nil)
+				r.
+					EXPECT().
+					SavePart(gomock.Any(), uint(3), uint(0), []byte(upload)).
+					Return(nil)
+				j.
+					EXPECT().
+					Enqueue(gomock.Any(), JobType, gomock.Any()).
+					Return(jobs.Job{}, errDBDown)
+				r.
+					EXPECT().
+					Finish(gomock.Any(), uint(3), StatusFailed, Progress{}, "db down", "").
+					Return(nil)
+				r.
+					EXPECT().
+					DeleteParts(gomock.Any(), uint(3)).
+					Return(nil)
+			},
+			expectedError: errDBDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			r := NewMockRepository(ctrl)
+			j := jobs.NewMockService(ctrl)
+			tt.executeBeforeTest(r, j)
+			s := NewService(r, nil, j)
+
+			// when
+			_, err := s.Start(serviceCtx, FormatCSV, strings.NewReader(upload))
+
+			// then
+			require.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestServiceHandle(t *testing.T) {
+	upload := "name,age\nana,30\n"
+
+	tests := []struct {
+		name              string
+		executeBeforeTest func(r *MockRepository, u *users.MockService)
+		expectedError     error
+	}{
+		{
+			name: "runs a pending import from its stored upload",
+			executeBeforeTest: func(r *MockRepository, u *users.MockService) {
+				r.
+					EXPECT().
+					Find(gomock.Any(), uint(3)).
+					Return(Import{ID: 3, Status: StatusPending}, nil)
+				r.
+					EXPECT().
+					FindPart(gomock.Any(), uint(3), uint(0)).
+					Return([]byte(upload), nil)
+				r.
+					EXPECT().
+					UpdateProgress(gomock.Any(), uint(3), Progress{}).
+					Return(nil)
+				u.
+					EXPECT().
+					SaveBatch(gomock.Any(), []users.NewUser{{Name: "ana", Age: 30}}, users.BatchBestEffort).
+					Return([]users.BatchResult{{Index: 0, ID: 7}}, nil)
+				r.
+					EXPECT().
+					Finish(gomock.Any(), uint(3), StatusCompleted, Progress{ProcessedRows: 1, Percent: 100}, "", "line,error\n").
+					Return(nil)
+				r.
+					EXPECT().
+					DeleteParts(gomock.Any(), uint(3)).
+					Return(nil)
+			},
+		},
+		{
+			name: "keeps a failed import without retrying it",
+			executeBeforeTest: func(r *MockRepository, u *users.MockService) {
+				r.
+					EXPECT().
+					Find(gomock.Any(), uint(3)).
+					Return(Import{ID: 3, Status: StatusFailed, Error: "db down"}, nil)
+				r.
+					EXPECT().
+					DeleteParts(gomock.Any(), uint(3)).
+					Return(nil)
+			},
+		},
+		{
+			name: "fails an import interrupted by a previous attempt",
+			executeBeforeTest: func(r *MockRepository, u *users.MockService) {
+				r.
+					EXPECT().
+					Find(gomock.Any(), uint(3)).
+					Return(Import{ID: 3, Status: StatusRunning}, nil)
+				r.
+					EXPECT().
+					Finish(gomock.Any(), uint(3), StatusFailed, Progress{}, ErrorImportInterrupted.Error(), "").
+					Return(nil)
+				r.
+					EXPECT().
+					DeleteParts(gomock.Any(), uint(3)).
+					Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			r := NewMockRepository(ctrl)
+			u := users.NewMockService(ctrl)
+			tt.executeBeforeTest(r, u)
+			s := NewService(r, u, nil)
+			job := jobs.Job{Payload: json.RawMessage(`{"import_id":3,"format":"csv","size":16}`)}
+
+			// when
+			err := s.Handle(serviceCtx, job)
+
+			// then
+			require.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
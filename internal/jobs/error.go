@@ -0,0 +1,13 @@
+package jobs
+
+import (
+	"errors"
+)
+
+var (
+	ErrorJobNotFound  = errors.New("job not found")
+	ErrorLockLost     = errors.New("job lock lost to another worker")
+	ErrorLockExpired  = errors.New("job lock expired on its last attempt")
+	ErrorHandlerPanic = errors.New("job handler panicked")
+	ErrorSavingToDB   = errors.New("error saving job to db")
+)
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"strconv"
+
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+)
+
+// actionRead is the action of reading the status of a job.
+const actionRead = "jobs:read"
+
+// entityName names jobs in the authorization decisions.
+const entityName = "job"
+
+// policy lets admins read every job and the other principals the jobs they enqueued.
+var policy = authz.Policy{
+	actionRead: {authz.HasRole(auth.RoleAdmin), authz.IsOwner()},
+}
+
+func resource(job Job) authz.Resource {
+	return authz.Resource{
+		Type:    entityName,
+		ID:      strconv.FormatUint(uint64(job.ID), 10),
+		OwnerID: job.Principal.Subject,
+	}
+}
@@ -0,0 +1,281 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/database"
+)
+
+type Repository interface {
+	Save(ctx context.Context, job Job) (uint, error)
+	Find(ctx context.Context, id uint) (Job, error)
+	Claim(ctx context.Context, jobType string, limit uint, now time.Time, lockedUntil time.Time) ([]Job, error)
+	Extend(ctx context.Context, id uint, attempts uint, lockedUntil time.Time) error
+	Complete(ctx context.Context, id uint, attempts uint) error
+	Retry(ctx context.Context, id uint, attempts uint, lastError string, runAt time.Time) error
+	Fail(ctx context.Context, id uint, attempts uint, lastError string) error
+}
+
+// Job statuses.
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Job is a unit of background work of a given type. It runs on behalf of the principal
+// that enqueued it, Attempts counts its claims and fences the updates of the worker
+// running it.
+type Job struct {
+	ID          uint            `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"-"`
+	Status      string          `json:"status"`
+	Attempts    uint            `json:"attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	Principal   auth.Principal  `json:"-"`
+	RequestID   string          `json:"request_id,omitempty"`
+	RunAt       time.Time       `json:"run_at"`
+	LockedUntil *time.Time      `json:"-"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+}
+
+// Decode decodes the json payload of the job into v.
+func (j Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+func NewRepository(queries *database.Queries) *repository {
+	return &repository{
+		queries,
+		time.Now,
+	}
+}
+
+type repository struct {
+	queries *database.Queries
+	now     func() time.Time
+}
+
+func (r *repository) Save(ctx context.Context, job Job) (uint, error) {
+	principal, err := json.Marshal(job.Principal)
+	if err != nil {
+		return 0, err
+	}
+
+	now := r.now().UTC()
+	runAt := job.RunAt.UTC()
+	if job.RunAt.IsZero() {
+		runAt = now
+	}
+
+	result, err := r.queries.SaveJob(ctx, database.SaveJobParams{
+		Type:      job.Type,
+		Payload:   job.Payload,
+		Principal: principal,
+		RequestID: job.RequestID,
+		RunAt:     runAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return 0, ErrorSavingToDB
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(id), nil
+}
+
+func (r *repository) Find(ctx context.Context, id uint) (Job, error) {
+	j, err := r.queries.FindJob(ctx, int64(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Job{}, ErrorJobNotFound
+	}
+	if err != nil {
+		return Job{}, err
+	}
+
+	return toJob(j)
+}
+
+// Claim locks up to limit jobs of jobType until lockedUntil, they are the queued jobs due
+// by now and the running ones whose lock expired because their worker is gone. The rows
+// locked by other workers are skipped so that each job is claimed by a single worker.
+func (r *repository) Claim(ctx context.Context, jobType string, limit uint, now time.Time, lockedUntil time.Time) ([]Job, error) {
+	now, lockedUntil = now.UTC(), lockedUntil.UTC()
+	var claimed []Job
+
+	err := r.queries.ExecTx(ctx, func(q *database.Queries) error {
+		rows, err := q.ListClaimableJobs(ctx, database.ListClaimableJobsParams{
+			Type:        jobType,
+			RunAt:       now,
+			LockedUntil: sql.NullTime{Time: now, Valid: true},
+			Limit:       int32(limit),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			job, err := toJob(row)
+			if err != nil {
+				return err
+			}
+
+			job.Status = StatusRunning
+			job.Attempts++
+			job.LockedUntil = &lockedUntil
+			job.UpdatedAt = now
+
+			if err := q.ClaimJob(ctx, database.ClaimJobParams{
+				Attempts:    int32(job.Attempts),
+				LockedUntil: sql.NullTime{Time: lockedUntil, Valid: true},
+				UpdatedAt:   now,
+				ID:          int64(job.ID),
+			}); err != nil {
+				return err
+			}
+			claimed = append(claimed, job)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// Extend keeps the job locked until lockedUntil, it fails with ErrorLockLost when the
+// attempt was claimed again by another worker.
+func (r *repository) Extend(ctx context.Context, id uint, attempts uint, lockedUntil time.Time) error {
+	result, err := r.queries.ExtendJobLock(ctx, database.ExtendJobLockParams{
+		LockedUntil: sql.NullTime{Time: lockedUntil.UTC(), Valid: true},
+		UpdatedAt:   r.now().UTC(),
+		ID:          int64(id),
+		Attempts:    int32(attempts),
+	})
+	if err != nil {
+		return err
+	}
+
+	return expectAttempt(result)
+}
+
+func (r *repository) Complete(ctx context.Context, id uint, attempts uint) error {
+	now := r.now().UTC()
+
+	result, err := r.queries.CompleteJob(ctx, database.CompleteJobParams{
+		UpdatedAt:  now,
+		FinishedAt: sql.NullTime{Time: now, Valid: true},
+		ID:         int64(id),
+		Attempts:   int32(attempts),
+	})
+	if err != nil {
+		return err
+	}
+
+	return expectAttempt(result)
+}
+
+// Retry queues the job again to run at runAt.
+func (r *repository) Retry(ctx context.Context, id uint, attempts uint, lastError string, runAt time.Time) error {
+	result, err := r.queries.RetryJob(ctx, database.RetryJobParams{
+		LastError: truncate(lastError),
+		RunAt:     runAt.UTC(),
+		UpdatedAt: r.now().UTC(),
+		ID:        int64(id),
+		Attempts:  int32(attempts),
+	})
+	if err != nil {
+		return err
+	}
+
+	return expectAttempt(result)
+}
+
+func (r *repository) Fail(ctx context.Context, id uint, attempts uint, lastError string) error {
+	now := r.now().UTC()
+
+	result, err := r.queries.FailJob(ctx, database.FailJobParams{
+		LastError:  truncate(lastError),
+		UpdatedAt:  now,
+		FinishedAt: sql.NullTime{Time: now, Valid: true},
+		ID:         int64(id),
+		Attempts:   int32(attempts),
+	})
+	if err != nil {
+		return err
+	}
+
+	return expectAttempt(result)
+}
+
+// expectAttempt reports ErrorLockLost when the update didn't match the running attempt.
+func expectAttempt(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrorLockLost
+	}
+
+	return nil
+}
+
+// maxErrorLength is the size of the last_error column.
+const maxErrorLength = 1000
+
+func truncate(s string) string {
+	if len(s) > maxErrorLength {
+		return s[:maxErrorLength]
+	}
+
+	return s
+}
+
+func toJob(j database.Job) (Job, error) {
+	job := Job{
+		ID:        uint(j.ID),
+		Type:      j.Type,
+		Payload:   j.Payload,
+		Status:    j.Status,
+		Attempts:  uint(j.Attempts),
+		LastError: j.LastError,
+		RequestID: j.RequestID,
+		RunAt:     j.RunAt,
+		CreatedAt: j.CreatedAt,
+		UpdatedAt: j.UpdatedAt,
+	}
+
+	if err := json.Unmarshal(j.Principal, &job.Principal); err != nil {
+		return Job{}, err
+	}
+
+	if j.LockedUntil.Valid {
+		lockedUntil := j.LockedUntil.Time
+		job.LockedUntil = &lockedUntil
+	}
+
+	if j.FinishedAt.Valid {
+		finishedAt := j.FinishedAt.Time
+		job.FinishedAt = &finishedAt
+	}
+
+	return job, nil
+}
@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/johan-ag/testing/internal/audit"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+type Service interface {
+	Enqueue(ctx context.Context, jobType string, payload interface{}) (Job, error)
+	Find(ctx context.Context, id uint) (Job, error)
+}
+
+//go:generate mockgen -destination=./mocks.go -package=jobs github.com/johan-ag/testing/internal/jobs Repository,Service
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) *service {
+	return &service{
+		repository,
+	}
+}
+
+// Enqueue queues a job of jobType with payload encoded as json. The job runs on behalf of
+// the principal and under the request id of ctx, callers authorize the work beforehand.
+func (s *service) Enqueue(ctx context.Context, jobType string, payload interface{}) (Job, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return Job{}, err
+	}
+
+	principal, _ := auth.PrincipalFrom(ctx)
+	id, err := s.repository.Save(ctx, Job{
+		Type:      jobType,
+		Payload:   raw,
+		Principal: principal,
+		RequestID: audit.RequestID(ctx),
+	})
+	if err != nil {
+		return Job{}, err
+	}
+
+	logging.Info(ctx, "job enqueued", log.Uint("job_id", id), log.String("job_type", jobType))
+	return s.repository.Find(ctx, id)
+}
+
+func (s *service) Find(ctx context.Context, id uint) (Job, error) {
+	job, err := s.repository.Find(ctx, id)
+	if err != nil {
+		return Job{}, err
+	}
+
+	if err := policy.Authorize(ctx, actionRead, resource(job)); err != nil {
+		return Job{}, err
+	}
+
+	return job, nil
+}
@@ -0,0 +1,233 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/johan-ag/testing/internal/audit"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/johan-ag/testing/internal/platform/tracing"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+// Handler runs a job, its context is canceled when the worker stops or loses the job.
+type Handler func(ctx context.Context, job Job) error
+
+// Definition tells a worker how to run the jobs of a type.
+type Definition struct {
+	Type    string
+	Handler Handler
+	// Concurrency caps the jobs of the type a worker runs at once.
+	Concurrency uint
+	// Timeout is how long a claimed job stays invisible to the other workers, the worker
+	// running it extends the lock while the handler runs so it only expires when the
+	// worker is gone.
+	Timeout time.Duration
+	// MaxAttempts is how many times a failing job runs before it is marked failed.
+	MaxAttempts uint
+}
+
+const (
+	defaultConcurrency = 1
+	defaultTimeout     = 5 * time.Minute
+	defaultMaxAttempts = 5
+
+	baseBackoff = time.Second
+	maxBackoff  = 10 * time.Minute
+)
+
+// Worker claims and runs the jobs of the types it is defined for, retrying the failed ones
+// with exponential backoff.
+type Worker struct {
+	repository  Repository
+	definitions []Definition
+	now         func() time.Time
+}
+
+func NewWorker(repository Repository, definitions ...Definition) *Worker {
+	for i, d := range definitions {
+		if d.Concurrency == 0 {
+			definitions[i].Concurrency = defaultConcurrency
+		}
+		if d.Timeout == 0 {
+			definitions[i].Timeout = defaultTimeout
+		}
+		if d.MaxAttempts == 0 {
+			definitions[i].MaxAttempts = defaultMaxAttempts
+		}
+	}
+
+	return &Worker{
+		repository,
+		definitions,
+		time.Now,
+	}
+}
+
+// Run polls for jobs of every type once per interval until ctx is done, then waits for
+// the jobs it is running to stop.
+func (w *Worker) Run(ctx context.Context, interval time.Duration) {
+	var wg sync.WaitGroup
+	for _, d := range w.definitions {
+		wg.Add(1)
+		go func(d Definition) {
+			defer wg.Done()
+			w.poll(ctx, d, interval)
+		}(d)
+	}
+
+	wg.Wait()
+}
+
+// poll claims jobs of d as its concurrency allows and runs each in its own goroutine.
+func (w *Worker) poll(ctx context.Context, d Definition, interval time.Duration) {
+	slots := make(chan struct{}, d.Concurrency)
+	var running sync.WaitGroup
+	defer running.Wait()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			free := cap(slots) - len(slots)
+			if free == 0 {
+				continue
+			}
+
+			now := w.now()
+			claimed, err := w.repository.Claim(ctx, d.Type, uint(free), now, now.Add(d.Timeout))
+			if err != nil {
+				logging.Error(ctx, "cannot claim jobs", log.String("job_type", d.Type), log.Err(err))
+				continue
+			}
+
+			for _, job := range claimed {
+				slots <- struct{}{}
+				running.Add(1)
+				go func(job Job) {
+					defer running.Done()
+					defer func() { <-slots }()
+					w.execute(ctx, d, job)
+				}(job)
+			}
+		}
+	}
+}
+
+// execute runs a claimed job and records its outcome. The bookkeeping doesn't use ctx so
+// that a job interrupted by the worker stopping is still queued again.
+func (w *Worker) execute(ctx context.Context, d Definition, job Job) {
+	jobCtx := jobContext(job)
+
+	if job.Attempts > d.MaxAttempts {
+		w.finish(jobCtx, d, job, ErrorLockExpired)
+		return
+	}
+
+	runCtx, cancel := context.WithCancel(jobCtx)
+	defer cancel()
+	go w.heartbeat(runCtx, cancel, ctx, d, job)
+
+	runCtx, span := tracing.Start(runCtx, "jobs/"+d.Type)
+	err := run(runCtx, d.Handler, job)
+	tracing.End(span, err)
+	cancel()
+
+	w.finish(jobCtx, d, job, err)
+}
+
+// heartbeat extends the lock of job until runCtx is done, it cancels the run when the
+// worker stops or the lock is lost.
+func (w *Worker) heartbeat(runCtx context.Context, cancel context.CancelFunc, workerCtx context.Context, d Definition, job Job) {
+	ticker := time.NewTicker(d.Timeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-runCtx.Done():
+			return
+		case <-workerCtx.Done():
+			cancel()
+			return
+		case <-ticker.C:
+			err := w.repository.Extend(runCtx, job.ID, job.Attempts, w.now().Add(d.Timeout))
+			if errors.Is(err, ErrorLockLost) {
+				logging.Warn(runCtx, "job lock lost")
+				cancel()
+				return
+			}
+			if err != nil {
+				logging.Warn(runCtx, "cannot extend job lock", log.Err(err))
+			}
+		}
+	}
+}
+
+// finish marks job as succeeded when err is nil, or else queues it again after a backoff
+// until it runs out of attempts.
+func (w *Worker) finish(ctx context.Context, d Definition, job Job, err error) {
+	var finishErr error
+	switch {
+	case err == nil:
+		finishErr = w.repository.Complete(ctx, job.ID, job.Attempts)
+	case job.Attempts >= d.MaxAttempts:
+		logging.Warn(ctx, "job failed", log.Uint("attempts", job.Attempts), log.Err(err))
+		finishErr = w.repository.Fail(ctx, job.ID, job.Attempts, err.Error())
+	default:
+		logging.Info(ctx, "job will be retried", log.Uint("attempts", job.Attempts), log.Err(err))
+		finishErr = w.repository.Retry(ctx, job.ID, job.Attempts, err.Error(), w.now().Add(Backoff(job.Attempts)))
+	}
+
+	if finishErr != nil {
+		logging.Error(ctx, "cannot record job outcome", log.Err(finishErr))
+	}
+}
+
+// run calls handler turning its panics into errors.
+func run(ctx context.Context, handler Handler, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrorHandlerPanic, r)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+// jobContext is the context jobs run in, it carries the principal and request id of the
+// request that enqueued the job so its work is authorized and audited as that request.
+func jobContext(job Job) context.Context {
+	ctx := context.Background()
+	if job.Principal.Subject != "" {
+		ctx = auth.WithPrincipal(ctx, job.Principal)
+		ctx = audit.WithActor(ctx, job.Principal.Subject)
+	}
+	ctx = audit.WithRequestID(ctx, job.RequestID)
+
+	return logging.WithFields(ctx,
+		log.String(logging.RequestIDField, job.RequestID),
+		log.String("job_type", job.Type),
+		log.Uint("job_id", job.ID),
+	)
+}
+
+// Backoff doubles the wait after each failed attempt, up to maxBackoff.
+func Backoff(attempts uint) time.Duration {
+	wait := baseBackoff
+	for i := uint(1); i < attempts; i++ {
+		wait *= 2
+		if wait >= maxBackoff {
+			return maxBackoff
+		}
+	}
+
+	return wait
+}
@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerExecute(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	failure := errors.New("boom")
+
+	tests := []struct {
+		name              string
+		attempts          uint
+		handler           Handler
+		executeBeforeTest func(r *MockRepository)
+		expectedRun       bool
+	}{
+		{
+			name:     "completes the job",
+			attempts: 1,
+			handler:  func(ctx context.Context, job Job) error { return nil },
+			executeBeforeTest: func(r *MockRepository) {
+				r.EXPECT().Complete(gomock.Any(), uint(7), uint(1)).Return(nil)
+			},
+			expectedRun: true,
+		},
+		{
+			name:     "retries with backoff",
+			attempts: 2,
+			handler:  func(ctx context.Context, job Job) error { return failure },
+			executeBeforeTest: func(r *MockRepository) {
+				r.EXPECT().Retry(gomock.Any(), uint(7), uint(2), "boom", now.Add(2*time.Second)).Return(nil)
+			},
+			expectedRun: true,
+		},
+		{
+			name:     "fails on the last attempt",
+			attempts: 3,
+			handler:  func(ctx context.Context, job Job) error { return failure },
+			executeBeforeTest: func(r *MockRepository) {
+				r.EXPECT().Fail(gomock.Any(), uint(7), uint(3), "boom").Return(nil)
+			},
+			expectedRun: true,
+		},
+		{
+			name:     "retries panicking handlers",
+			attempts: 1,
+			handler:  func(ctx context.Context, job Job) error { panic("oops") },
+			executeBeforeTest: func(r *MockRepository) {
+				r.EXPECT().Retry(gomock.Any(), uint(7), uint(1), "job handler panicked: oops", now.Add(time.Second)).Return(nil)
+			},
+			expectedRun: true,
+		},
+		{
+			name:     "fails without running when the lock expired on the last attempt",
+			attempts: 4,
+			handler:  func(ctx context.Context, job Job) error { return nil },
+			executeBeforeTest: func(r *MockRepository) {
+				r.EXPECT().Fail(gomock.Any(), uint(7), uint(4), ErrorLockExpired.Error()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			r := NewMockRepository(ctrl)
+			tt.executeBeforeTest(r)
+
+			ran := false
+			var principal auth.Principal
+			d := Definition{
+				Type: "test",
+				Handler: func(ctx context.Context, job Job) error {
+					ran = true
+					principal, _ = auth.PrincipalFrom(ctx)
+					return tt.handler(ctx, job)
+				},
+				MaxAttempts: 3,
+			}
+			w := NewWorker(r, d)
+			w.now = func() time.Time { return now }
+			job := Job{ID: 7, Type: "test", Attempts: tt.attempts, Principal: auth.Principal{Subject: "svc"}}
+
+			// when
+			w.execute(context.Background(), w.definitions[0], job)
+
+			// then
+			require.Equal(t, tt.expectedRun, ran)
+			if tt.expectedRun {
+				require.Equal(t, "svc", principal.Subject)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, time.Second, Backoff(1))
+	require.Equal(t, 8*time.Second, Backoff(4))
+	require.Equal(t, maxBackoff, Backoff(30))
+}
@@ -0,0 +1,10 @@
+package outbox
+
+import (
+	"errors"
+)
+
+var (
+	ErrorSavingToDB       = errors.New("error saving outbox event to db")
+	ErrorUnexpectedStatus = errors.New("unexpected webhook response status")
+)
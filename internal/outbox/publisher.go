@@ -0,0 +1,101 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+// Publisher delivers the outbox events to the downstream consumers.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// NewLogPublisher returns a publisher that writes the events to the application log.
+func NewLogPublisher() *logPublisher {
+	return &logPublisher{}
+}
+
+type logPublisher struct{}
+
+func (p *logPublisher) Publish(ctx context.Context, event Event) error {
+	logging.Info(ctx, "domain event published",
+		log.Uint("event_id", event.ID),
+		log.String("event_type", event.Type),
+		log.String("aggregate_type", event.AggregateType),
+		log.String("aggregate_id", event.AggregateID),
+		log.String("payload", string(event.Payload)),
+	)
+
+	return nil
+}
+
+// Doer sends http requests, *http.Client satisfies it.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// NewWebhookPublisher returns a publisher that posts each event as json to url. The
+// X-Event-ID header lets the receiver drop the duplicates of an at-least-once delivery.
+func NewWebhookPublisher(url string, client Doer) *webhookPublisher {
+	return &webhookPublisher{
+		url,
+		client,
+	}
+}
+
+type webhookPublisher struct {
+	url    string
+	client Doer
+}
+
+func (p *webhookPublisher) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatUint(uint64(event.ID), 10))
+	req.Header.Set("X-Event-Type", event.Type)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %d", ErrorUnexpectedStatus, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// NewMultiPublisher returns a publisher that hands each event to every publisher in order.
+// The event is retried on all of them when any fails, consumers get it at least once.
+func NewMultiPublisher(publishers ...Publisher) multiPublisher {
+	return publishers
+}
+
+type multiPublisher []Publisher
+
+func (p multiPublisher) Publish(ctx context.Context, event Event) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
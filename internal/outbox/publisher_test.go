@@ -0,0 +1,56 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookPublisherPublish(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		expectedError error
+	}{
+		{
+			name:          "webhook publish successful",
+			status:        http.StatusAccepted,
+			expectedError: nil,
+		},
+		{
+			name:          "webhook publish unexpected status",
+			status:        http.StatusInternalServerError,
+			expectedError: ErrorUnexpectedStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var received *http.Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			event, err := NewEvent("UserCreated", "user", "1", map[string]string{"name": "name"})
+			require.NoError(t, err)
+			event.ID = 7
+
+			publisher := NewWebhookPublisher(server.URL, server.Client())
+
+			// when
+			err = publisher.Publish(context.Background(), event)
+
+			// then
+			require.True(t, errors.Is(err, tt.expectedError))
+			require.Equal(t, "7", received.Header.Get("X-Event-ID"))
+			require.Equal(t, "UserCreated", received.Header.Get("X-Event-Type"))
+		})
+	}
+}
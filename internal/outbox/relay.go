@@ -0,0 +1,114 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+const (
+	// batchSize is how many events a relay round claims.
+	batchSize = 100
+
+	baseBackoff = time.Second
+	maxBackoff  = 10 * time.Minute
+)
+
+//go:generate mockgen -destination=./mocks.go -package=outbox github.com/johan-ag/testing/internal/outbox Repository,Publisher
+
+// Relay publishes the pending outbox events at least once, retrying the failed ones with
+// exponential backoff until maxAttempts, when they are dead lettered.
+type Relay struct {
+	repository  Repository
+	publisher   Publisher
+	maxAttempts uint
+	now         func() time.Time
+}
+
+func NewRelay(repository Repository, publisher Publisher, maxAttempts uint) *Relay {
+	return &Relay{
+		repository,
+		publisher,
+		maxAttempts,
+		time.Now,
+	}
+}
+
+// Run relays the pending events once per interval until ctx is done.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Relay(ctx); err != nil {
+				logging.Error(ctx, "cannot relay outbox events", log.Err(err))
+			}
+		}
+	}
+}
+
+// Relay publishes a batch of the pending events and returns how many were delivered. The
+// events stay locked while they are published so that other instances skip them.
+func (r *Relay) Relay(ctx context.Context) (int, error) {
+	delivered := 0
+
+	err := r.repository.WithinTx(ctx, func(repository Repository) error {
+		events, err := repository.Pending(ctx, r.now(), batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			attempts := event.Attempts + 1
+
+			if err := r.publisher.Publish(ctx, event); err != nil {
+				if err := r.fail(ctx, repository, event, attempts, err); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := repository.MarkDelivered(ctx, event.ID, attempts, r.now()); err != nil {
+				return err
+			}
+			delivered++
+		}
+
+		return nil
+	})
+
+	return delivered, err
+}
+
+func (r *Relay) fail(ctx context.Context, repository Repository, event Event, attempts uint, cause error) error {
+	if attempts >= r.maxAttempts {
+		logging.Warn(ctx, "outbox event dead lettered",
+			log.Uint("event_id", event.ID),
+			log.String("event_type", event.Type),
+			log.Uint("attempts", attempts),
+			log.Err(cause),
+		)
+		return repository.DeadLetter(ctx, event.ID, attempts, cause.Error())
+	}
+
+	return repository.Retry(ctx, event.ID, attempts, cause.Error(), r.now().Add(Backoff(attempts)))
+}
+
+// Backoff doubles the wait after each failed attempt, up to maxBackoff.
+func Backoff(attempts uint) time.Duration {
+	wait := baseBackoff
+	for i := uint(1); i < attempts; i++ {
+		wait *= 2
+		if wait >= maxBackoff {
+			return maxBackoff
+		}
+	}
+
+	return wait
+}
@@ -0,0 +1,89 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayRelay(t *testing.T) {
+	now := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	errPublish := errors.New("publish error")
+
+	tests := []struct {
+		name              string
+		event             Event
+		executeBeforeTest func(ctx context.Context, r *MockRepository, p *MockPublisher, event Event)
+		expectedDelivered int
+	}{
+		{
+			name:  "relay test delivered",
+			event: Event{ID: 1, Type: "UserCreated"},
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, p *MockPublisher, event Event) {
+				p.EXPECT().Publish(gomock.Eq(ctx), gomock.Eq(event)).Return(nil)
+				r.EXPECT().MarkDelivered(gomock.Eq(ctx), gomock.Eq(event.ID), gomock.Eq(uint(1)), gomock.Eq(now)).Return(nil)
+			},
+			expectedDelivered: 1,
+		},
+		{
+			name:  "relay test retried with backoff",
+			event: Event{ID: 2, Type: "UserCreated", Attempts: 2},
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, p *MockPublisher, event Event) {
+				p.EXPECT().Publish(gomock.Eq(ctx), gomock.Eq(event)).Return(errPublish)
+				r.EXPECT().Retry(gomock.Eq(ctx), gomock.Eq(event.ID), gomock.Eq(uint(3)), gomock.Eq(errPublish.Error()), gomock.Eq(now.Add(4*time.Second))).Return(nil)
+			},
+			expectedDelivered: 0,
+		},
+		{
+			name:  "relay test dead lettered",
+			event: Event{ID: 3, Type: "UserCreated", Attempts: 4},
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, p *MockPublisher, event Event) {
+				p.EXPECT().Publish(gomock.Eq(ctx), gomock.Eq(event)).Return(errPublish)
+				r.EXPECT().DeadLetter(gomock.Eq(ctx), gomock.Eq(event.ID), gomock.Eq(uint(5)), gomock.Eq(errPublish.Error())).Return(nil)
+			},
+			expectedDelivered: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			repository := NewMockRepository(ctrl)
+			publisher := NewMockPublisher(ctrl)
+
+			repository.
+				EXPECT().
+				WithinTx(gomock.Eq(ctx), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(Repository) error) error {
+					return fn(repository)
+				})
+			repository.
+				EXPECT().
+				Pending(gomock.Eq(ctx), gomock.Eq(now), gomock.Eq(uint(batchSize))).
+				Return([]Event{tt.event}, nil)
+			tt.executeBeforeTest(ctx, repository, publisher, tt.event)
+
+			relay := NewRelay(repository, publisher, 5)
+			relay.now = func() time.Time { return now }
+
+			// when
+			delivered, err := relay.Relay(ctx)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedDelivered, delivered)
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, time.Second, Backoff(1))
+	require.Equal(t, 8*time.Second, Backoff(4))
+	require.Equal(t, maxBackoff, Backoff(30))
+}
@@ -0,0 +1,147 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/database"
+)
+
+type Repository interface {
+	Save(ctx context.Context, event Event) error
+	Pending(ctx context.Context, due time.Time, limit uint) ([]Event, error)
+	MarkDelivered(ctx context.Context, id uint, attempts uint, deliveredAt time.Time) error
+	Retry(ctx context.Context, id uint, attempts uint, lastError string, nextAttemptAt time.Time) error
+	DeadLetter(ctx context.Context, id uint, attempts uint, lastError string) error
+	WithinTx(ctx context.Context, fn func(Repository) error) error
+}
+
+// Event is a domain event waiting in the outbox to be published.
+type Event struct {
+	ID            uint            `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      uint            `json:"-"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// NewEvent builds the eventType event of the aggregate with payload encoded as json.
+func NewEvent(eventType, aggregateType, aggregateID string, payload interface{}) (Event, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       raw,
+	}, nil
+}
+
+// NewRepository returns an outbox repository over queries, bind queries to a transaction
+// with database.Queries.ExecTx to save the event along with the change that raised it.
+func NewRepository(queries *database.Queries) *repository {
+	return &repository{
+		queries,
+		time.Now,
+	}
+}
+
+type repository struct {
+	queries *database.Queries
+	now     func() time.Time
+}
+
+func (r *repository) Save(ctx context.Context, event Event) error {
+	now := r.now().UTC()
+
+	err := r.queries.SaveOutboxEvent(ctx, database.SaveOutboxEventParams{
+		EventType:     event.Type,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		Payload:       event.Payload,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+	if err != nil {
+		return ErrorSavingToDB
+	}
+
+	return nil
+}
+
+// Pending returns the events due to be published and locks them until the transaction ends,
+// call it within WithinTx so that concurrent relays skip them.
+func (r *repository) Pending(ctx context.Context, due time.Time, limit uint) ([]Event, error) {
+	rows, err := r.queries.ListPendingOutboxEvents(ctx, database.ListPendingOutboxEventsParams{
+		NextAttemptAt: due.UTC(),
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(rows))
+	for _, e := range rows {
+		events = append(events, Event{
+			ID:            uint(e.ID),
+			Type:          e.EventType,
+			AggregateType: e.AggregateType,
+			AggregateID:   e.AggregateID,
+			Payload:       e.Payload,
+			Attempts:      uint(e.Attempts),
+			CreatedAt:     e.CreatedAt,
+		})
+	}
+
+	return events, nil
+}
+
+func (r *repository) MarkDelivered(ctx context.Context, id uint, attempts uint, deliveredAt time.Time) error {
+	return r.queries.MarkOutboxEventDelivered(ctx, database.MarkOutboxEventDeliveredParams{
+		Attempts:    int32(attempts),
+		DeliveredAt: sql.NullTime{Time: deliveredAt.UTC(), Valid: true},
+		ID:          int64(id),
+	})
+}
+
+func (r *repository) Retry(ctx context.Context, id uint, attempts uint, lastError string, nextAttemptAt time.Time) error {
+	return r.queries.RetryOutboxEvent(ctx, database.RetryOutboxEventParams{
+		Attempts:      int32(attempts),
+		LastError:     truncate(lastError),
+		NextAttemptAt: nextAttemptAt.UTC(),
+		ID:            int64(id),
+	})
+}
+
+// DeadLetter parks the event, the relay doesn't try to publish it anymore.
+func (r *repository) DeadLetter(ctx context.Context, id uint, attempts uint, lastError string) error {
+	return r.queries.DeadLetterOutboxEvent(ctx, database.DeadLetterOutboxEventParams{
+		Attempts:  int32(attempts),
+		LastError: truncate(lastError),
+		ID:        int64(id),
+	})
+}
+
+func (r *repository) WithinTx(ctx context.Context, fn func(Repository) error) error {
+	return r.queries.ExecTx(ctx, func(q *database.Queries) error {
+		return fn(NewRepository(q))
+	})
+}
+
+// maxErrorLength is the size of the last_error column.
+const maxErrorLength = 1000
+
+func truncate(s string) string {
+	if len(s) > maxErrorLength {
+		return s[:maxErrorLength]
+	}
+
+	return s
+}
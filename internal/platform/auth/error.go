@@ -0,0 +1,12 @@
+package auth
+
+import (
+	"errors"
+)
+
+var (
+	ErrorMissingToken = errors.New("missing bearer token")
+	ErrorInvalidToken = errors.New("invalid token")
+	ErrorUnknownKey   = errors.New("unknown signing key")
+	ErrorInvalidJWKS  = errors.New("invalid jwks")
+)
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+)
+
+// Roles granted through the roles claim of the tokens.
+const (
+	RoleAdmin   = "admin"
+	RoleUser    = "user"
+	RoleService = "service"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string   `json:"sub"`
+	Roles   []string `json:"roles"`
+}
+
+// HasRole reports whether the principal was granted role.
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+type contextKey int
+
+const principalKey contextKey = iota
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// PrincipalFrom returns the principal carried by ctx, ok is false for anonymous requests.
+func PrincipalFrom(ctx context.Context) (principal Principal, ok bool) {
+	principal, ok = ctx.Value(principalKey).(Principal)
+	return principal, ok
+}
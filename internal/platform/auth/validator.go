@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Config sets how bearer tokens are validated. HMACSecret enables HS256 tokens and RSAKeys,
+// usually loaded with LoadJWKS, enables RS256 tokens.
+type Config struct {
+	Issuer     string
+	Audience   string
+	HMACSecret []byte
+	RSAKeys    map[string]*rsa.PublicKey
+	Leeway     time.Duration
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles"`
+}
+
+// Validator authenticates the principals of JWT bearer tokens.
+type Validator struct {
+	config Config
+	now    func() time.Time
+}
+
+func NewValidator(config Config) *Validator {
+	return &Validator{
+		config,
+		time.Now,
+	}
+}
+
+// Validate checks the signature, issuer, audience and expiry of token and returns its principal.
+func (v *Validator) Validate(token string) (Principal, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods(v.methods()), jwt.WithoutClaimsValidation())
+
+	var c claims
+	if _, err := parser.ParseWithClaims(token, &c, v.key); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrorInvalidToken, err)
+	}
+
+	if err := v.validateClaims(c); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrorInvalidToken, err)
+	}
+
+	return Principal{
+		Subject: c.Subject,
+		Roles:   c.Roles,
+	}, nil
+}
+
+// ValidateHeader validates the token of an Authorization header with the Bearer scheme.
+func (v *Validator) ValidateHeader(header string) (Principal, error) {
+	const scheme = "bearer "
+	if len(header) <= len(scheme) || !strings.EqualFold(header[:len(scheme)], scheme) {
+		return Principal{}, ErrorMissingToken
+	}
+
+	return v.Validate(strings.TrimSpace(header[len(scheme):]))
+}
+
+func (v *Validator) methods() []string {
+	var methods []string
+	if len(v.config.HMACSecret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(v.config.RSAKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	return methods
+}
+
+// key returns the key that verifies the signature of token, RS256 keys are picked by the
+// kid header, which can be omitted when the JWKS has a single key.
+func (v *Validator) key(token *jwt.Token) (interface{}, error) {
+	if token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+		if len(v.config.HMACSecret) == 0 {
+			return nil, ErrorUnknownKey
+		}
+		return v.config.HMACSecret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := v.config.RSAKeys[kid]; ok {
+		return key, nil
+	}
+
+	if kid == "" && len(v.config.RSAKeys) == 1 {
+		for _, key := range v.config.RSAKeys {
+			return key, nil
+		}
+	}
+
+	return nil, ErrorUnknownKey
+}
+
+func (v *Validator) validateClaims(c claims) error {
+	now := v.now()
+
+	if c.ExpiresAt == nil {
+		return fmt.Errorf("missing expiry")
+	}
+	if !c.VerifyExpiresAt(now.Add(-v.config.Leeway), true) {
+		return fmt.Errorf("token is expired")
+	}
+	if !c.VerifyNotBefore(now.Add(v.config.Leeway), false) {
+		return fmt.Errorf("token is not valid yet")
+	}
+	if !c.VerifyIssuer(v.config.Issuer, true) {
+		return fmt.Errorf("unexpected issuer %q", c.Issuer)
+	}
+	if !c.VerifyAudience(v.config.Audience, true) {
+		return fmt.Errorf("unexpected audience %v", c.Audience)
+	}
+	if c.Subject == "" {
+		return fmt.Errorf("missing subject")
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorValidate(t *testing.T) {
+	now := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	secret := []byte("secret")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keys, err := ParseJWKS([]byte(fmt.Sprintf(`{"keys":[{"kid":"key-1","kty":"RSA","use":"sig","n":%q,"e":%q}]}`,
+		base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+	)))
+	require.NoError(t, err)
+
+	validClaims := func() claims {
+		return claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "1",
+				Issuer:    "issuer",
+				Audience:  jwt.ClaimStrings{"users-api"},
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			},
+			Roles: []string{"admin"},
+		}
+	}
+	hs256 := func(c claims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString(secret)
+		require.NoError(t, err)
+		return token
+	}
+	rs256 := func(kid string, c claims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(rsaKey)
+		require.NoError(t, err)
+		return signed
+	}
+
+	tests := []struct {
+		name          string
+		token         func() string
+		expectedError error
+	}{
+		{
+			name:          "validate hs256 token",
+			token:         func() string { return hs256(validClaims()) },
+			expectedError: nil,
+		},
+		{
+			name:          "validate rs256 token",
+			token:         func() string { return rs256("key-1", validClaims()) },
+			expectedError: nil,
+		},
+		{
+			name:          "validate rs256 token with unknown key",
+			token:         func() string { return rs256("key-2", validClaims()) },
+			expectedError: ErrorInvalidToken,
+		},
+		{
+			name: "validate expired token",
+			token: func() string {
+				c := validClaims()
+				c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Hour))
+				return hs256(c)
+			},
+			expectedError: ErrorInvalidToken,
+		},
+		{
+			name: "validate token without expiry",
+			token: func() string {
+				c := validClaims()
+				c.ExpiresAt = nil
+				return hs256(c)
+			},
+			expectedError: ErrorInvalidToken,
+		},
+		{
+			name: "validate token of another issuer",
+			token: func() string {
+				c := validClaims()
+				c.Issuer = "other"
+				return hs256(c)
+			},
+			expectedError: ErrorInvalidToken,
+		},
+		{
+			name: "validate token for another audience",
+			token: func() string {
+				c := validClaims()
+				c.Audience = jwt.ClaimStrings{"other"}
+				return hs256(c)
+			},
+			expectedError: ErrorInvalidToken,
+		},
+		{
+			name: "validate unsigned token",
+			token: func() string {
+				token, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+				require.NoError(t, err)
+				return token
+			},
+			expectedError: ErrorInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			validator := NewValidator(Config{
+				Issuer:     "issuer",
+				Audience:   "users-api",
+				HMACSecret: secret,
+				RSAKeys:    keys,
+			})
+			validator.now = func() time.Time { return now }
+
+			// when
+			principal, err := validator.ValidateHeader("Bearer " + tt.token())
+
+			// then
+			require.True(t, errors.Is(err, tt.expectedError), err)
+			if tt.expectedError == nil {
+				require.Equal(t, Principal{Subject: "1", Roles: []string{"admin"}}, principal)
+			}
+		})
+	}
+}
+
+func TestValidatorValidateHeaderWithoutToken(t *testing.T) {
+	validator := NewValidator(Config{HMACSecret: []byte("secret")})
+
+	_, err := validator.ValidateHeader("Basic dXNlcjpwYXNz")
+
+	require.Equal(t, ErrorMissingToken, err)
+}
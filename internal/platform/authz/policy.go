@@ -0,0 +1,139 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+// Reason codes of the denials.
+const (
+	ReasonUnauthenticated = "unauthenticated"
+	ReasonNoPolicy        = "no_policy"
+	ReasonRoleRequired    = "role_required"
+	ReasonNotOwner        = "not_owner"
+)
+
+var ErrorDenied = errors.New("permission denied")
+
+// DeniedError tells why the principal can't perform the action.
+type DeniedError struct {
+	Action string
+	Reason string
+}
+
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("%v: %s (%s)", ErrorDenied, e.Action, e.Reason)
+}
+
+func (e *DeniedError) Unwrap() error {
+	return ErrorDenied
+}
+
+// Resource is what an action is performed on. OwnerID is the subject of the principal that
+// owns it, if any.
+type Resource struct {
+	Type    string
+	ID      string
+	OwnerID string
+}
+
+// Rule allows an action to the principals it is satisfied by, Reason is reported otherwise.
+type Rule struct {
+	Reason string
+	Allows func(principal auth.Principal, resource Resource) bool
+}
+
+// HasRole is satisfied by the principals granted role.
+func HasRole(role string) Rule {
+	return Rule{
+		Reason: ReasonRoleRequired,
+		Allows: func(principal auth.Principal, _ Resource) bool {
+			return principal.HasRole(role)
+		},
+	}
+}
+
+// IsOwner is satisfied by the principal that owns the resource.
+func IsOwner() Rule {
+	return Rule{
+		Reason: ReasonNotOwner,
+		Allows: func(principal auth.Principal, resource Resource) bool {
+			return resource.OwnerID != "" && resource.OwnerID == principal.Subject
+		},
+	}
+}
+
+// Authenticated is satisfied by any principal.
+func Authenticated() Rule {
+	return Rule{
+		Reason: ReasonUnauthenticated,
+		Allows: func(auth.Principal, Resource) bool {
+			return true
+		},
+	}
+}
+
+// Policy maps each action to the rules that allow it, any of them is enough. The actions
+// without rules are denied.
+type Policy map[string][]Rule
+
+// Authorize checks that the principal in ctx can perform action over resource. Denials are
+// logged for audit and returned as a *DeniedError carrying the reason of the last rule tried.
+func (p Policy) Authorize(ctx context.Context, action string, resource Resource) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return deny(ctx, principal, action, resource, ReasonUnauthenticated)
+	}
+
+	rules, ok := p[action]
+	if !ok || len(rules) == 0 {
+		return deny(ctx, principal, action, resource, ReasonNoPolicy)
+	}
+
+	var reason string
+	for _, rule := range rules {
+		if rule.Allows(principal, resource) {
+			return nil
+		}
+		reason = rule.Reason
+	}
+
+	return deny(ctx, principal, action, resource, reason)
+}
+
+// Allows tells whether the principal in ctx can perform action over resource, without
+// logging the denials. It is meant to filter lists down to what the principal can see.
+func (p Policy) Allows(ctx context.Context, action string, resource Resource) bool {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return false
+	}
+
+	for _, rule := range p[action] {
+		if rule.Allows(principal, resource) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func deny(ctx context.Context, principal auth.Principal, action string, resource Resource, reason string) error {
+	logging.Warn(ctx, "authorization denied",
+		log.String("subject", principal.Subject),
+		log.String("action", action),
+		log.String("resource_type", resource.Type),
+		log.String("resource_id", resource.ID),
+		log.String("reason", reason),
+	)
+
+	return &DeniedError{
+		Action: action,
+		Reason: reason,
+	}
+}
@@ -0,0 +1,88 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyAuthorize(t *testing.T) {
+	policy := Policy{
+		"read":   {HasRole(auth.RoleAdmin), IsOwner()},
+		"delete": {HasRole(auth.RoleAdmin)},
+	}
+	admin := auth.Principal{Subject: "1", Roles: []string{auth.RoleAdmin}}
+	user := auth.Principal{Subject: "2", Roles: []string{auth.RoleUser}}
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		action         string
+		resource       Resource
+		expectedReason string
+	}{
+		{
+			name:     "authorize admin",
+			ctx:      auth.WithPrincipal(context.Background(), admin),
+			action:   "delete",
+			resource: Resource{Type: "user", ID: "2", OwnerID: "2"},
+		},
+		{
+			name:     "authorize owner",
+			ctx:      auth.WithPrincipal(context.Background(), user),
+			action:   "read",
+			resource: Resource{Type: "user", ID: "2", OwnerID: "2"},
+		},
+		{
+			name:           "deny another owner",
+			ctx:            auth.WithPrincipal(context.Background(), user),
+			action:         "read",
+			resource:       Resource{Type: "user", ID: "3", OwnerID: "3"},
+			expectedReason: ReasonNotOwner,
+		},
+		{
+			name:           "deny missing role",
+			ctx:            auth.WithPrincipal(context.Background(), user),
+			action:         "delete",
+			resource:       Resource{Type: "user", ID: "2", OwnerID: "2"},
+			expectedReason: ReasonRoleRequired,
+		},
+		{
+			name:           "deny unknown action",
+			ctx:            auth.WithPrincipal(context.Background(), admin),
+			action:         "purge",
+			resource:       Resource{Type: "user"},
+			expectedReason: ReasonNoPolicy,
+		},
+		{
+			name:           "deny anonymous",
+			ctx:            context.Background(),
+			action:         "read",
+			resource:       Resource{Type: "user", ID: "2", OwnerID: "2"},
+			expectedReason: ReasonUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			err := policy.Authorize(tt.ctx, tt.action, tt.resource)
+
+			// then
+			require.Equal(t, tt.expectedReason == "", policy.Allows(tt.ctx, tt.action, tt.resource))
+
+			if tt.expectedReason == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var denied *DeniedError
+			require.True(t, errors.As(err, &denied))
+			require.True(t, errors.Is(err, ErrorDenied))
+			require.Equal(t, tt.expectedReason, denied.Reason)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/database"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+const (
+	// listenBatchSize is how many invalidations a listener reads at a time.
+	listenBatchSize = 500
+
+	// retention is how long the invalidations are kept, far longer than the listeners take
+	// to read them.
+	retention = time.Hour
+)
+
+//go:generate mockgen -destination=./mocks.go -package=cache github.com/johan-ag/testing/internal/platform/cache Broadcaster
+
+// Broadcaster spreads the invalidations of the caches to every instance.
+type Broadcaster interface {
+	Broadcast(ctx context.Context, cache, key string) error
+
+	// Listen calls evict with the keys of cache invalidated from then on, once per
+	// interval until ctx is done.
+	Listen(ctx context.Context, cache string, interval time.Duration, evict func(key string))
+}
+
+func NewMySQLBroadcaster(queries *database.Queries) *mysqlBroadcaster {
+	return &mysqlBroadcaster{
+		queries,
+		time.Now,
+	}
+}
+
+// mysqlBroadcaster keeps the invalidations in a table that every instance polls. An
+// invalidation committed after a later one has been read is missed, which the TTL of the
+// local tier bounds.
+type mysqlBroadcaster struct {
+	queries *database.Queries
+	now     func() time.Time
+}
+
+func (b *mysqlBroadcaster) Broadcast(ctx context.Context, cache, key string) error {
+	return b.queries.SaveCacheInvalidation(ctx, database.SaveCacheInvalidationParams{
+		Cache:     cache,
+		Key:       key,
+		CreatedAt: b.now().UTC(),
+	})
+}
+
+func (b *mysqlBroadcaster) Listen(ctx context.Context, cache string, interval time.Duration, evict func(key string)) {
+	// the invalidations before listening don't matter, the local tier starts empty
+	last, err := b.queries.FindLastCacheInvalidationID(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logging.Error(ctx, "cannot find the last cache invalidation", log.String("cache", cache), log.Err(err))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	pruned := b.now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			last = b.poll(ctx, cache, last, evict)
+
+			if b.now().Sub(pruned) >= retention {
+				if _, err := b.queries.DeleteCacheInvalidations(ctx, b.now().UTC().Add(-retention)); err != nil {
+					logging.Error(ctx, "cannot prune cache invalidations", log.Err(err))
+				}
+				pruned = b.now()
+			}
+		}
+	}
+}
+
+// poll evicts the keys of cache invalidated after the one with id after, and returns the
+// id of the last one read.
+func (b *mysqlBroadcaster) poll(ctx context.Context, cache string, after int64, evict func(key string)) int64 {
+	for {
+		rows, err := b.queries.ListCacheInvalidations(ctx, database.ListCacheInvalidationsParams{
+			Cache: cache,
+			ID:    after,
+			Limit: listenBatchSize,
+		})
+		if err != nil {
+			logging.Error(ctx, "cannot list cache invalidations", log.String("cache", cache), log.Err(err))
+			return after
+		}
+
+		for _, row := range rows {
+			evict(row.Key)
+			after = row.ID
+		}
+
+		if len(rows) < listenBatchSize {
+			return after
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/johan-ag/testing/internal/platform/metrics"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"github.com/mercadolibre/fury_go-toolkit-kvs/pkg/kvs"
+	"golang.org/x/sync/singleflight"
+)
+
+// Tiers of a Cache.
+const (
+	TierLocal  = "local"
+	TierRemote = "remote"
+)
+
+// Config sizes the tiers of a Cache.
+type Config struct {
+	// Size is how many values the local tier holds.
+	Size int
+
+	// LocalTTL is how long the local tier serves a value, which bounds how stale it gets
+	// when an invalidation is missed.
+	LocalTTL time.Duration
+
+	// RemoteTTL is how long KVS serves a value.
+	RemoteTTL time.Duration
+}
+
+// Cache serves values from an in-process LRU, then from KVS, and loads them only when both
+// miss, so that the hot keys don't cost a network hop. The concurrent misses of a key share
+// one lookup to KVS and one load.
+type Cache struct {
+	// generation counts the invalidations, so that the loads that raced one don't cache
+	// what they read before it. The loads hold mu to read it until they cached their value.
+	mu         sync.RWMutex
+	generation uint64
+
+	name        string
+	local       *lru
+	remote      kvs.QueryableClient
+	remoteTTL   time.Duration
+	broadcaster Broadcaster
+	group       singleflight.Group
+	stats       map[string]*counters
+	now         func() time.Time
+}
+
+func New(name string, config Config, remote kvs.QueryableClient, broadcaster Broadcaster) *Cache {
+	return &Cache{
+		name:        name,
+		local:       newLRU(config.Size, config.LocalTTL),
+		remote:      remote,
+		remoteTTL:   config.RemoteTTL,
+		broadcaster: broadcaster,
+		stats: map[string]*counters{
+			TierLocal:  {},
+			TierRemote: {},
+		},
+		now: time.Now,
+	}
+}
+
+// remoteEntry is a value as stored in KVS. It expires on its own, so that a value written
+// back by a load racing an invalidation doesn't outlive RemoteTTL.
+type remoteEntry struct {
+	Value     json.RawMessage `json:"value"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// Get returns the value of key. decode reads the values stored in KVS, which are the JSON
+// of the values returned by load, and load reads the value from the source of truth when
+// no tier holds it. The errors of KVS are logged and taken as misses, the ones of load are
+// returned and nothing is cached.
+//
+// The concurrent misses share the load, so it runs on a context detached from the
+// cancellation of the caller that started it and it must not depend on who that caller
+// is: each caller is authorized before asking the cache. A caller whose ctx is done stops
+// waiting for it.
+func (c *Cache) Get(ctx context.Context, key string, decode func(json.RawMessage) (interface{}, error), load func(context.Context) (interface{}, error)) (interface{}, error) {
+	if value, ok := c.local.Get(key); ok {
+		c.observe(TierLocal, true)
+		return value, nil
+	}
+	c.observe(TierLocal, false)
+
+	results := c.group.DoChan(key, func() (interface{}, error) {
+		ctx := detach(ctx)
+		if value, ok := c.getRemote(ctx, key, decode); ok {
+			c.local.Set(key, value)
+			return value, nil
+		}
+
+		generation := c.currentGeneration()
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		c.set(ctx, key, value, generation)
+		return value, nil
+	})
+
+	select {
+	case result := <-results:
+		return result.Val, result.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Invalidate drops key from both tiers and broadcasts it, so that the other instances drop
+// it from their local tier too.
+func (c *Cache) Invalidate(ctx context.Context, key string) error {
+	c.mu.Lock()
+	c.generation++
+	c.mu.Unlock()
+
+	c.local.Delete(key)
+	// the loads in flight may have read the value before it changed
+	c.group.Forget(key)
+
+	if _, err := c.remote.Delete(ctx, key); err != nil && !errors.Is(err, kvs.ErrKeyNotFound) {
+		return err
+	}
+
+	return c.broadcaster.Broadcast(ctx, c.name, key)
+}
+
+// Run evicts from the local tier the keys invalidated by any instance, listening to them
+// once per interval until ctx is done.
+func (c *Cache) Run(ctx context.Context, interval time.Duration) {
+	c.broadcaster.Listen(ctx, c.name, interval, c.local.Delete)
+}
+
+// Stats are the lookups to each tier since the Cache was created.
+func (c *Cache) Stats() []Stats {
+	return []Stats{
+		c.stats[TierLocal].snapshot(TierLocal),
+		c.stats[TierRemote].snapshot(TierRemote),
+	}
+}
+
+func (c *Cache) getRemote(ctx context.Context, key string, decode func(json.RawMessage) (interface{}, error)) (interface{}, bool) {
+	item, err := c.remote.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, kvs.ErrKeyNotFound) {
+			logging.Warn(ctx, "cannot get cached value", log.String("cache", c.name), log.String("key", key), log.Err(err))
+		}
+		c.observe(TierRemote, false)
+		return nil, false
+	}
+
+	var entry remoteEntry
+	if err := item.GetValue(&entry); err != nil || !c.now().Before(entry.ExpiresAt) {
+		c.observe(TierRemote, false)
+		return nil, false
+	}
+
+	value, err := decode(entry.Value)
+	if err != nil {
+		logging.Warn(ctx, "cannot decode cached value", log.String("cache", c.name), log.String("key", key), log.Err(err))
+		c.observe(TierRemote, false)
+		return nil, false
+	}
+
+	c.observe(TierRemote, true)
+	return value, true
+}
+
+func (c *Cache) currentGeneration() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.generation
+}
+
+// set caches the value loaded at generation, unless an invalidation happened since then.
+func (c *Cache) set(ctx context.Context, key string, value interface{}, generation uint64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.generation != generation {
+		return
+	}
+
+	c.setRemote(ctx, key, value)
+	c.local.Set(key, value)
+}
+
+func (c *Cache) setRemote(ctx context.Context, key string, value interface{}) {
+	raw, err := json.Marshal(value)
+	if err == nil {
+		err = c.remote.Set(ctx, key, remoteEntry{raw, c.now().Add(c.remoteTTL)})
+	}
+	if err != nil {
+		logging.Warn(ctx, "cannot cache value", log.String("cache", c.name), log.String("key", key), log.Err(err))
+	}
+}
+
+func (c *Cache) observe(tier string, hit bool) {
+	counters := c.stats[tier]
+
+	result := metrics.ResultMiss
+	if hit {
+		atomic.AddUint64(&counters.hits, 1)
+		result = metrics.ResultHit
+	} else {
+		atomic.AddUint64(&counters.misses, 1)
+	}
+
+	metrics.ObserveCacheLookup(c.name, tier, result, counters.snapshot(tier).HitRatio())
+}
+
+// Stats counts the lookups to a tier.
+type Stats struct {
+	Tier   string
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRatio is the share of the lookups that hit, zero before the first one.
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// detached keeps the values of a context, such as its logger and its span, but not its
+// deadline nor its cancellation.
+type detached struct {
+	parent context.Context
+}
+
+func detach(ctx context.Context) context.Context {
+	return detached{ctx}
+}
+
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (detached) Err() error {
+	return nil
+}
+
+func (d detached) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
+
+type counters struct {
+	hits   uint64
+	misses uint64
+}
+
+func (c *counters) snapshot(tier string) Stats {
+	return Stats{
+		Tier:   tier,
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+	}
+}
@@ -0,0 +1,269 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockkvs "github.com/johan-ag/testing/internal/platform/kvs"
+	"github.com/mercadolibre/fury_go-toolkit-kvs/pkg/kvs"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var config = Config{
+	Size:      10,
+	LocalTTL:  time.Second,
+	RemoteTTL: time.Minute,
+}
+
+func decodeString(raw json.RawMessage) (interface{}, error) {
+	var s string
+	err := json.Unmarshal(raw, &s)
+	return s, err
+}
+
+func TestCacheGet(t *testing.T) {
+	tests := []struct {
+		name              string
+		executeBeforeTest func(ctx context.Context, r *mockkvs.MockQueryableClient)
+		loadErr           error
+		expectedValue     interface{}
+		expectedError     error
+		expectedLoads     int32
+		expectedLocal     int
+	}{
+		{
+			name: "remote hit",
+			executeBeforeTest: func(ctx context.Context, r *mockkvs.MockQueryableClient) {
+				r.EXPECT().
+					Get(gomock.Eq(detach(ctx)), gomock.Eq("key")).
+					Return(kvs.Item{Key: "key", Value: remoteEntry{json.RawMessage(`"cached"`), now.Add(time.Second)}}, nil)
+			},
+			expectedValue: "cached",
+			expectedLocal: 1,
+		},
+		{
+			name: "miss loads into both tiers",
+			executeBeforeTest: func(ctx context.Context, r *mockkvs.MockQueryableClient) {
+				r.EXPECT().
+					Get(gomock.Eq(detach(ctx)), gomock.Eq("key")).
+					Return(kvs.Item{}, kvs.ErrKeyNotFound)
+				r.EXPECT().
+					Set(gomock.Eq(detach(ctx)), gomock.Eq("key"), gomock.Eq(remoteEntry{json.RawMessage(`"loaded"`), now.Add(time.Minute)})).
+					Return(nil)
+			},
+			expectedValue: "loaded",
+			expectedLoads: 1,
+			expectedLocal: 1,
+		},
+		{
+			name: "expired remote entries are misses",
+			executeBeforeTest: func(ctx context.Context, r *mockkvs.MockQueryableClient) {
+				r.EXPECT().
+					Get(gomock.Eq(detach(ctx)), gomock.Eq("key")).
+					Return(kvs.Item{Key: "key", Value: remoteEntry{json.RawMessage(`"cached"`), now}}, nil)
+				r.EXPECT().
+					Set(gomock.Eq(detach(ctx)), gomock.Eq("key"), gomock.Any()).
+					Return(nil)
+			},
+			expectedValue: "loaded",
+			expectedLoads: 1,
+			expectedLocal: 1,
+		},
+		{
+			name: "remote errors are misses",
+			executeBeforeTest: func(ctx context.Context, r *mockkvs.MockQueryableClient) {
+				r.EXPECT().
+					Get(gomock.Eq(detach(ctx)), gomock.Eq("key")).
+					Return(kvs.Item{}, errors.New("timeout"))
+				r.EXPECT().
+					Set(gomock.Eq(detach(ctx)), gomock.Eq("key"), gomock.Any()).
+					Return(errors.New("timeout"))
+			},
+			expectedValue: "loaded",
+			expectedLoads: 1,
+			expectedLocal: 1,
+		},
+		{
+			name: "load errors aren't cached",
+			executeBeforeTest: func(ctx context.Context, r *mockkvs.MockQueryableClient) {
+				r.EXPECT().
+					Get(gomock.Eq(detach(ctx)), gomock.Eq("key")).
+					Return(kvs.Item{}, kvs.ErrKeyNotFound)
+			},
+			loadErr:       errors.New("not found"),
+			expectedError: errors.New("not found"),
+			expectedLoads: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			remote := mockkvs.NewMockQueryableClient(ctrl)
+
+			tt.executeBeforeTest(ctx, remote)
+
+			c := New("test", config, remote, NewMockBroadcaster(ctrl))
+			c.now = func() time.Time { return now }
+
+			var loads int32
+			load := func(context.Context) (interface{}, error) {
+				atomic.AddInt32(&loads, 1)
+				if tt.loadErr != nil {
+					return nil, tt.loadErr
+				}
+				return "loaded", nil
+			}
+
+			// when
+			value, err := c.Get(ctx, "key", decodeString, load)
+
+			// then
+			require.Equal(t, tt.expectedError, err)
+			require.Equal(t, tt.expectedValue, value)
+			require.Equal(t, tt.expectedLoads, loads)
+			require.Equal(t, tt.expectedLocal, c.local.Len())
+		})
+	}
+}
+
+func TestCacheGetCollapsesConcurrentMisses(t *testing.T) {
+	// given
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	remote := mockkvs.NewMockQueryableClient(ctrl)
+	remote.EXPECT().Get(gomock.Any(), gomock.Eq("key")).Return(kvs.Item{}, kvs.ErrKeyNotFound).Times(1)
+	remote.EXPECT().Set(gomock.Any(), gomock.Eq("key"), gomock.Any()).Return(nil).Times(1)
+
+	c := New("test", config, remote, NewMockBroadcaster(ctrl))
+
+	var loads int32
+	release := make(chan struct{})
+	load := func(context.Context) (interface{}, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return "loaded", nil
+	}
+
+	// when
+	var wg sync.WaitGroup
+	values := make([]interface{}, 10)
+	for i := range values {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			values[i], _ = c.Get(ctx, "key", decodeString, load)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// then
+	require.Equal(t, int32(1), loads)
+	for _, value := range values {
+		require.Equal(t, "loaded", value)
+	}
+
+	_, _ = c.Get(ctx, "key", decodeString, load)
+	stats := c.Stats()
+	require.Equal(t, Stats{Tier: TierLocal, Hits: 1, Misses: 10}, stats[0])
+	require.Equal(t, Stats{Tier: TierRemote, Misses: 1}, stats[1])
+	require.InDelta(t, 1.0/11, stats[0].HitRatio(), 1e-9)
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	// given
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	remote := mockkvs.NewMockQueryableClient(ctrl)
+	remote.EXPECT().Get(gomock.Eq(detach(ctx)), gomock.Eq("key")).Return(kvs.Item{}, kvs.ErrKeyNotFound)
+	remote.EXPECT().Set(gomock.Eq(detach(ctx)), gomock.Eq("key"), gomock.Any()).Return(nil)
+	remote.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq("key")).Return(true, nil)
+
+	broadcaster := NewMockBroadcaster(ctrl)
+	broadcaster.EXPECT().Broadcast(gomock.Eq(ctx), gomock.Eq("test"), gomock.Eq("key")).Return(nil)
+
+	c := New("test", config, remote, broadcaster)
+	_, err := c.Get(ctx, "key", decodeString, func(context.Context) (interface{}, error) {
+		return "loaded", nil
+	})
+	require.NoError(t, err)
+
+	// when
+	err = c.Invalidate(ctx, "key")
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, 0, c.local.Len())
+}
+
+func TestCacheGetDetachesTheLoad(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	ctrl := gomock.NewController(t)
+	remote := mockkvs.NewMockQueryableClient(ctrl)
+	remote.EXPECT().Get(gomock.Any(), gomock.Eq("key")).Return(kvs.Item{}, kvs.ErrKeyNotFound)
+	stored := make(chan struct{})
+	remote.EXPECT().Set(gomock.Any(), gomock.Eq("key"), gomock.Any()).DoAndReturn(func(context.Context, string, interface{}) error {
+		close(stored)
+		return nil
+	})
+
+	c := New("test", config, remote, NewMockBroadcaster(ctrl))
+
+	release := make(chan struct{})
+	load := func(ctx context.Context) (interface{}, error) {
+		<-release
+		return "loaded", ctx.Err()
+	}
+
+	// when
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	value, err := c.Get(ctx, "key", decodeString, load)
+	close(release)
+	<-stored
+
+	// then
+	require.Equal(t, context.Canceled, err)
+	require.Nil(t, value)
+	require.Eventually(t, func() bool { return c.local.Len() == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestCacheGetRacingInvalidate(t *testing.T) {
+	// given
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	remote := mockkvs.NewMockQueryableClient(ctrl)
+	remote.EXPECT().Get(gomock.Any(), gomock.Eq("key")).Return(kvs.Item{}, kvs.ErrKeyNotFound)
+	remote.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq("key")).Return(false, kvs.ErrKeyNotFound)
+
+	broadcaster := NewMockBroadcaster(ctrl)
+	broadcaster.EXPECT().Broadcast(gomock.Eq(ctx), gomock.Eq("test"), gomock.Eq("key")).Return(nil)
+
+	c := New("test", config, remote, broadcaster)
+
+	// when
+	value, err := c.Get(ctx, "key", decodeString, func(context.Context) (interface{}, error) {
+		require.NoError(t, c.Invalidate(ctx, "key"))
+		return "stale", nil
+	})
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "stale", value)
+	require.Equal(t, 0, c.local.Len())
+}
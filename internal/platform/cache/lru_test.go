@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	// given
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newLRU(2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// when
+	_, _ = c.Get("a")
+	c.Set("c", 3)
+
+	// then
+	_, ok := c.Get("b")
+	require.False(t, ok, "the least recently used entry is evicted")
+
+	value, ok := c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, value)
+
+	now = now.Add(time.Minute)
+	_, ok = c.Get("c")
+	require.False(t, ok, "the entries expire after the ttl")
+	require.Equal(t, 1, c.Len())
+}
@@ -0,0 +1,62 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/johan-ag/testing/internal/platform/cache (interfaces: Broadcaster)
+
+// Package cache is a generated GoMock package.
+package cache
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBroadcaster is a mock of Broadcaster interface.
+type MockBroadcaster struct {
+	ctrl     *gomock.Controller
+	recorder *MockBroadcasterMockRecorder
+}
+
+// MockBroadcasterMockRecorder is the mock recorder for MockBroadcaster.
+type MockBroadcasterMockRecorder struct {
+	mock *MockBroadcaster
+}
+
+// NewMockBroadcaster creates a new mock instance.
+func NewMockBroadcaster(ctrl *gomock.Controller) *MockBroadcaster {
+	mock := &MockBroadcaster{ctrl: ctrl}
+	mock.recorder = &MockBroadcasterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBroadcaster) EXPECT() *MockBroadcasterMockRecorder {
+	return m.recorder
+}
+
+// Broadcast mocks base method.
+func (m *MockBroadcaster) Broadcast(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Broadcast", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Broadcast indicates an expected call of Broadcast.
+func (mr *MockBroadcasterMockRecorder) Broadcast(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Broadcast", reflect.TypeOf((*MockBroadcaster)(nil).Broadcast), arg0, arg1, arg2)
+}
+
+// Listen mocks base method.
+func (m *MockBroadcaster) Listen(arg0 context.Context, arg1 string, arg2 time.Duration, arg3 func(string)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Listen", arg0, arg1, arg2, arg3)
+}
+
+// Listen indicates an expected call of Listen.
+func (mr *MockBroadcasterMockRecorder) Listen(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Listen", reflect.TypeOf((*MockBroadcaster)(nil).Listen), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"strings"
+)
+
+// listUsersByIDs, listUserIDs, listBooksByAuthors and listCardsByOwners filter by lists of
+// IDs, sqlc can't generate IN clauses of a variable length. Their params are added by in.
+const listUsersByIDs = `-- name: ListUsersByIDs :many
+SELECT id, name, age, random, created_at, updated_at, deleted_at, activated_at FROM ` + "`" + `users` + "`" + `
+WHERE ` + "`" + `deleted_at` + "`" + ` IS NULL AND ` + "`" + `id` + "`" + ` IN `
+
+const listUserIDs = `-- name: ListUserIDs :many
+SELECT id FROM ` + "`" + `users` + "`" + `
+WHERE ` + "`" + `deleted_at` + "`" + ` IS NULL AND ` + "`" + `id` + "`" + ` IN `
+
+const listBooksByAuthors = `-- name: ListBooksByAuthors :many
+SELECT id, title, author FROM ` + "`" + `books` + "`" + `
+WHERE ` + "`" + `author` + "`" + ` IN `
+
+const listCardsByOwners = `-- name: ListCardsByOwners :many
+SELECT uc.user_id, c.id, c.name, c.status, c.species, c.gender, c.image
+FROM ` + "`" + `user_cards` + "`" + ` uc JOIN ` + "`" + `cards` + "`" + ` c ON c.id = uc.card_id
+WHERE uc.user_id IN `
+
+// ListUsersByIDs returns the users of ids that aren't deleted, in id order.
+func (q *Queries) ListUsersByIDs(ctx context.Context, ids []int32) ([]User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := q.db.QueryContext(ctx, listUsersByIDs+in(len(ids))+" ORDER BY `id`", values(ids)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Age,
+			&i.Random,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ActivatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListUserIDs returns those of ids whose users exist and aren't deleted, in id order.
+func (q *Queries) ListUserIDs(ctx context.Context, ids []int32) ([]int32, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := q.db.QueryContext(ctx, listUserIDs+in(len(ids))+" ORDER BY `id`", values(ids)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListBooksByAuthors returns the books written by any of authors, in id order.
+func (q *Queries) ListBooksByAuthors(ctx context.Context, authors []int32) ([]Book, error) {
+	if len(authors) == 0 {
+		return nil, nil
+	}
+
+	rows, err := q.db.QueryContext(ctx, listBooksByAuthors+in(len(authors))+" ORDER BY `id`", values(authors)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(&i.ID, &i.Title, &i.Author); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+type ListCardsByOwnersRow struct {
+	UserID  int32
+	ID      int32
+	Name    string
+	Status  string
+	Species string
+	Gender  string
+	Image   string
+}
+
+// ListCardsByOwners returns the cards owned by any of owners along with their owner, in
+// owner and card id order.
+func (q *Queries) ListCardsByOwners(ctx context.Context, owners []int32) ([]ListCardsByOwnersRow, error) {
+	if len(owners) == 0 {
+		return nil, nil
+	}
+
+	rows, err := q.db.QueryContext(ctx, listCardsByOwners+in(len(owners))+" ORDER BY uc.user_id, c.id", values(owners)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCardsByOwnersRow
+	for rows.Next() {
+		var i ListCardsByOwnersRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.ID,
+			&i.Name,
+			&i.Status,
+			&i.Species,
+			&i.Gender,
+			&i.Image,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// in is the list of n params of an IN clause.
+func in(n int) string {
+	return "(" + strings.TrimSuffix(strings.Repeat("?, ", n), ", ") + ")"
+}
+
+func values(ids []int32) []interface{} {
+	list := make([]interface{}, len(ids))
+	for i, id := range ids {
+		list[i] = id
+	}
+
+	return list
+}
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/johan-ag/testing/internal/platform/metrics"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+// Types of the query errors.
+const (
+	ErrorTypeTimeout  = "timeout"
+	ErrorTypeCanceled = "canceled"
+	ErrorTypeBadConn  = "bad_conn"
+	ErrorTypeTxDone   = "tx_done"
+	ErrorTypeOther    = "other"
+)
+
+// NewInstrumentedDB records the duration and errors of the queries run through db by sqlc
+// name, and logs the ones slower than slowThreshold. Their args are redacted to their types
+// since they may carry personal data.
+func NewInstrumentedDB(db DBTX, slowThreshold time.Duration) *instrumentedDB {
+	return &instrumentedDB{
+		db,
+		slowThreshold,
+		time.Now,
+	}
+}
+
+type instrumentedDB struct {
+	db            DBTX
+	slowThreshold time.Duration
+	now           func() time.Time
+}
+
+func (i *instrumentedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (result sql.Result, err error) {
+	defer i.observe(ctx, query, args, i.now(), &err)
+	return i.db.ExecContext(ctx, query, args...)
+}
+
+func (i *instrumentedDB) PrepareContext(ctx context.Context, query string) (stmt *sql.Stmt, err error) {
+	defer i.observe(ctx, query, nil, i.now(), &err)
+	return i.db.PrepareContext(ctx, query)
+}
+
+func (i *instrumentedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	defer i.observe(ctx, query, args, i.now(), &err)
+	return i.db.QueryContext(ctx, query, args...)
+}
+
+func (i *instrumentedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	start := i.now()
+	row := i.db.QueryRowContext(ctx, query, args...)
+
+	err := row.Err()
+	i.observe(ctx, query, args, start, &err)
+
+	return row
+}
+
+func (i *instrumentedDB) Unwrap() DBTX {
+	return i.db
+}
+
+func (i *instrumentedDB) WrapTx(tx DBTX) DBTX {
+	return &instrumentedDB{
+		tx,
+		i.slowThreshold,
+		i.now,
+	}
+}
+
+// observe is deferred by the instrumented methods, err points to their named result.
+func (i *instrumentedDB) observe(ctx context.Context, query string, args []interface{}, start time.Time, err *error) {
+	name := queryName(query)
+	duration := i.now().Sub(start)
+	errorType := ErrorType(*err)
+
+	metrics.ObserveQuery(name, duration, errorType)
+
+	if duration >= i.slowThreshold {
+		logging.Warn(ctx, "slow query",
+			log.String("query", name),
+			log.Duration("duration", duration),
+			log.String("args", redact(args)),
+		)
+	}
+}
+
+// ErrorType classifies err for the metrics, it is empty when err is nil. MySQL errors are
+// typed by their number, e.g. mysql_1062 for duplicate keys.
+func ErrorType(err error) string {
+	var mysqlErr *mysql.MySQLError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorTypeTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorTypeCanceled
+	case errors.Is(err, driver.ErrBadConn):
+		return ErrorTypeBadConn
+	case errors.Is(err, sql.ErrTxDone):
+		return ErrorTypeTxDone
+	case errors.As(err, &mysqlErr):
+		return fmt.Sprintf("mysql_%d", mysqlErr.Number)
+	}
+
+	return ErrorTypeOther
+}
+
+// redact replaces args by their types.
+func redact(args []interface{}) string {
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = fmt.Sprintf("%T", arg)
+	}
+
+	return "[" + strings.Join(types, ", ") + "]"
+}
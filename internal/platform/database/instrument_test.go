@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorType(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedType string
+	}{
+		{
+			name:         "error type none",
+			err:          nil,
+			expectedType: "",
+		},
+		{
+			name:         "error type timeout",
+			err:          fmt.Errorf("exec: %w", context.DeadlineExceeded),
+			expectedType: ErrorTypeTimeout,
+		},
+		{
+			name:         "error type mysql",
+			err:          &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"},
+			expectedType: "mysql_1062",
+		},
+		{
+			name:         "error type other",
+			err:          errors.New("boom"),
+			expectedType: ErrorTypeOther,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			errorType := ErrorType(tt.err)
+
+			// then
+			require.Equal(t, tt.expectedType, errorType)
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	// when
+	redacted := redact([]interface{}{"rick", int32(70), time.Time{}})
+
+	// then
+	require.Equal(t, "[string, int32, time.Time]", redacted)
+}
@@ -4,15 +4,155 @@
 
 package database
 
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+type AuditEvent struct {
+	ID        int64
+	Entity    string
+	EntityID  string
+	Action    string
+	Actor     string
+	RequestID string
+	Diff      json.RawMessage
+	CreatedAt time.Time
+}
+
 type Book struct {
 	ID     int32
 	Title  string
 	Author int32
 }
 
+type CacheInvalidation struct {
+	ID        int64
+	Cache     string
+	Key       string
+	CreatedAt time.Time
+}
+
+type Card struct {
+	ID        int32
+	Name      string
+	Status    string
+	Species   string
+	Gender    string
+	Image     string
+	Hash      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type CardSyncRun struct {
+	ID         int32
+	Status     string
+	Added      int32
+	Updated    int32
+	DurationMs int64
+	Error      string
+	StartedAt  time.Time
+	FinishedAt sql.NullTime
+	LastPage   int32
+}
+
+type Job struct {
+	ID          int64
+	Type        string
+	Payload     json.RawMessage
+	Status      string
+	Attempts    int32
+	LastError   string
+	Principal   json.RawMessage
+	RequestID   string
+	RunAt       time.Time
+	LockedUntil sql.NullTime
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	FinishedAt  sql.NullTime
+}
+
+type OutboxEvent struct {
+	ID            int64
+	EventType     string
+	AggregateType string
+	AggregateID   string
+	Payload       json.RawMessage
+	Status        string
+	Attempts      int32
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	DeliveredAt   sql.NullTime
+}
+
 type User struct {
-	ID     int32
-	Name   string
-	Age    int32
-	Random string
+	ID          int32
+	Name        string
+	Age         int32
+	Random      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   sql.NullTime
+	ActivatedAt sql.NullTime
+}
+
+type ScheduleLock struct {
+	Name       string
+	Tick       time.Time
+	Owner      string
+	AcquiredAt time.Time
+}
+
+type UserCard struct {
+	UserID    int32
+	CardID    int32
+	CreatedAt time.Time
+}
+
+type UserImport struct {
+	ID            int32
+	Format        string
+	Status        string
+	CreatedBy     string
+	ProcessedRows int32
+	FailedRows    int32
+	Progress      int32
+	Error         string
+	ErrorReport   string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	FinishedAt    sql.NullTime
+}
+
+type UserImportPart struct {
+	ImportID int32
+	Part     int32
+	Content  []byte
+}
+
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int32
+	EventID        int64
+	EventType      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int32
+	ResponseStatus int32
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    sql.NullTime
+}
+
+type WebhookSubscription struct {
+	ID         int32
+	Url        string
+	EventTypes json.RawMessage
+	Secret     string
+	CreatedAt  time.Time
+	DeletedAt  sql.NullTime
 }
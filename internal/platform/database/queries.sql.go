@@ -8,8 +8,239 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"time"
 )
 
+const acquireScheduleTick = `-- name: AcquireScheduleTick :execresult
+INSERT INTO ` + "`" + `schedule_locks` + "`" + ` (
+    ` + "`" + `name` + "`" + `, ` + "`" + `tick` + "`" + `, ` + "`" + `owner` + "`" + `, ` + "`" + `acquired_at` + "`" + `
+) VALUES ( ?, ?, ?, ? )
+ON DUPLICATE KEY UPDATE
+    ` + "`" + `owner` + "`" + ` = IF(` + "`" + `tick` + "`" + ` < VALUES(` + "`" + `tick` + "`" + `), VALUES(` + "`" + `owner` + "`" + `), ` + "`" + `owner` + "`" + `),
+    ` + "`" + `acquired_at` + "`" + ` = IF(` + "`" + `tick` + "`" + ` < VALUES(` + "`" + `tick` + "`" + `), VALUES(` + "`" + `acquired_at` + "`" + `), ` + "`" + `acquired_at` + "`" + `),
+    ` + "`" + `tick` + "`" + ` = GREATEST(` + "`" + `tick` + "`" + `, VALUES(` + "`" + `tick` + "`" + `))
+`
+
+type AcquireScheduleTickParams struct {
+	Name       string
+	Tick       time.Time
+	Owner      string
+	AcquiredAt time.Time
+}
+
+// Scheduler
+func (q *Queries) AcquireScheduleTick(ctx context.Context, arg AcquireScheduleTickParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, acquireScheduleTick,
+		arg.Name,
+		arg.Tick,
+		arg.Owner,
+		arg.AcquiredAt,
+	)
+}
+
+const activateUser = `-- name: ActivateUser :execresult
+UPDATE ` + "`" + `users` + "`" + ` SET ` + "`" + `activated_at` + "`" + ` = ?, ` + "`" + `updated_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ? AND ` + "`" + `activated_at` + "`" + ` IS NULL AND ` + "`" + `deleted_at` + "`" + ` IS NULL
+`
+
+type ActivateUserParams struct {
+	ActivatedAt sql.NullTime
+	UpdatedAt   time.Time
+	ID          int32
+}
+
+func (q *Queries) ActivateUser(ctx context.Context, arg ActivateUserParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, activateUser, arg.ActivatedAt, arg.UpdatedAt, arg.ID)
+}
+
+const assignCard = `-- name: AssignCard :exec
+INSERT INTO ` + "`" + `user_cards` + "`" + ` (
+    ` + "`" + `user_id` + "`" + `, ` + "`" + `card_id` + "`" + `, ` + "`" + `created_at` + "`" + `
+) VALUES ( ?, ?, ? )
+ON DUPLICATE KEY UPDATE ` + "`" + `created_at` + "`" + ` = ` + "`" + `created_at` + "`" + `
+`
+
+type AssignCardParams struct {
+	UserID    int32
+	CardID    int32
+	CreatedAt time.Time
+}
+
+func (q *Queries) AssignCard(ctx context.Context, arg AssignCardParams) error {
+	_, err := q.db.ExecContext(ctx, assignCard, arg.UserID, arg.CardID, arg.CreatedAt)
+	return err
+}
+
+const claimJob = `-- name: ClaimJob :exec
+UPDATE ` + "`" + `jobs` + "`" + ` SET ` + "`" + `status` + "`" + ` = 'running', ` + "`" + `attempts` + "`" + ` = ?, ` + "`" + `locked_until` + "`" + ` = ?, ` + "`" + `updated_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+type ClaimJobParams struct {
+	Attempts    int32
+	LockedUntil sql.NullTime
+	UpdatedAt   time.Time
+	ID          int64
+}
+
+func (q *Queries) ClaimJob(ctx context.Context, arg ClaimJobParams) error {
+	_, err := q.db.ExecContext(ctx, claimJob,
+		arg.Attempts,
+		arg.LockedUntil,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const claimWebhookDelivery = `-- name: ClaimWebhookDelivery :exec
+UPDATE ` + "`" + `webhook_deliveries` + "`" + ` SET ` + "`" + `attempts` + "`" + ` = ?, ` + "`" + `next_attempt_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+type ClaimWebhookDeliveryParams struct {
+	Attempts      int32
+	NextAttemptAt time.Time
+	ID            int64
+}
+
+func (q *Queries) ClaimWebhookDelivery(ctx context.Context, arg ClaimWebhookDeliveryParams) error {
+	_, err := q.db.ExecContext(ctx, claimWebhookDelivery, arg.Attempts, arg.NextAttemptAt, arg.ID)
+	return err
+}
+
+const completeJob = `-- name: CompleteJob :execresult
+UPDATE ` + "`" + `jobs` + "`" + ` SET ` + "`" + `status` + "`" + ` = 'succeeded', ` + "`" + `locked_until` + "`" + ` = NULL, ` + "`" + `updated_at` + "`" + ` = ?, ` + "`" + `finished_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ? AND ` + "`" + `attempts` + "`" + ` = ? AND ` + "`" + `status` + "`" + ` = 'running'
+`
+
+type CompleteJobParams struct {
+	UpdatedAt  time.Time
+	FinishedAt sql.NullTime
+	ID         int64
+	Attempts   int32
+}
+
+func (q *Queries) CompleteJob(ctx context.Context, arg CompleteJobParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, completeJob,
+		arg.UpdatedAt,
+		arg.FinishedAt,
+		arg.ID,
+		arg.Attempts,
+	)
+}
+
+const deadLetterOutboxEvent = `-- name: DeadLetterOutboxEvent :exec
+UPDATE ` + "`" + `outbox_events` + "`" + ` SET ` + "`" + `status` + "`" + ` = 'dead', ` + "`" + `attempts` + "`" + ` = ?, ` + "`" + `last_error` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+type DeadLetterOutboxEventParams struct {
+	Attempts  int32
+	LastError string
+	ID        int64
+}
+
+func (q *Queries) DeadLetterOutboxEvent(ctx context.Context, arg DeadLetterOutboxEventParams) error {
+	_, err := q.db.ExecContext(ctx, deadLetterOutboxEvent, arg.Attempts, arg.LastError, arg.ID)
+	return err
+}
+
+const deleteCacheInvalidations = `-- name: DeleteCacheInvalidations :execrows
+DELETE FROM ` + "`" + `cache_invalidations` + "`" + ` WHERE ` + "`" + `created_at` + "`" + ` < ?
+`
+
+func (q *Queries) DeleteCacheInvalidations(ctx context.Context, createdAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCacheInvalidations, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteUserImportParts = `-- name: DeleteUserImportParts :exec
+DELETE FROM ` + "`" + `user_import_parts` + "`" + ` WHERE ` + "`" + `import_id` + "`" + ` = ?
+`
+
+func (q *Queries) DeleteUserImportParts(ctx context.Context, importID int32) error {
+	_, err := q.db.ExecContext(ctx, deleteUserImportParts, importID)
+	return err
+}
+
+const deleteWebhookSubscription = `-- name: DeleteWebhookSubscription :execresult
+UPDATE ` + "`" + `webhook_subscriptions` + "`" + ` SET ` + "`" + `deleted_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ? AND ` + "`" + `deleted_at` + "`" + ` IS NULL
+`
+
+type DeleteWebhookSubscriptionParams struct {
+	DeletedAt sql.NullTime
+	ID        int32
+}
+
+func (q *Queries) DeleteWebhookSubscription(ctx context.Context, arg DeleteWebhookSubscriptionParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteWebhookSubscription, arg.DeletedAt, arg.ID)
+}
+
+const extendJobLock = `-- name: ExtendJobLock :execresult
+UPDATE ` + "`" + `jobs` + "`" + ` SET ` + "`" + `locked_until` + "`" + ` = ?, ` + "`" + `updated_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ? AND ` + "`" + `attempts` + "`" + ` = ? AND ` + "`" + `status` + "`" + ` = 'running'
+`
+
+type ExtendJobLockParams struct {
+	LockedUntil sql.NullTime
+	UpdatedAt   time.Time
+	ID          int64
+	Attempts    int32
+}
+
+func (q *Queries) ExtendJobLock(ctx context.Context, arg ExtendJobLockParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, extendJobLock,
+		arg.LockedUntil,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.Attempts,
+	)
+}
+
+const failJob = `-- name: FailJob :execresult
+UPDATE ` + "`" + `jobs` + "`" + ` SET ` + "`" + `status` + "`" + ` = 'failed', ` + "`" + `last_error` + "`" + ` = ?, ` + "`" + `locked_until` + "`" + ` = NULL, ` + "`" + `updated_at` + "`" + ` = ?, ` + "`" + `finished_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ? AND ` + "`" + `attempts` + "`" + ` = ? AND ` + "`" + `status` + "`" + ` = 'running'
+`
+
+type FailJobParams struct {
+	LastError  string
+	UpdatedAt  time.Time
+	FinishedAt sql.NullTime
+	ID         int64
+	Attempts   int32
+}
+
+func (q *Queries) FailJob(ctx context.Context, arg FailJobParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, failJob,
+		arg.LastError,
+		arg.UpdatedAt,
+		arg.FinishedAt,
+		arg.ID,
+		arg.Attempts,
+	)
+}
+
+const failStaleCardSyncRuns = `-- name: FailStaleCardSyncRuns :execresult
+UPDATE ` + "`" + `card_sync_runs` + "`" + ` SET ` + "`" + `status` + "`" + ` = 'failed', ` + "`" + `error` + "`" + ` = ?, ` + "`" + `finished_at` + "`" + ` = ?
+WHERE ` + "`" + `status` + "`" + ` = 'running' AND ` + "`" + `started_at` + "`" + ` < ?
+`
+
+type FailStaleCardSyncRunsParams struct {
+	Error      string
+	FinishedAt sql.NullTime
+	StartedAt  time.Time
+}
+
+func (q *Queries) FailStaleCardSyncRuns(ctx context.Context, arg FailStaleCardSyncRunsParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, failStaleCardSyncRuns, arg.Error, arg.FinishedAt, arg.StartedAt)
+}
+
 const findBook = `-- name: FindBook :one
 SELECT id, title, author FROM ` + "`" + `books` + "`" + ` WHERE ` + "`" + `id` + "`" + ` = ?
 `
@@ -21,8 +252,97 @@ func (q *Queries) FindBook(ctx context.Context, id int32) (Book, error) {
 	return i, err
 }
 
+const findCard = `-- name: FindCard :one
+SELECT id, name, status, species, gender, image, hash, created_at, updated_at FROM ` + "`" + `cards` + "`" + ` WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+func (q *Queries) FindCard(ctx context.Context, id int32) (Card, error) {
+	row := q.db.QueryRowContext(ctx, findCard, id)
+	var i Card
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Status,
+		&i.Species,
+		&i.Gender,
+		&i.Image,
+		&i.Hash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findJob = `-- name: FindJob :one
+SELECT id, type, payload, status, attempts, last_error, principal, request_id, run_at, locked_until, created_at, updated_at, finished_at FROM ` + "`" + `jobs` + "`" + ` WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+func (q *Queries) FindJob(ctx context.Context, id int64) (Job, error) {
+	row := q.db.QueryRowContext(ctx, findJob, id)
+	var i Job
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.LastError,
+		&i.Principal,
+		&i.RequestID,
+		&i.RunAt,
+		&i.LockedUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const findLastCacheInvalidationID = `-- name: FindLastCacheInvalidationID :one
+SELECT ` + "`" + `id` + "`" + ` FROM ` + "`" + `cache_invalidations` + "`" + ` ORDER BY ` + "`" + `id` + "`" + ` DESC LIMIT 1
+`
+
+func (q *Queries) FindLastCacheInvalidationID(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, findLastCacheInvalidationID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const findLastCardSyncRun = `-- name: FindLastCardSyncRun :one
+SELECT id, status, added, updated, duration_ms, error, started_at, finished_at, last_page FROM ` + "`" + `card_sync_runs` + "`" + ` ORDER BY ` + "`" + `id` + "`" + ` DESC LIMIT 1
+`
+
+func (q *Queries) FindLastCardSyncRun(ctx context.Context) (CardSyncRun, error) {
+	row := q.db.QueryRowContext(ctx, findLastCardSyncRun)
+	var i CardSyncRun
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.Added,
+		&i.Updated,
+		&i.DurationMs,
+		&i.Error,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.LastPage,
+	)
+	return i, err
+}
+
+const findLastSucceededCardSyncRunPage = `-- name: FindLastSucceededCardSyncRunPage :one
+SELECT ` + "`" + `last_page` + "`" + ` FROM ` + "`" + `card_sync_runs` + "`" + ` WHERE ` + "`" + `status` + "`" + ` = 'succeeded' ORDER BY ` + "`" + `id` + "`" + ` DESC LIMIT 1
+`
+
+func (q *Queries) FindLastSucceededCardSyncRunPage(ctx context.Context) (int32, error) {
+	row := q.db.QueryRowContext(ctx, findLastSucceededCardSyncRunPage)
+	var last_page int32
+	err := row.Scan(&last_page)
+	return last_page, err
+}
+
 const findUser = `-- name: FindUser :one
-SELECT id, name, age, random FROM ` + "`" + `users` + "`" + ` WHERE ` + "`" + `id` + "`" + ` = ?
+SELECT id, name, age, random, created_at, updated_at, deleted_at, activated_at FROM ` + "`" + `users` + "`" + ` WHERE ` + "`" + `id` + "`" + ` = ? AND ` + "`" + `deleted_at` + "`" + ` IS NULL
 `
 
 func (q *Queries) FindUser(ctx context.Context, id int32) (User, error) {
@@ -33,10 +353,923 @@ func (q *Queries) FindUser(ctx context.Context, id int32) (User, error) {
 		&i.Name,
 		&i.Age,
 		&i.Random,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ActivatedAt,
+	)
+	return i, err
+}
+
+const findUserImport = `-- name: FindUserImport :one
+SELECT id, format, status, created_by, processed_rows, failed_rows, progress, error, error_report, created_at, updated_at, finished_at FROM ` + "`" + `user_imports` + "`" + ` WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+func (q *Queries) FindUserImport(ctx context.Context, id int32) (UserImport, error) {
+	row := q.db.QueryRowContext(ctx, findUserImport, id)
+	var i UserImport
+	err := row.Scan(
+		&i.ID,
+		&i.Format,
+		&i.Status,
+		&i.CreatedBy,
+		&i.ProcessedRows,
+		&i.FailedRows,
+		&i.Progress,
+		&i.Error,
+		&i.ErrorReport,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FinishedAt,
 	)
 	return i, err
 }
 
+const findUserImportPart = `-- name: FindUserImportPart :one
+SELECT ` + "`" + `content` + "`" + ` FROM ` + "`" + `user_import_parts` + "`" + ` WHERE ` + "`" + `import_id` + "`" + ` = ? AND ` + "`" + `part` + "`" + ` = ?
+`
+
+type FindUserImportPartParams struct {
+	ImportID int32
+	Part     int32
+}
+
+func (q *Queries) FindUserImportPart(ctx context.Context, arg FindUserImportPartParams) ([]byte, error) {
+	row := q.db.QueryRowContext(ctx, findUserImportPart, arg.ImportID, arg.Part)
+	var content []byte
+	err := row.Scan(&content)
+	return content, err
+}
+
+const findUserIncludingDeleted = `-- name: FindUserIncludingDeleted :one
+SELECT id, name, age, random, created_at, updated_at, deleted_at, activated_at FROM ` + "`" + `users` + "`" + ` WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+func (q *Queries) FindUserIncludingDeleted(ctx context.Context, id int32) (User, error) {
+	row := q.db.QueryRowContext(ctx, findUserIncludingDeleted, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Age,
+		&i.Random,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ActivatedAt,
+	)
+	return i, err
+}
+
+const findWebhookDelivery = `-- name: FindWebhookDelivery :one
+SELECT id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, created_at, delivered_at FROM ` + "`" + `webhook_deliveries` + "`" + ` WHERE ` + "`" + `id` + "`" + ` = ? AND ` + "`" + `subscription_id` + "`" + ` = ?
+`
+
+type FindWebhookDeliveryParams struct {
+	ID             int64
+	SubscriptionID int32
+}
+
+func (q *Queries) FindWebhookDelivery(ctx context.Context, arg FindWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, findWebhookDelivery, arg.ID, arg.SubscriptionID)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.ResponseStatus,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.CreatedAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const findWebhookSubscription = `-- name: FindWebhookSubscription :one
+SELECT id, url, event_types, secret, created_at, deleted_at FROM ` + "`" + `webhook_subscriptions` + "`" + ` WHERE ` + "`" + `id` + "`" + ` = ? AND ` + "`" + `deleted_at` + "`" + ` IS NULL
+`
+
+func (q *Queries) FindWebhookSubscription(ctx context.Context, id int32) (WebhookSubscription, error) {
+	row := q.db.QueryRowContext(ctx, findWebhookSubscription, id)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.EventTypes,
+		&i.Secret,
+		&i.CreatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const finishCardSyncRun = `-- name: FinishCardSyncRun :exec
+UPDATE ` + "`" + `card_sync_runs` + "`" + ` SET ` + "`" + `status` + "`" + ` = ?, ` + "`" + `added` + "`" + ` = ?, ` + "`" + `updated` + "`" + ` = ?, ` + "`" + `duration_ms` + "`" + ` = ?, ` + "`" + `error` + "`" + ` = ?, ` + "`" + `finished_at` + "`" + ` = ?, ` + "`" + `last_page` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+type FinishCardSyncRunParams struct {
+	Status     string
+	Added      int32
+	Updated    int32
+	DurationMs int64
+	Error      string
+	FinishedAt sql.NullTime
+	LastPage   int32
+	ID         int32
+}
+
+func (q *Queries) FinishCardSyncRun(ctx context.Context, arg FinishCardSyncRunParams) error {
+	_, err := q.db.ExecContext(ctx, finishCardSyncRun,
+		arg.Status,
+		arg.Added,
+		arg.Updated,
+		arg.DurationMs,
+		arg.Error,
+		arg.FinishedAt,
+		arg.LastPage,
+		arg.ID,
+	)
+	return err
+}
+
+const finishUserImport = `-- name: FinishUserImport :exec
+UPDATE ` + "`" + `user_imports` + "`" + ` SET ` + "`" + `status` + "`" + ` = ?, ` + "`" + `processed_rows` + "`" + ` = ?, ` + "`" + `failed_rows` + "`" + ` = ?, ` + "`" + `progress` + "`" + ` = ?, ` + "`" + `error` + "`" + ` = ?, ` + "`" + `error_report` + "`" + ` = ?, ` + "`" + `updated_at` + "`" + ` = ?, ` + "`" + `finished_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+type FinishUserImportParams struct {
+	Status        string
+	ProcessedRows int32
+	FailedRows    int32
+	Progress      int32
+	Error         string
+	ErrorReport   string
+	UpdatedAt     time.Time
+	FinishedAt    sql.NullTime
+	ID            int32
+}
+
+func (q *Queries) FinishUserImport(ctx context.Context, arg FinishUserImportParams) error {
+	_, err := q.db.ExecContext(ctx, finishUserImport,
+		arg.Status,
+		arg.ProcessedRows,
+		arg.FailedRows,
+		arg.Progress,
+		arg.Error,
+		arg.ErrorReport,
+		arg.UpdatedAt,
+		arg.FinishedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const listAuditEvents = `-- name: ListAuditEvents :many
+SELECT id, entity, entity_id, action, actor, request_id, diff, created_at FROM ` + "`" + `audit_events` + "`" + `
+WHERE ` + "`" + `entity` + "`" + ` = ? AND ` + "`" + `entity_id` + "`" + ` = ?
+ORDER BY ` + "`" + `id` + "`" + `
+`
+
+type ListAuditEventsParams struct {
+	Entity   string
+	EntityID string
+}
+
+func (q *Queries) ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listAuditEvents, arg.Entity, arg.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditEvent
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.Entity,
+			&i.EntityID,
+			&i.Action,
+			&i.Actor,
+			&i.RequestID,
+			&i.Diff,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCacheInvalidations = `-- name: ListCacheInvalidations :many
+SELECT ` + "`" + `id` + "`" + `, ` + "`" + `key` + "`" + ` FROM ` + "`" + `cache_invalidations` + "`" + `
+WHERE ` + "`" + `cache` + "`" + ` = ? AND ` + "`" + `id` + "`" + ` > ? ORDER BY ` + "`" + `id` + "`" + ` LIMIT ?
+`
+
+type ListCacheInvalidationsParams struct {
+	Cache string
+	ID    int64
+	Limit int32
+}
+
+type ListCacheInvalidationsRow struct {
+	ID  int64
+	Key string
+}
+
+func (q *Queries) ListCacheInvalidations(ctx context.Context, arg ListCacheInvalidationsParams) ([]ListCacheInvalidationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCacheInvalidations, arg.Cache, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCacheInvalidationsRow
+	for rows.Next() {
+		var i ListCacheInvalidationsRow
+		if err := rows.Scan(&i.ID, &i.Key); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCardHashes = `-- name: ListCardHashes :many
+SELECT ` + "`" + `id` + "`" + `, ` + "`" + `hash` + "`" + ` FROM ` + "`" + `cards` + "`" + `
+`
+
+type ListCardHashesRow struct {
+	ID   int32
+	Hash string
+}
+
+// Cards
+func (q *Queries) ListCardHashes(ctx context.Context) ([]ListCardHashesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCardHashes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCardHashesRow
+	for rows.Next() {
+		var i ListCardHashesRow
+		if err := rows.Scan(&i.ID, &i.Hash); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCards = `-- name: ListCards :many
+SELECT id, name, status, species, gender, image, hash, created_at, updated_at FROM ` + "`" + `cards` + "`" + `
+ORDER BY ` + "`" + `id` + "`" + ` LIMIT ? OFFSET ?
+`
+
+type ListCardsParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListCards(ctx context.Context, arg ListCardsParams) ([]Card, error) {
+	rows, err := q.db.QueryContext(ctx, listCards, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Card
+	for rows.Next() {
+		var i Card
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Status,
+			&i.Species,
+			&i.Gender,
+			&i.Image,
+			&i.Hash,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listClaimableJobs = `-- name: ListClaimableJobs :many
+SELECT id, type, payload, status, attempts, last_error, principal, request_id, run_at, locked_until, created_at, updated_at, finished_at FROM ` + "`" + `jobs` + "`" + `
+WHERE ` + "`" + `type` + "`" + ` = ? AND (
+    (` + "`" + `status` + "`" + ` = 'queued' AND ` + "`" + `run_at` + "`" + ` <= ?) OR (` + "`" + `status` + "`" + ` = 'running' AND ` + "`" + `locked_until` + "`" + ` <= ?)
+)
+ORDER BY ` + "`" + `id` + "`" + ` LIMIT ?
+FOR UPDATE SKIP LOCKED
+`
+
+type ListClaimableJobsParams struct {
+	Type        string
+	RunAt       time.Time
+	LockedUntil sql.NullTime
+	Limit       int32
+}
+
+func (q *Queries) ListClaimableJobs(ctx context.Context, arg ListClaimableJobsParams) ([]Job, error) {
+	rows, err := q.db.QueryContext(ctx, listClaimableJobs,
+		arg.Type,
+		arg.RunAt,
+		arg.LockedUntil,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Job
+	for rows.Next() {
+		var i Job
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.LastError,
+			&i.Principal,
+			&i.RequestID,
+			&i.RunAt,
+			&i.LockedUntil,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingOutboxEvents = `-- name: ListPendingOutboxEvents :many
+SELECT id, event_type, aggregate_type, aggregate_id, payload, status, attempts, last_error, next_attempt_at, created_at, delivered_at FROM ` + "`" + `outbox_events` + "`" + `
+WHERE ` + "`" + `status` + "`" + ` = 'pending' AND ` + "`" + `next_attempt_at` + "`" + ` <= ?
+ORDER BY ` + "`" + `id` + "`" + ` LIMIT ?
+FOR UPDATE SKIP LOCKED
+`
+
+type ListPendingOutboxEventsParams struct {
+	NextAttemptAt time.Time
+	Limit         int32
+}
+
+func (q *Queries) ListPendingOutboxEvents(ctx context.Context, arg ListPendingOutboxEventsParams) ([]OutboxEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listPendingOutboxEvents, arg.NextAttemptAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OutboxEvent
+	for rows.Next() {
+		var i OutboxEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingWebhookDeliveries = `-- name: ListPendingWebhookDeliveries :many
+SELECT id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, created_at, delivered_at FROM ` + "`" + `webhook_deliveries` + "`" + `
+WHERE ` + "`" + `status` + "`" + ` = 'pending' AND ` + "`" + `next_attempt_at` + "`" + ` <= ?
+ORDER BY ` + "`" + `id` + "`" + ` LIMIT ?
+FOR UPDATE SKIP LOCKED
+`
+
+type ListPendingWebhookDeliveriesParams struct {
+	NextAttemptAt time.Time
+	Limit         int32
+}
+
+func (q *Queries) ListPendingWebhookDeliveries(ctx context.Context, arg ListPendingWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, listPendingWebhookDeliveries, arg.NextAttemptAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.ResponseStatus,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT id, name, age, random, created_at, updated_at, deleted_at, activated_at FROM ` + "`" + `users` + "`" + ` WHERE ` + "`" + `deleted_at` + "`" + ` IS NULL
+ORDER BY ` + "`" + `id` + "`" + ` LIMIT ? OFFSET ?
+`
+
+type ListUsersParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Age,
+			&i.Random,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ActivatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersAfter = `-- name: ListUsersAfter :many
+SELECT id, name, age, random, created_at, updated_at, deleted_at, activated_at FROM ` + "`" + `users` + "`" + ` WHERE ` + "`" + `id` + "`" + ` > ? AND ` + "`" + `deleted_at` + "`" + ` IS NULL
+ORDER BY ` + "`" + `id` + "`" + ` LIMIT ?
+`
+
+type ListUsersAfterParams struct {
+	ID    int32
+	Limit int32
+}
+
+func (q *Queries) ListUsersAfter(ctx context.Context, arg ListUsersAfterParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersAfter, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Age,
+			&i.Random,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ActivatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersIncludingDeleted = `-- name: ListUsersIncludingDeleted :many
+SELECT id, name, age, random, created_at, updated_at, deleted_at, activated_at FROM ` + "`" + `users` + "`" + `
+ORDER BY ` + "`" + `id` + "`" + ` LIMIT ? OFFSET ?
+`
+
+type ListUsersIncludingDeletedParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListUsersIncludingDeleted(ctx context.Context, arg ListUsersIncludingDeletedParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersIncludingDeleted, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Age,
+			&i.Random,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ActivatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersIncludingDeletedAfter = `-- name: ListUsersIncludingDeletedAfter :many
+SELECT id, name, age, random, created_at, updated_at, deleted_at, activated_at FROM ` + "`" + `users` + "`" + ` WHERE ` + "`" + `id` + "`" + ` > ?
+ORDER BY ` + "`" + `id` + "`" + ` LIMIT ?
+`
+
+type ListUsersIncludingDeletedAfterParams struct {
+	ID    int32
+	Limit int32
+}
+
+func (q *Queries) ListUsersIncludingDeletedAfter(ctx context.Context, arg ListUsersIncludingDeletedAfterParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersIncludingDeletedAfter, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Age,
+			&i.Random,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ActivatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, created_at, delivered_at FROM ` + "`" + `webhook_deliveries` + "`" + ` WHERE ` + "`" + `subscription_id` + "`" + ` = ?
+ORDER BY ` + "`" + `id` + "`" + ` DESC LIMIT ?
+`
+
+type ListWebhookDeliveriesParams struct {
+	SubscriptionID int32
+	Limit          int32
+}
+
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookDeliveries, arg.SubscriptionID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.ResponseStatus,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookSubscriptions = `-- name: ListWebhookSubscriptions :many
+SELECT id, url, event_types, secret, created_at, deleted_at FROM ` + "`" + `webhook_subscriptions` + "`" + ` WHERE ` + "`" + `deleted_at` + "`" + ` IS NULL ORDER BY ` + "`" + `id` + "`" + `
+`
+
+func (q *Queries) ListWebhookSubscriptions(ctx context.Context) ([]WebhookSubscription, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookSubscriptions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookSubscription
+	for rows.Next() {
+		var i WebhookSubscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.EventTypes,
+			&i.Secret,
+			&i.CreatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxEventDelivered = `-- name: MarkOutboxEventDelivered :exec
+UPDATE ` + "`" + `outbox_events` + "`" + ` SET ` + "`" + `status` + "`" + ` = 'delivered', ` + "`" + `attempts` + "`" + ` = ?, ` + "`" + `delivered_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+type MarkOutboxEventDeliveredParams struct {
+	Attempts    int32
+	DeliveredAt sql.NullTime
+	ID          int64
+}
+
+func (q *Queries) MarkOutboxEventDelivered(ctx context.Context, arg MarkOutboxEventDeliveredParams) error {
+	_, err := q.db.ExecContext(ctx, markOutboxEventDelivered, arg.Attempts, arg.DeliveredAt, arg.ID)
+	return err
+}
+
+const markWebhookDeliveryFailed = `-- name: MarkWebhookDeliveryFailed :exec
+UPDATE ` + "`" + `webhook_deliveries` + "`" + ` SET ` + "`" + `status` + "`" + ` = 'failed', ` + "`" + `attempts` + "`" + ` = ?, ` + "`" + `response_status` + "`" + ` = ?, ` + "`" + `last_error` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+type MarkWebhookDeliveryFailedParams struct {
+	Attempts       int32
+	ResponseStatus int32
+	LastError      string
+	ID             int64
+}
+
+func (q *Queries) MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markWebhookDeliveryFailed,
+		arg.Attempts,
+		arg.ResponseStatus,
+		arg.LastError,
+		arg.ID,
+	)
+	return err
+}
+
+const markWebhookDeliverySucceeded = `-- name: MarkWebhookDeliverySucceeded :exec
+UPDATE ` + "`" + `webhook_deliveries` + "`" + ` SET ` + "`" + `status` + "`" + ` = 'succeeded', ` + "`" + `attempts` + "`" + ` = ?, ` + "`" + `response_status` + "`" + ` = ?, ` + "`" + `delivered_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+type MarkWebhookDeliverySucceededParams struct {
+	Attempts       int32
+	ResponseStatus int32
+	DeliveredAt    sql.NullTime
+	ID             int64
+}
+
+func (q *Queries) MarkWebhookDeliverySucceeded(ctx context.Context, arg MarkWebhookDeliverySucceededParams) error {
+	_, err := q.db.ExecContext(ctx, markWebhookDeliverySucceeded,
+		arg.Attempts,
+		arg.ResponseStatus,
+		arg.DeliveredAt,
+		arg.ID,
+	)
+	return err
+}
+
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :execresult
+DELETE FROM ` + "`" + `users` + "`" + ` WHERE ` + "`" + `deleted_at` + "`" + ` IS NOT NULL AND ` + "`" + `deleted_at` + "`" + ` < ?
+`
+
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, deletedAt sql.NullTime) (sql.Result, error) {
+	return q.db.ExecContext(ctx, purgeDeletedUsers, deletedAt)
+}
+
+const restoreUser = `-- name: RestoreUser :execresult
+UPDATE ` + "`" + `users` + "`" + ` SET ` + "`" + `deleted_at` + "`" + ` = NULL, ` + "`" + `updated_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ? AND ` + "`" + `deleted_at` + "`" + ` IS NOT NULL
+`
+
+type RestoreUserParams struct {
+	UpdatedAt time.Time
+	ID        int32
+}
+
+func (q *Queries) RestoreUser(ctx context.Context, arg RestoreUserParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, restoreUser, arg.UpdatedAt, arg.ID)
+}
+
+const retryJob = `-- name: RetryJob :execresult
+UPDATE ` + "`" + `jobs` + "`" + ` SET ` + "`" + `status` + "`" + ` = 'queued', ` + "`" + `last_error` + "`" + ` = ?, ` + "`" + `run_at` + "`" + ` = ?, ` + "`" + `locked_until` + "`" + ` = NULL, ` + "`" + `updated_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ? AND ` + "`" + `attempts` + "`" + ` = ? AND ` + "`" + `status` + "`" + ` = 'running'
+`
+
+type RetryJobParams struct {
+	LastError string
+	RunAt     time.Time
+	UpdatedAt time.Time
+	ID        int64
+	Attempts  int32
+}
+
+func (q *Queries) RetryJob(ctx context.Context, arg RetryJobParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, retryJob,
+		arg.LastError,
+		arg.RunAt,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.Attempts,
+	)
+}
+
+const retryOutboxEvent = `-- name: RetryOutboxEvent :exec
+UPDATE ` + "`" + `outbox_events` + "`" + ` SET ` + "`" + `attempts` + "`" + ` = ?, ` + "`" + `last_error` + "`" + ` = ?, ` + "`" + `next_attempt_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+type RetryOutboxEventParams struct {
+	Attempts      int32
+	LastError     string
+	NextAttemptAt time.Time
+	ID            int64
+}
+
+func (q *Queries) RetryOutboxEvent(ctx context.Context, arg RetryOutboxEventParams) error {
+	_, err := q.db.ExecContext(ctx, retryOutboxEvent,
+		arg.Attempts,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.ID,
+	)
+	return err
+}
+
+const retryWebhookDelivery = `-- name: RetryWebhookDelivery :exec
+UPDATE ` + "`" + `webhook_deliveries` + "`" + ` SET ` + "`" + `attempts` + "`" + ` = ?, ` + "`" + `response_status` + "`" + ` = ?, ` + "`" + `last_error` + "`" + ` = ?, ` + "`" + `next_attempt_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+type RetryWebhookDeliveryParams struct {
+	Attempts       int32
+	ResponseStatus int32
+	LastError      string
+	NextAttemptAt  time.Time
+	ID             int64
+}
+
+func (q *Queries) RetryWebhookDelivery(ctx context.Context, arg RetryWebhookDeliveryParams) error {
+	_, err := q.db.ExecContext(ctx, retryWebhookDelivery,
+		arg.Attempts,
+		arg.ResponseStatus,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.ID,
+	)
+	return err
+}
+
+const saveAuditEvent = `-- name: SaveAuditEvent :exec
+INSERT INTO ` + "`" + `audit_events` + "`" + ` (
+    ` + "`" + `entity` + "`" + `, ` + "`" + `entity_id` + "`" + `, ` + "`" + `action` + "`" + `, ` + "`" + `actor` + "`" + `, ` + "`" + `request_id` + "`" + `, ` + "`" + `diff` + "`" + `, ` + "`" + `created_at` + "`" + `
+) VALUES ( ?, ?, ?, ?, ?, ?, ? )
+`
+
+type SaveAuditEventParams struct {
+	Entity    string
+	EntityID  string
+	Action    string
+	Actor     string
+	RequestID string
+	Diff      json.RawMessage
+	CreatedAt time.Time
+}
+
+// Audit
+func (q *Queries) SaveAuditEvent(ctx context.Context, arg SaveAuditEventParams) error {
+	_, err := q.db.ExecContext(ctx, saveAuditEvent,
+		arg.Entity,
+		arg.EntityID,
+		arg.Action,
+		arg.Actor,
+		arg.RequestID,
+		arg.Diff,
+		arg.CreatedAt,
+	)
+	return err
+}
+
 const saveBook = `-- name: SaveBook :execresult
 INSERT INTO ` + "`" + `books` + "`" + ` (
     ` + "`" + `title` + "`" + `, ` + "`" + `author` + "`" + `
@@ -53,19 +1286,370 @@ func (q *Queries) SaveBook(ctx context.Context, arg SaveBookParams) (sql.Result,
 	return q.db.ExecContext(ctx, saveBook, arg.Title, arg.Author)
 }
 
+const saveCacheInvalidation = `-- name: SaveCacheInvalidation :exec
+INSERT INTO ` + "`" + `cache_invalidations` + "`" + ` (
+    ` + "`" + `cache` + "`" + `, ` + "`" + `key` + "`" + `, ` + "`" + `created_at` + "`" + `
+) VALUES ( ?, ?, ? )
+`
+
+type SaveCacheInvalidationParams struct {
+	Cache     string
+	Key       string
+	CreatedAt time.Time
+}
+
+// Cache
+func (q *Queries) SaveCacheInvalidation(ctx context.Context, arg SaveCacheInvalidationParams) error {
+	_, err := q.db.ExecContext(ctx, saveCacheInvalidation, arg.Cache, arg.Key, arg.CreatedAt)
+	return err
+}
+
+const saveCardSyncRun = `-- name: SaveCardSyncRun :execresult
+INSERT INTO ` + "`" + `card_sync_runs` + "`" + ` (
+    ` + "`" + `status` + "`" + `, ` + "`" + `started_at` + "`" + `
+) VALUES ( 'running', ? )
+`
+
+func (q *Queries) SaveCardSyncRun(ctx context.Context, startedAt time.Time) (sql.Result, error) {
+	return q.db.ExecContext(ctx, saveCardSyncRun, startedAt)
+}
+
+const saveJob = `-- name: SaveJob :execresult
+INSERT INTO ` + "`" + `jobs` + "`" + ` (
+    ` + "`" + `type` + "`" + `, ` + "`" + `payload` + "`" + `, ` + "`" + `status` + "`" + `, ` + "`" + `attempts` + "`" + `, ` + "`" + `principal` + "`" + `, ` + "`" + `request_id` + "`" + `, ` + "`" + `run_at` + "`" + `, ` + "`" + `created_at` + "`" + `, ` + "`" + `updated_at` + "`" + `
+) VALUES ( ?, ?, 'queued', 0, ?, ?, ?, ?, ? )
+`
+
+type SaveJobParams struct {
+	Type      string
+	Payload   json.RawMessage
+	Principal json.RawMessage
+	RequestID string
+	RunAt     time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Jobs
+func (q *Queries) SaveJob(ctx context.Context, arg SaveJobParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, saveJob,
+		arg.Type,
+		arg.Payload,
+		arg.Principal,
+		arg.RequestID,
+		arg.RunAt,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+}
+
+const saveOutboxEvent = `-- name: SaveOutboxEvent :exec
+INSERT INTO ` + "`" + `outbox_events` + "`" + ` (
+    ` + "`" + `event_type` + "`" + `, ` + "`" + `aggregate_type` + "`" + `, ` + "`" + `aggregate_id` + "`" + `, ` + "`" + `payload` + "`" + `, ` + "`" + `status` + "`" + `, ` + "`" + `attempts` + "`" + `, ` + "`" + `next_attempt_at` + "`" + `, ` + "`" + `created_at` + "`" + `
+) VALUES ( ?, ?, ?, ?, 'pending', 0, ?, ? )
+`
+
+type SaveOutboxEventParams struct {
+	EventType     string
+	AggregateType string
+	AggregateID   string
+	Payload       json.RawMessage
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+}
+
+// Outbox
+func (q *Queries) SaveOutboxEvent(ctx context.Context, arg SaveOutboxEventParams) error {
+	_, err := q.db.ExecContext(ctx, saveOutboxEvent,
+		arg.EventType,
+		arg.AggregateType,
+		arg.AggregateID,
+		arg.Payload,
+		arg.NextAttemptAt,
+		arg.CreatedAt,
+	)
+	return err
+}
+
 const saveUser = `-- name: SaveUser :execresult
 INSERT INTO ` + "`" + `users` + "`" + ` (
-    ` + "`" + `name` + "`" + `, ` + "`" + `age` + "`" + `, ` + "`" + `random` + "`" + `
-) VALUES ( ?, ?, ? )
+    ` + "`" + `name` + "`" + `, ` + "`" + `age` + "`" + `, ` + "`" + `random` + "`" + `, ` + "`" + `created_at` + "`" + `, ` + "`" + `updated_at` + "`" + `
+) VALUES ( ?, ?, ?, ?, ? )
 `
 
 type SaveUserParams struct {
-	Name   string
-	Age    int32
-	Random string
+	Name      string
+	Age       int32
+	Random    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 // Users
 func (q *Queries) SaveUser(ctx context.Context, arg SaveUserParams) (sql.Result, error) {
-	return q.db.ExecContext(ctx, saveUser, arg.Name, arg.Age, arg.Random)
+	return q.db.ExecContext(ctx, saveUser,
+		arg.Name,
+		arg.Age,
+		arg.Random,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+}
+
+const saveUserImport = `-- name: SaveUserImport :execresult
+INSERT INTO ` + "`" + `user_imports` + "`" + ` (
+    ` + "`" + `format` + "`" + `, ` + "`" + `status` + "`" + `, ` + "`" + `created_by` + "`" + `, ` + "`" + `error_report` + "`" + `, ` + "`" + `created_at` + "`" + `, ` + "`" + `updated_at` + "`" + `
+) VALUES ( ?, 'pending', ?, '', ?, ? )
+`
+
+type SaveUserImportParams struct {
+	Format    string
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// User imports
+func (q *Queries) SaveUserImport(ctx context.Context, arg SaveUserImportParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, saveUserImport,
+		arg.Format,
+		arg.CreatedBy,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+}
+
+const saveUserImportPart = `-- name: SaveUserImportPart :exec
+INSERT INTO ` + "`" + `user_import_parts` + "`" + ` (
+    ` + "`" + `import_id` + "`" + `, ` + "`" + `part` + "`" + `, ` + "`" + `content` + "`" + `
+) VALUES ( ?, ?, ? )
+`
+
+type SaveUserImportPartParams struct {
+	ImportID int32
+	Part     int32
+	Content  []byte
+}
+
+func (q *Queries) SaveUserImportPart(ctx context.Context, arg SaveUserImportPartParams) error {
+	_, err := q.db.ExecContext(ctx, saveUserImportPart, arg.ImportID, arg.Part, arg.Content)
+	return err
+}
+
+const saveWebhookDelivery = `-- name: SaveWebhookDelivery :execresult
+INSERT INTO ` + "`" + `webhook_deliveries` + "`" + ` (
+    ` + "`" + `subscription_id` + "`" + `, ` + "`" + `event_id` + "`" + `, ` + "`" + `event_type` + "`" + `, ` + "`" + `payload` + "`" + `, ` + "`" + `status` + "`" + `, ` + "`" + `attempts` + "`" + `, ` + "`" + `next_attempt_at` + "`" + `, ` + "`" + `created_at` + "`" + `
+) VALUES ( ?, ?, ?, ?, 'pending', 0, ?, ? )
+`
+
+type SaveWebhookDeliveryParams struct {
+	SubscriptionID int32
+	EventID        int64
+	EventType      string
+	Payload        json.RawMessage
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+}
+
+func (q *Queries) SaveWebhookDelivery(ctx context.Context, arg SaveWebhookDeliveryParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, saveWebhookDelivery,
+		arg.SubscriptionID,
+		arg.EventID,
+		arg.EventType,
+		arg.Payload,
+		arg.NextAttemptAt,
+		arg.CreatedAt,
+	)
+}
+
+const saveWebhookSubscription = `-- name: SaveWebhookSubscription :execresult
+INSERT INTO ` + "`" + `webhook_subscriptions` + "`" + ` (
+    ` + "`" + `url` + "`" + `, ` + "`" + `event_types` + "`" + `, ` + "`" + `secret` + "`" + `, ` + "`" + `created_at` + "`" + `
+) VALUES ( ?, ?, ?, ? )
+`
+
+type SaveWebhookSubscriptionParams struct {
+	Url        string
+	EventTypes json.RawMessage
+	Secret     string
+	CreatedAt  time.Time
+}
+
+// Webhooks
+func (q *Queries) SaveWebhookSubscription(ctx context.Context, arg SaveWebhookSubscriptionParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, saveWebhookSubscription,
+		arg.Url,
+		arg.EventTypes,
+		arg.Secret,
+		arg.CreatedAt,
+	)
+}
+
+const searchUsersAndBooks = `-- name: SearchUsersAndBooks :many
+SELECT 'user' AS ` + "`" + `type` + "`" + `, ` + "`" + `id` + "`" + `, ` + "`" + `name` + "`" + ` AS ` + "`" + `text` + "`" + `, MATCH(` + "`" + `name` + "`" + `) AGAINST (? IN NATURAL LANGUAGE MODE) AS ` + "`" + `score` + "`" + `
+FROM ` + "`" + `users` + "`" + ` WHERE ` + "`" + `deleted_at` + "`" + ` IS NULL AND (? OR ` + "`" + `id` + "`" + ` = ?) AND MATCH(` + "`" + `name` + "`" + `) AGAINST (? IN NATURAL LANGUAGE MODE)
+UNION ALL
+SELECT 'book' AS ` + "`" + `type` + "`" + `, ` + "`" + `id` + "`" + `, ` + "`" + `title` + "`" + ` AS ` + "`" + `text` + "`" + `, MATCH(` + "`" + `title` + "`" + `) AGAINST (? IN NATURAL LANGUAGE MODE) AS ` + "`" + `score` + "`" + `
+FROM ` + "`" + `books` + "`" + ` WHERE MATCH(` + "`" + `title` + "`" + `) AGAINST (? IN NATURAL LANGUAGE MODE)
+ORDER BY ` + "`" + `score` + "`" + ` DESC LIMIT ?
+`
+
+type SearchUsersAndBooksParams struct {
+	Query    string
+	AllUsers bool
+	UserID   int32
+	Limit    int32
+}
+
+type SearchUsersAndBooksRow struct {
+	Type  string
+	ID    int32
+	Text  string
+	Score float64
+}
+
+// Search
+func (q *Queries) SearchUsersAndBooks(ctx context.Context, arg SearchUsersAndBooksParams) ([]SearchUsersAndBooksRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchUsersAndBooks,
+		arg.Query,
+		arg.AllUsers,
+		arg.UserID,
+		arg.Query,
+		arg.Query,
+		arg.Query,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchUsersAndBooksRow
+	for rows.Next() {
+		var i SearchUsersAndBooksRow
+		if err := rows.Scan(
+			&i.Type,
+			&i.ID,
+			&i.Text,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :execresult
+UPDATE ` + "`" + `users` + "`" + ` SET ` + "`" + `deleted_at` + "`" + ` = ?, ` + "`" + `updated_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ? AND ` + "`" + `deleted_at` + "`" + ` IS NULL
+`
+
+type SoftDeleteUserParams struct {
+	DeletedAt sql.NullTime
+	UpdatedAt time.Time
+	ID        int32
+}
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, arg SoftDeleteUserParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, softDeleteUser, arg.DeletedAt, arg.UpdatedAt, arg.ID)
+}
+
+const unassignCard = `-- name: UnassignCard :execresult
+DELETE FROM ` + "`" + `user_cards` + "`" + ` WHERE ` + "`" + `user_id` + "`" + ` = ? AND ` + "`" + `card_id` + "`" + ` = ?
+`
+
+type UnassignCardParams struct {
+	UserID int32
+	CardID int32
+}
+
+func (q *Queries) UnassignCard(ctx context.Context, arg UnassignCardParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, unassignCard, arg.UserID, arg.CardID)
+}
+
+const updateUser = `-- name: UpdateUser :execresult
+UPDATE ` + "`" + `users` + "`" + ` SET ` + "`" + `name` + "`" + ` = ?, ` + "`" + `age` + "`" + ` = ?, ` + "`" + `updated_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ? AND ` + "`" + `deleted_at` + "`" + ` IS NULL
+`
+
+type UpdateUserParams struct {
+	Name      string
+	Age       int32
+	UpdatedAt time.Time
+	ID        int32
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateUser,
+		arg.Name,
+		arg.Age,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+}
+
+const updateUserImportProgress = `-- name: UpdateUserImportProgress :exec
+UPDATE ` + "`" + `user_imports` + "`" + ` SET ` + "`" + `status` + "`" + ` = 'running', ` + "`" + `processed_rows` + "`" + ` = ?, ` + "`" + `failed_rows` + "`" + ` = ?, ` + "`" + `progress` + "`" + ` = ?, ` + "`" + `updated_at` + "`" + ` = ?
+WHERE ` + "`" + `id` + "`" + ` = ?
+`
+
+type UpdateUserImportProgressParams struct {
+	ProcessedRows int32
+	FailedRows    int32
+	Progress      int32
+	UpdatedAt     time.Time
+	ID            int32
+}
+
+func (q *Queries) UpdateUserImportProgress(ctx context.Context, arg UpdateUserImportProgressParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserImportProgress,
+		arg.ProcessedRows,
+		arg.FailedRows,
+		arg.Progress,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const upsertCard = `-- name: UpsertCard :exec
+INSERT INTO ` + "`" + `cards` + "`" + ` (
+    ` + "`" + `id` + "`" + `, ` + "`" + `name` + "`" + `, ` + "`" + `status` + "`" + `, ` + "`" + `species` + "`" + `, ` + "`" + `gender` + "`" + `, ` + "`" + `image` + "`" + `, ` + "`" + `hash` + "`" + `, ` + "`" + `created_at` + "`" + `, ` + "`" + `updated_at` + "`" + `
+) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ? )
+ON DUPLICATE KEY UPDATE
+    ` + "`" + `name` + "`" + ` = VALUES(` + "`" + `name` + "`" + `), ` + "`" + `status` + "`" + ` = VALUES(` + "`" + `status` + "`" + `), ` + "`" + `species` + "`" + ` = VALUES(` + "`" + `species` + "`" + `), ` + "`" + `gender` + "`" + ` = VALUES(` + "`" + `gender` + "`" + `),
+    ` + "`" + `image` + "`" + ` = VALUES(` + "`" + `image` + "`" + `), ` + "`" + `hash` + "`" + ` = VALUES(` + "`" + `hash` + "`" + `), ` + "`" + `updated_at` + "`" + ` = VALUES(` + "`" + `updated_at` + "`" + `)
+`
+
+type UpsertCardParams struct {
+	ID        int32
+	Name      string
+	Status    string
+	Species   string
+	Gender    string
+	Image     string
+	Hash      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) UpsertCard(ctx context.Context, arg UpsertCardParams) error {
+	_, err := q.db.ExecContext(ctx, upsertCard,
+		arg.ID,
+		arg.Name,
+		arg.Status,
+		arg.Species,
+		arg.Gender,
+		arg.Image,
+		arg.Hash,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	return err
 }
@@ -0,0 +1,22 @@
+package database
+
+import (
+	"strings"
+)
+
+// namePrefix starts the marker sqlc puts at the top of each query.
+const namePrefix = "-- name: "
+
+// queryName returns the sqlc name of query, e.g. FindUser, or unknown when it has none.
+func queryName(query string) string {
+	if !strings.HasPrefix(query, namePrefix) {
+		return "unknown"
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(query, namePrefix))
+	if len(fields) == 0 {
+		return "unknown"
+	}
+
+	return fields[0]
+}
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		expectedName string
+	}{
+		{
+			name:         "query name from sqlc marker",
+			query:        restoreUser,
+			expectedName: "RestoreUser",
+		},
+		{
+			name:         "query name unknown",
+			query:        "SELECT 1",
+			expectedName: "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			name := queryName(tt.query)
+
+			// then
+			require.Equal(t, tt.expectedName, name)
+		})
+	}
+}
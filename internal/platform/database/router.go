@@ -0,0 +1,186 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// NewRouter sends the reads outside transactions to replicas, in turns, and the writes and
+// transactions to primary. The reads of the Session in their context stay on the primary for
+// stickyWindow after it writes, so that it reads its writes despite the replication lag,
+// the statements without a Session never stick. The replicas that fail their health checks
+// or return bad connections are skipped until they recover, falling back to the primary when
+// none is left.
+func NewRouter(primary DBTX, replicas []DBTX, stickyWindow time.Duration) *router {
+	r := &router{
+		primary:      primary,
+		stickyWindow: stickyWindow,
+		now:          time.Now,
+	}
+	for _, db := range replicas {
+		r.replicas = append(r.replicas, &replica{db: db, healthy: 1})
+	}
+
+	return r
+}
+
+type router struct {
+	primary      DBTX
+	replicas     []*replica
+	next         uint32
+	stickyWindow time.Duration
+	now          func() time.Time
+}
+
+type replica struct {
+	db      DBTX
+	healthy int32
+}
+
+func (r *router) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	r.written(ctx)
+	return r.primary.ExecContext(ctx, query, args...)
+}
+
+func (r *router) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return r.primary.PrepareContext(ctx, query)
+}
+
+func (r *router) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if rep := r.replica(ctx); rep != nil {
+		rows, err := rep.db.QueryContext(ctx, query, args...)
+		if !r.failed(ctx, rep, err) {
+			return rows, err
+		}
+	}
+
+	return r.primary.QueryContext(ctx, query, args...)
+}
+
+func (r *router) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if rep := r.replica(ctx); rep != nil {
+		row := rep.db.QueryRowContext(ctx, query, args...)
+		if !r.failed(ctx, rep, row.Err()) {
+			return row
+		}
+	}
+
+	return r.primary.QueryRowContext(ctx, query, args...)
+}
+
+// Unwrap returns the primary, where the transactions are begun.
+func (r *router) Unwrap() DBTX {
+	return r.primary
+}
+
+// WrapTx sends every statement of tx to the primary, its writes make the session sticky too.
+func (r *router) WrapTx(tx DBTX) DBTX {
+	return &routedTx{
+		tx,
+		r,
+	}
+}
+
+type routedTx struct {
+	DBTX
+	router *router
+}
+
+func (t *routedTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	t.router.written(ctx)
+	return t.DBTX.ExecContext(ctx, query, args...)
+}
+
+// Run checks the health of the replicas once per interval until ctx is done.
+func (r *router) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.CheckHealth(ctx)
+		}
+	}
+}
+
+// CheckHealth pings the replicas, the ones that answer are used again.
+func (r *router) CheckHealth(ctx context.Context) {
+	for i, rep := range r.replicas {
+		p, ok := rep.db.(pinger)
+		if !ok {
+			continue
+		}
+
+		if err := p.PingContext(ctx); err != nil {
+			if atomic.SwapInt32(&rep.healthy, 0) == 1 {
+				logging.Warn(ctx, "database replica unhealthy", log.Int("replica", i), log.Err(err))
+			}
+			continue
+		}
+
+		if atomic.SwapInt32(&rep.healthy, 1) == 0 {
+			logging.Info(ctx, "database replica recovered", log.Int("replica", i))
+		}
+	}
+}
+
+// replica picks the replica that serves a read in ctx, it is nil when the read must go to
+// the primary.
+func (r *router) replica(ctx context.Context) *replica {
+	if len(r.replicas) == 0 || r.sticky(ctx) {
+		return nil
+	}
+
+	start := atomic.AddUint32(&r.next, 1)
+	for i := range r.replicas {
+		rep := r.replicas[(int(start)+i)%len(r.replicas)]
+		if atomic.LoadInt32(&rep.healthy) == 1 {
+			return rep
+		}
+	}
+
+	return nil
+}
+
+// failed tells whether the read must be retried on the primary, marking rep as unhealthy
+// when its connection is broken.
+func (r *router) failed(ctx context.Context, rep *replica, err error) bool {
+	if !errors.Is(err, driver.ErrBadConn) {
+		return false
+	}
+
+	if atomic.SwapInt32(&rep.healthy, 0) == 1 {
+		logging.Warn(ctx, "database replica unhealthy", log.Err(err))
+	}
+
+	return true
+}
+
+func (r *router) written(ctx context.Context) {
+	if session, ok := sessionFrom(ctx); ok && r.stickyWindow > 0 {
+		session.stick(r.now().Add(r.stickyWindow))
+	}
+}
+
+func (r *router) sticky(ctx context.Context) bool {
+	if primaryFrom(ctx) {
+		return true
+	}
+
+	session, ok := sessionFrom(ctx)
+	return ok && r.now().Before(session.PrimaryUntil())
+}
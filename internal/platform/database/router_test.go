@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// routedDB records the statements routed to it.
+type routedDB struct {
+	DBTX
+	name    string
+	calls   *[]string
+	err     error
+	pingErr error
+}
+
+func (d routedDB) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	*d.calls = append(*d.calls, d.name+":exec")
+	return nil, nil
+}
+
+func (d routedDB) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	*d.calls = append(*d.calls, d.name+":query")
+	return nil, d.err
+}
+
+func (d routedDB) PingContext(context.Context) error {
+	return d.pingErr
+}
+
+func TestRouter(t *testing.T) {
+	// the sessions are new for each test, see below
+	var alice, bob context.Context
+	job := context.Background()
+
+	tests := []struct {
+		name          string
+		replicaErr    error
+		pingErr       error
+		run           func(r *router, now *time.Time)
+		expectedCalls []string
+	}{
+		{
+			name: "router sends reads to replicas in turns",
+			run: func(r *router, now *time.Time) {
+				_, _ = r.QueryContext(alice, listUsers)
+				_, _ = r.QueryContext(alice, listUsers)
+				_, _ = r.QueryContext(alice, listUsers)
+			},
+			expectedCalls: []string{"replica1:query", "replica0:query", "replica1:query"},
+		},
+		{
+			name: "router sticks the session to the primary after a write",
+			run: func(r *router, now *time.Time) {
+				_, _ = r.ExecContext(alice, updateUser)
+				_, _ = r.QueryContext(alice, listUsers)
+				_, _ = r.QueryContext(bob, listUsers)
+				*now = now.Add(time.Second)
+				_, _ = r.QueryContext(alice, listUsers)
+			},
+			expectedCalls: []string{"primary:exec", "primary:query", "replica1:query", "replica0:query"},
+		},
+		{
+			name: "router doesn't stick the statements without a session",
+			run: func(r *router, now *time.Time) {
+				_, _ = r.ExecContext(job, updateUser)
+				_, _ = r.QueryContext(job, listUsers)
+			},
+			expectedCalls: []string{"primary:exec", "replica1:query"},
+		},
+		{
+			name: "router sticks the sessions marked by a previous request",
+			run: func(r *router, now *time.Time) {
+				_, _ = r.QueryContext(WithSession(context.Background(), NewSession(now.Add(time.Second))), listUsers)
+			},
+			expectedCalls: []string{"primary:query"},
+		},
+		{
+			name: "router sends the reads forced to the primary there",
+			run: func(r *router, now *time.Time) {
+				_, _ = r.QueryContext(WithPrimary(job), listUsers)
+				_, _ = r.QueryContext(WithPrimary(alice), listUsers)
+				_, _ = r.QueryContext(alice, listUsers)
+			},
+			expectedCalls: []string{"primary:query", "primary:query", "replica1:query"},
+		},
+		{
+			name:       "router fails over to the primary on bad connections",
+			replicaErr: driver.ErrBadConn,
+			run: func(r *router, now *time.Time) {
+				_, _ = r.QueryContext(alice, listUsers)
+				_, _ = r.QueryContext(alice, listUsers)
+				_, _ = r.QueryContext(alice, listUsers)
+			},
+			expectedCalls: []string{"replica1:query", "primary:query", "replica0:query", "primary:query", "primary:query"},
+		},
+		{
+			name:    "router skips the replicas that fail their health checks",
+			pingErr: errors.New("connection refused"),
+			run: func(r *router, now *time.Time) {
+				r.CheckHealth(context.Background())
+				_, _ = r.QueryContext(alice, listUsers)
+			},
+			expectedCalls: []string{"primary:query"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			alice = WithSession(context.Background(), NewSession(time.Time{}))
+			bob = WithSession(context.Background(), NewSession(time.Time{}))
+
+			var calls []string
+			now := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+
+			primary := routedDB{name: "primary", calls: &calls}
+			replicas := []DBTX{
+				routedDB{name: "replica0", calls: &calls, err: tt.replicaErr, pingErr: tt.pingErr},
+				routedDB{name: "replica1", calls: &calls, err: tt.replicaErr, pingErr: tt.pingErr},
+			}
+
+			r := NewRouter(primary, replicas, time.Second)
+			r.now = func() time.Time { return now }
+
+			// when
+			tt.run(r, &now)
+
+			// then
+			require.Equal(t, tt.expectedCalls, calls)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Session is the read-your-writes marker of a client. Its reads go to the primary until
+// PrimaryUntil, which its writes push forward. It travels with the client between its
+// requests, so that every instance of the API honors it.
+type Session struct {
+	mu           sync.Mutex
+	primaryUntil time.Time
+}
+
+func NewSession(primaryUntil time.Time) *Session {
+	return &Session{
+		primaryUntil: primaryUntil,
+	}
+}
+
+// PrimaryUntil is when the reads of the session can go back to the replicas.
+func (s *Session) PrimaryUntil() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.primaryUntil
+}
+
+func (s *Session) stick(until time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if until.After(s.primaryUntil) {
+		s.primaryUntil = until
+	}
+}
+
+type sessionKey struct{}
+
+// WithSession routes the statements run with the returned context as the ones of session.
+func WithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+func sessionFrom(ctx context.Context) (*Session, bool) {
+	session, ok := ctx.Value(sessionKey{}).(*Session)
+	return session, ok
+}
+
+type primaryKey struct{}
+
+// WithPrimary sends the reads run with the returned context to the primary, session or not,
+// for the reads whose result outlives the replication lag, like the cache fills.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func primaryFrom(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/johan-ag/testing/internal/platform/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewTracedDB traces the queries run through db in spans named after them.
+func NewTracedDB(db DBTX) *tracedDB {
+	return &tracedDB{
+		db,
+	}
+}
+
+type tracedDB struct {
+	db DBTX
+}
+
+func (t *tracedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (result sql.Result, err error) {
+	ctx, span := start(ctx, query)
+	defer func() { tracing.End(span, err) }()
+
+	return t.db.ExecContext(ctx, query, args...)
+}
+
+func (t *tracedDB) PrepareContext(ctx context.Context, query string) (stmt *sql.Stmt, err error) {
+	ctx, span := start(ctx, query)
+	defer func() { tracing.End(span, err) }()
+
+	return t.db.PrepareContext(ctx, query)
+}
+
+func (t *tracedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	ctx, span := start(ctx, query)
+	defer func() { tracing.End(span, err) }()
+
+	return t.db.QueryContext(ctx, query, args...)
+}
+
+func (t *tracedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := start(ctx, query)
+	row := t.db.QueryRowContext(ctx, query, args...)
+	tracing.End(span, row.Err())
+
+	return row
+}
+
+func (t *tracedDB) Unwrap() DBTX {
+	return t.db
+}
+
+func (t *tracedDB) WrapTx(tx DBTX) DBTX {
+	return NewTracedDB(tx)
+}
+
+func start(ctx context.Context, query string) (context.Context, trace.Span) {
+	name := queryName(query)
+
+	return tracing.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMySQL,
+			semconv.DBOperationKey.String(name),
+			attribute.String("db.statement", query),
+		),
+	)
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type fakeDB struct {
+	DBTX
+}
+
+func (f fakeDB) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+func TestTracedDB(t *testing.T) {
+	// given
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "users.Service/Restore")
+	queries := New(NewTracedDB(fakeDB{}))
+
+	// when
+	_, err := queries.RestoreUser(ctx, RestoreUserParams{ID: 1})
+	parent.End()
+
+	// then
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, "RestoreUser", spans[0].Name())
+	require.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	require.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	require.Equal(t, parent.SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+}
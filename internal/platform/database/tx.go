@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// wrapper is a DBTX that decorates another one, such as the traced one. Transactions are
+// begun on the wrapped DBTX and decorated the same way.
+type wrapper interface {
+	Unwrap() DBTX
+	WrapTx(tx DBTX) DBTX
+}
+
+// ExecTx runs fn with queries bound to a new transaction, committing it when fn succeeds
+// and rolling it back otherwise. When q is already bound to a transaction fn joins it.
+func (q *Queries) ExecTx(ctx context.Context, fn func(*Queries) error) error {
+	db, ok := beginnerOf(q.db)
+	if !ok {
+		return fn(q)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(New(wrapTx(q.db, tx))); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w: rollback: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// beginnerOf finds the DBTX that begins the transactions under the wrappers of db.
+func beginnerOf(db DBTX) (beginner, bool) {
+	for {
+		if b, ok := db.(beginner); ok {
+			return b, true
+		}
+
+		w, ok := db.(wrapper)
+		if !ok {
+			return nil, false
+		}
+		db = w.Unwrap()
+	}
+}
+
+// wrapTx decorates tx with the wrappers of db.
+func wrapTx(db DBTX, tx *sql.Tx) DBTX {
+	w, ok := db.(wrapper)
+	if !ok {
+		return tx
+	}
+
+	return w.WrapTx(wrapTx(w.Unwrap(), tx))
+}
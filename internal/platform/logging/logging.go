@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+type fieldsKey struct{}
+
+// Fields every line logged through ctx carries.
+const (
+	RequestIDField = "request_id"
+	TraceIDField   = "trace_id"
+	SubjectField   = "subject"
+)
+
+// WithFields returns a copy of ctx whose logger adds fields to every line, after the
+// ones already in ctx.
+func WithFields(ctx context.Context, fields ...log.Field) context.Context {
+	current := Fields(ctx)
+	merged := make([]log.Field, 0, len(current)+len(fields))
+	merged = append(merged, current...)
+	merged = append(merged, fields...)
+
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// Fields returns the fields of the logger in ctx.
+func Fields(ctx context.Context) []log.Field {
+	fields, _ := ctx.Value(fieldsKey{}).([]log.Field)
+	return fields
+}
+
+func Debug(ctx context.Context, msg string, fields ...log.Field) {
+	log.Debug(ctx, msg, with(ctx, fields)...)
+}
+
+func Info(ctx context.Context, msg string, fields ...log.Field) {
+	log.Info(ctx, msg, with(ctx, fields)...)
+}
+
+func Warn(ctx context.Context, msg string, fields ...log.Field) {
+	log.Warn(ctx, msg, with(ctx, fields)...)
+}
+
+func Error(ctx context.Context, msg string, fields ...log.Field) {
+	log.Error(ctx, msg, with(ctx, fields)...)
+}
+
+func with(ctx context.Context, fields []log.Field) []log.Field {
+	current := Fields(ctx)
+	if len(current) == 0 {
+		return fields
+	}
+
+	return append(append(make([]log.Field, 0, len(current)+len(fields)), current...), fields...)
+}
@@ -0,0 +1,22 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFields(t *testing.T) {
+	// given
+	ctx := WithFields(context.Background(), log.String(RequestIDField, "abc"))
+
+	// when
+	child := WithFields(ctx, log.String(SubjectField, "1"))
+
+	// then
+	require.Equal(t, []log.Field{log.String(RequestIDField, "abc")}, Fields(ctx))
+	require.Equal(t, []log.Field{log.String(RequestIDField, "abc"), log.String(SubjectField, "1")}, Fields(child))
+	require.Equal(t, []log.Field{log.String(RequestIDField, "abc"), log.Int("n", 1)}, with(ctx, []log.Field{log.Int("n", 1)}))
+}
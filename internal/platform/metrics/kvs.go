@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mercadolibre/fury_go-toolkit-kvs/pkg/kvs"
+)
+
+// layerKVS is the layer of the KVS calls.
+const layerKVS = "kvs"
+
+// Results of the KVS lookups.
+const (
+	ResultHit  = "hit"
+	ResultMiss = "miss"
+)
+
+// NewKVSClient counts the hits and misses of the lookups to container through client, and
+// records the bulk calls and ranges along with the service and repository calls.
+func NewKVSClient(container string, client kvs.QueryableClient) *kvsClient {
+	return &kvsClient{
+		client,
+		container,
+	}
+}
+
+type kvsClient struct {
+	kvs.QueryableClient
+	container string
+}
+
+func (c *kvsClient) Get(ctx context.Context, key string) (kvs.Item, error) {
+	item, err := c.QueryableClient.Get(ctx, key)
+	switch {
+	case errors.Is(err, kvs.ErrKeyNotFound):
+		kvsLookups.WithLabelValues(c.container, ResultMiss).Inc()
+	case err != nil:
+		kvsLookups.WithLabelValues(c.container, OutcomeError).Inc()
+	default:
+		kvsLookups.WithLabelValues(c.container, ResultHit).Inc()
+	}
+
+	return item, err
+}
+
+func (c *kvsClient) BatchGet(ctx context.Context, keys []string) (map[string]kvs.Item, error) {
+	items, err := c.QueryableClient.BatchGet(ctx, keys)
+	if err != nil {
+		kvsLookups.WithLabelValues(c.container, OutcomeError).Add(float64(len(keys)))
+		return items, err
+	}
+
+	kvsLookups.WithLabelValues(c.container, ResultHit).Add(float64(len(items)))
+	kvsLookups.WithLabelValues(c.container, ResultMiss).Add(float64(len(keys) - len(items)))
+
+	return items, nil
+}
+
+func (c *kvsClient) BulkGet(ctx context.Context, keys []string) (kvs.Bulk, error) {
+	start := time.Now()
+	bulk, err := c.QueryableClient.BulkGet(ctx, keys)
+	ObserveCall(layerKVS, c.container, "BulkGet", start, err)
+	if err != nil {
+		kvsLookups.WithLabelValues(c.container, OutcomeError).Add(float64(len(keys)))
+		return bulk, err
+	}
+
+	found := len(bulk.Items())
+	kvsLookups.WithLabelValues(c.container, ResultHit).Add(float64(found))
+	kvsLookups.WithLabelValues(c.container, ResultMiss).Add(float64(len(keys) - found))
+
+	return bulk, nil
+}
+
+func (c *kvsClient) BulkSet(ctx context.Context, items []kvs.Item) (kvs.Bulk, error) {
+	start := time.Now()
+	bulk, err := c.QueryableClient.BulkSet(ctx, items)
+	ObserveCall(layerKVS, c.container, "BulkSet", start, err)
+
+	return bulk, err
+}
+
+func (c *kvsClient) Range(ctx context.Context, query kvs.Query, fn func([]kvs.Item, error) error) error {
+	start := time.Now()
+	err := c.QueryableClient.Range(ctx, query, fn)
+	ObserveCall(layerKVS, c.container, "Range", start, err)
+
+	return err
+}
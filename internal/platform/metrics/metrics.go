@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Outcomes of the instrumented calls.
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Registry holds every metric of the application.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests served by route and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of the HTTP requests by route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	grpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_requests_total",
+		Help: "gRPC calls served by method and status code.",
+	}, []string{"method", "code"})
+
+	grpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_request_duration_seconds",
+		Help:    "Latency of the gRPC calls by method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+
+	calls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "calls_total",
+		Help: "Calls to the service and repository methods and to KVS by outcome.",
+	}, []string{"layer", "component", "method", "outcome"})
+
+	callDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "call_duration_seconds",
+		Help:    "Latency of the service and repository methods and of KVS.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"layer", "component", "method"})
+
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Latency of the database queries by sqlc name.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"query"})
+
+	queryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Failed database queries by sqlc name and error type.",
+	}, []string{"query", "type"})
+
+	kvsLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kvs_lookups_total",
+		Help: "KVS keys looked up by container and result, either hit, miss or error.",
+	}, []string{"container", "result"})
+
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_lookups_total",
+		Help: "Cache lookups by cache, tier and result, either hit or miss.",
+	}, []string{"cache", "tier", "result"})
+
+	cacheHitRatio = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cache_hit_ratio",
+		Help: "Share of the lookups of each cache tier that hit since the process started.",
+	}, []string{"cache", "tier"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		grpcRequests,
+		grpcDuration,
+		calls,
+		callDuration,
+		queryDuration,
+		queryErrors,
+		kvsLookups,
+		cacheLookups,
+		cacheHitRatio,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB exposes the connection pool stats of db as gauges.
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveRequest records a served HTTP request.
+func ObserveRequest(method, route string, status int, duration time.Duration) {
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveRPC records a served gRPC call, code is the name of its status code.
+func ObserveRPC(method, code string, duration time.Duration) {
+	grpcRequests.WithLabelValues(method, code).Inc()
+	grpcDuration.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+// ObserveCall records a call to method of component, started at start and failed with err.
+func ObserveCall(layer, component, method string, start time.Time, err error) {
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	}
+
+	calls.WithLabelValues(layer, component, method, outcome).Inc()
+	callDuration.WithLabelValues(layer, component, method).Observe(time.Since(start).Seconds())
+}
+
+// ObserveQuery records a database query, errorType is empty when it succeeded.
+func ObserveQuery(query string, duration time.Duration, errorType string) {
+	queryDuration.WithLabelValues(query).Observe(duration.Seconds())
+	if errorType != "" {
+		queryErrors.WithLabelValues(query, errorType).Inc()
+	}
+}
+
+// ObserveCacheLookup records a lookup to tier of cache, hitRatio is the one of the tier
+// including it.
+func ObserveCacheLookup(cache, tier, result string, hitRatio float64) {
+	cacheLookups.WithLabelValues(cache, tier, result).Inc()
+	cacheHitRatio.WithLabelValues(cache, tier).Set(hitRatio)
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockkvs "github.com/johan-ag/testing/internal/platform/kvs"
+	"github.com/mercadolibre/fury_go-toolkit-kvs/pkg/kvs"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVSClientGet(t *testing.T) {
+	// given
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	qkvs := mockkvs.NewMockQueryableClient(ctrl)
+	qkvs.EXPECT().Get(gomock.Eq(ctx), gomock.Eq("found")).Return(kvs.Item{Key: "found"}, nil)
+	qkvs.EXPECT().Get(gomock.Eq(ctx), gomock.Eq("missing")).Return(kvs.Item{}, kvs.ErrKeyNotFound)
+	qkvs.EXPECT().Get(gomock.Eq(ctx), gomock.Eq("broken")).Return(kvs.Item{}, errors.New("timeout"))
+
+	client := NewKVSClient("test", qkvs)
+	lookups := func(result string) float64 {
+		return testutil.ToFloat64(kvsLookups.WithLabelValues("test", result))
+	}
+	hits, misses, errs := lookups(ResultHit), lookups(ResultMiss), lookups(OutcomeError)
+
+	// when
+	_, _ = client.Get(ctx, "found")
+	_, _ = client.Get(ctx, "missing")
+	_, _ = client.Get(ctx, "broken")
+
+	// then
+	require.Equal(t, hits+1, lookups(ResultHit))
+	require.Equal(t, misses+1, lookups(ResultMiss))
+	require.Equal(t, errs+1, lookups(OutcomeError))
+}
+
+func TestKVSClientBulkGet(t *testing.T) {
+	// given
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	qkvs := mockkvs.NewMockQueryableClient(ctrl)
+	qkvs.EXPECT().
+		BulkGet(gomock.Eq(ctx), gomock.Eq([]string{"a", "b"})).
+		DoAndReturn(func(context.Context, []string) (kvs.Bulk, error) {
+			var bulk kvs.Bulk
+			return bulk, errors.New("timeout")
+		})
+
+	client := NewKVSClient("test", qkvs)
+	errs := testutil.ToFloat64(kvsLookups.WithLabelValues("test", OutcomeError))
+	failed := testutil.ToFloat64(calls.WithLabelValues(layerKVS, "test", "BulkGet", OutcomeError))
+
+	// when
+	_, err := client.BulkGet(ctx, []string{"a", "b"})
+
+	// then
+	require.Error(t, err)
+	require.Equal(t, errs+2, testutil.ToFloat64(kvsLookups.WithLabelValues("test", OutcomeError)))
+	require.Equal(t, failed+1, testutil.ToFloat64(calls.WithLabelValues(layerKVS, "test", "BulkGet", OutcomeError)))
+}
+
+func TestHandler(t *testing.T) {
+	// given
+	ObserveRequest(http.MethodGet, "/api/users/{id}", http.StatusOK, time.Millisecond)
+	ObserveCall("repository", "users", "Find", time.Now(), nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+
+	// when
+	Handler().ServeHTTP(w, r)
+
+	// then
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `http_requests_total{method="GET",route="/api/users/{id}",status="200"}`)
+	require.Contains(t, w.Body.String(), `calls_total{component="users",layer="repository",method="Find",outcome="success"}`)
+	require.Contains(t, w.Body.String(), `http_request_duration_seconds_bucket{method="GET",route="/api/users/{id}"`)
+}
@@ -0,0 +1,10 @@
+package ratelimit
+
+import (
+	"errors"
+)
+
+var (
+	ErrorInvalidLimit  = errors.New("invalid rate limit")
+	ErrorInvalidConfig = errors.New("invalid rate limit config")
+)
@@ -0,0 +1,233 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// keyPrefix namespaces the buckets in the store.
+	keyPrefix = "ratelimit"
+
+	// maxSaveAttempts is how many times a bucket is taken from again when another instance
+	// wrote it meanwhile.
+	maxSaveAttempts = 3
+)
+
+// Limit lets a client make Requests per Window, bursts of up to Requests are allowed
+// and the bucket refills evenly along the window.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// ParseLimit parses limits written as requests/window, e.g. 10/1m.
+func ParseLimit(s string) (Limit, error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return Limit{}, fmt.Errorf("%w: %q", ErrorInvalidLimit, s)
+	}
+
+	requests, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || requests <= 0 {
+		return Limit{}, fmt.Errorf("%w: %q", ErrorInvalidLimit, s)
+	}
+
+	window, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || window <= 0 {
+		return Limit{}, fmt.Errorf("%w: %q", ErrorInvalidLimit, s)
+	}
+
+	return Limit{
+		Requests: requests,
+		Window:   window,
+	}, nil
+}
+
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Window.Seconds()
+}
+
+// Config maps the routes, written as "METHOD /path", to their limits.
+type Config map[string]Limit
+
+// ParseConfig parses a comma separated list of route=limit pairs, e.g.
+// "POST /api/users=10/1m,GET /api/users=100/1m", over the defaults.
+func ParseConfig(s string, defaults Config) (Config, error) {
+	config := make(Config, len(defaults))
+	for route, limit := range defaults {
+		config[route] = limit
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%w: %q", ErrorInvalidConfig, pair)
+		}
+
+		limit, err := ParseLimit(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		config[strings.TrimSpace(parts[0])] = limit
+	}
+
+	return config, nil
+}
+
+// Result tells whether the request is allowed and what is left of the limit.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed, zero when Allowed.
+	RetryAfter time.Duration
+}
+
+// Limiter applies token buckets to the clients of each route. The requests of a client
+// take from its bucket one at a time within an instance, and the store tells when another
+// instance wrote the bucket meanwhile so that the request takes from it again.
+type Limiter struct {
+	store Store
+	now   func() time.Time
+	locks *keyLocks
+}
+
+func NewLimiter(store Store) *Limiter {
+	return &Limiter{
+		store,
+		time.Now,
+		newKeyLocks(),
+	}
+}
+
+// Allow takes a token from the bucket of client for route. The request is limited when
+// the bucket keeps being written by other instances, the client is hammering it.
+func (l *Limiter) Allow(ctx context.Context, route, client string, limit Limit) (Result, error) {
+	key := keyPrefix + ":" + route + ":" + client
+
+	unlock := l.locks.lock(key)
+	defer unlock()
+
+	for attempt := 1; attempt <= maxSaveAttempts; attempt++ {
+		now := l.now()
+
+		b, found, err := l.store.Load(ctx, key)
+		if err != nil {
+			return Result{}, err
+		}
+
+		result, next := take(b, found && now.Before(b.ExpiresAt), now, limit)
+
+		saved, err := l.store.Save(ctx, key, next)
+		if err != nil {
+			return Result{}, err
+		}
+		if saved {
+			return result, nil
+		}
+	}
+
+	return Result{
+		Limit:      limit.Requests,
+		Reset:      seconds(limit.Window.Seconds()),
+		RetryAfter: seconds(1 / limit.rate()),
+	}, nil
+}
+
+// Purge drops the buckets that expired, which are full again.
+func (l *Limiter) Purge(ctx context.Context) error {
+	return l.store.Purge(ctx, l.now())
+}
+
+// take takes a token from b, which is full when it isn't found, and returns the bucket to
+// save in its place. The bucket expires once it is full again, a Window at the most.
+func take(b Bucket, found bool, now time.Time, limit Limit) (Result, Bucket) {
+	capacity := float64(limit.Requests)
+	tokens := capacity
+	if found {
+		elapsed := now.Sub(b.UpdatedAt).Seconds()
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		tokens = math.Min(capacity, b.Tokens+elapsed*limit.rate())
+	}
+
+	result := Result{
+		Limit: limit.Requests,
+	}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - tokens) / limit.rate())
+	}
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = seconds((capacity - tokens) / limit.rate())
+
+	return result, Bucket{
+		Tokens:    tokens,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(limit.Window),
+		Version:   b.Version,
+	}
+}
+
+// keyLocks serializes the requests of each key, a key holds a lock only while some
+// request of it is in flight.
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+	holders int
+}
+
+func newKeyLocks() *keyLocks {
+	return &keyLocks{
+		locks: make(map[string]*keyLock),
+	}
+}
+
+// lock waits for the lock of key and returns its unlock.
+func (l *keyLocks) lock(key string) func() {
+	l.mu.Lock()
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &keyLock{}
+		l.locks[key] = lock
+	}
+	lock.holders++
+	l.mu.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		l.mu.Lock()
+		lock.holders--
+		if lock.holders == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// seconds rounds up s to whole seconds, the precision of the rate limit headers.
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s)) * time.Second
+}
@@ -0,0 +1,183 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	// given
+	ctx := context.Background()
+	now := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	limit := Limit{Requests: 2, Window: time.Minute}
+
+	limiter := NewLimiter(NewMemoryStore())
+	limiter.now = func() time.Time { return now }
+
+	// when
+	first, err := limiter.Allow(ctx, "POST /api/users", "client", limit)
+	require.NoError(t, err)
+	second, err := limiter.Allow(ctx, "POST /api/users", "client", limit)
+	require.NoError(t, err)
+	third, err := limiter.Allow(ctx, "POST /api/users", "client", limit)
+	require.NoError(t, err)
+	other, err := limiter.Allow(ctx, "POST /api/users", "other", limit)
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Second)
+	refilled, err := limiter.Allow(ctx, "POST /api/users", "client", limit)
+	require.NoError(t, err)
+
+	// then
+	require.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 1, Reset: 30 * time.Second}, first)
+	require.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Minute}, second)
+	require.Equal(t, Result{Allowed: false, Limit: 2, Remaining: 0, Reset: time.Minute, RetryAfter: 30 * time.Second}, third)
+	require.True(t, other.Allowed)
+	require.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Minute}, refilled)
+}
+
+func TestParseConfig(t *testing.T) {
+	defaults := Config{"POST /api/users": {Requests: 10, Window: time.Minute}}
+
+	tests := []struct {
+		name           string
+		value          string
+		expectedConfig Config
+		expectedError  error
+	}{
+		{
+			name:           "parse config defaults",
+			value:          "",
+			expectedConfig: defaults,
+		},
+		{
+			name:  "parse config overrides",
+			value: "POST /api/users=5/1s, GET /api/users=100/1m",
+			expectedConfig: Config{
+				"POST /api/users": {Requests: 5, Window: time.Second},
+				"GET /api/users":  {Requests: 100, Window: time.Minute},
+			},
+		},
+		{
+			name:          "parse config invalid limit",
+			value:         "POST /api/users=five/1s",
+			expectedError: ErrorInvalidLimit,
+		},
+		{
+			name:          "parse config invalid pair",
+			value:         "POST /api/users",
+			expectedError: ErrorInvalidConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			config, err := ParseConfig(tt.value, defaults)
+
+			// then
+			require.ErrorIs(t, err, tt.expectedError)
+			if tt.expectedError == nil {
+				require.Equal(t, tt.expectedConfig, config)
+			}
+		})
+	}
+}
+
+func TestLimiterAllowConcurrently(t *testing.T) {
+	// given
+	ctx := context.Background()
+	limit := Limit{Requests: 5, Window: time.Minute}
+	limiter := NewLimiter(NewMemoryStore())
+
+	// when
+	var allowed int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := limiter.Allow(ctx, "POST /api/users", "client", limit)
+			require.NoError(t, err)
+			if result.Allowed {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// then
+	require.Equal(t, int32(5), allowed)
+	require.Empty(t, limiter.locks.locks)
+}
+
+func TestLimiterAllowRetriesTheBucketsWrittenMeanwhile(t *testing.T) {
+	// given
+	ctx := context.Background()
+	now := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	limit := Limit{Requests: 2, Window: time.Minute}
+
+	store := &racingStore{NewMemoryStore(), 1}
+	limiter := NewLimiter(store)
+	limiter.now = func() time.Time { return now }
+
+	// when
+	first, err := limiter.Allow(ctx, "POST /api/users", "client", limit)
+	require.NoError(t, err)
+	store.races = maxSaveAttempts
+	second, err := limiter.Allow(ctx, "POST /api/users", "client", limit)
+	require.NoError(t, err)
+
+	// then
+	require.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Minute}, first)
+	require.Equal(t, Result{Allowed: false, Limit: 2, Reset: time.Minute, RetryAfter: 30 * time.Second}, second)
+}
+
+func TestLimiterPurge(t *testing.T) {
+	// given
+	ctx := context.Background()
+	now := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	limit := Limit{Requests: 2, Window: time.Minute}
+
+	store := NewMemoryStore()
+	limiter := NewLimiter(store)
+	limiter.now = func() time.Time { return now }
+
+	_, err := limiter.Allow(ctx, "POST /api/users", "old", limit)
+	require.NoError(t, err)
+	now = now.Add(30 * time.Second)
+	_, err = limiter.Allow(ctx, "POST /api/users", "recent", limit)
+	require.NoError(t, err)
+
+	// when
+	now = now.Add(30 * time.Second)
+	err = limiter.Purge(ctx)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, store.buckets, 1)
+	require.Contains(t, store.buckets, "ratelimit:POST /api/users:recent")
+}
+
+// racingStore writes each bucket ahead of the next races saves, as another instance
+// taking from it at the same time would.
+type racingStore struct {
+	*memoryStore
+	races int
+}
+
+func (s *racingStore) Save(ctx context.Context, key string, b Bucket) (bool, error) {
+	if s.races > 0 {
+		s.races--
+		if _, err := s.memoryStore.Save(ctx, key, b); err != nil {
+			return false, err
+		}
+	}
+
+	return s.memoryStore.Save(ctx, key, b)
+}
@@ -0,0 +1,163 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid"
+	"github.com/mercadolibre/fury_go-toolkit-kvs/pkg/kvs"
+)
+
+// Bucket is the token bucket state of a client. Version counts its writes, so that a
+// write over a newer bucket is told apart.
+type Bucket struct {
+	Tokens    float64   `json:"tokens"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Version   uint64    `json:"version"`
+	Writer    string    `json:"writer"`
+}
+
+// Store keeps the buckets of the clients, found is false for the clients seen for the
+// first time. Save writes b as the version after b.Version, saved is false when the
+// stored bucket isn't at b.Version anymore. Purge drops the buckets expired at now.
+type Store interface {
+	Load(ctx context.Context, key string) (b Bucket, found bool, err error)
+	Save(ctx context.Context, key string, b Bucket) (saved bool, err error)
+	Purge(ctx context.Context, now time.Time) error
+}
+
+// NewKVSStore keeps the buckets in KVS so that every instance shares them. KVS has no
+// conditional writes, so that Save checks the version before writing and reads the bucket
+// back after, which only lets a request of another instance through when it writes right
+// between the two.
+func NewKVSStore(qkvs kvs.QueryableClient) *kvsStore {
+	return &kvsStore{
+		qkvs,
+	}
+}
+
+type kvsStore struct {
+	qkvs kvs.QueryableClient
+}
+
+func (s *kvsStore) Load(ctx context.Context, key string) (Bucket, bool, error) {
+	item, err := s.qkvs.Get(ctx, key)
+	if errors.Is(err, kvs.ErrKeyNotFound) {
+		return Bucket{}, false, nil
+	}
+	if err != nil {
+		return Bucket{}, false, err
+	}
+
+	var b Bucket
+	if err := item.GetValue(&b); err != nil {
+		return Bucket{}, false, err
+	}
+
+	return b, true, nil
+}
+
+func (s *kvsStore) Save(ctx context.Context, key string, b Bucket) (bool, error) {
+	current, _, err := s.Load(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if current.Version != b.Version {
+		return false, nil
+	}
+
+	writer, err := gonanoid.Nanoid()
+	if err != nil {
+		return false, err
+	}
+	b.Version++
+	b.Writer = writer
+
+	if err := s.qkvs.Set(ctx, key, b); err != nil {
+		return false, err
+	}
+
+	// another instance may have written the bucket since it was checked
+	stored, found, err := s.Load(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	return found && stored.Writer == writer, nil
+}
+
+// Purge ranges the buckets a page at a time, dropping the expired ones.
+func (s *kvsStore) Purge(ctx context.Context, now time.Time) error {
+	// ; sorts right after the : that ends the prefix
+	query := kvs.Query{From: keyPrefix + ":", To: keyPrefix + ";"}
+
+	return s.qkvs.Range(ctx, query, func(items []kvs.Item, err error) error {
+		if err != nil {
+			return err
+		}
+
+		var expired []string
+		for _, item := range items {
+			var b Bucket
+			if err := item.GetValue(&b); err != nil {
+				return err
+			}
+			if !now.Before(b.ExpiresAt) {
+				expired = append(expired, item.Key)
+			}
+		}
+		if len(expired) == 0 {
+			return nil
+		}
+
+		return s.qkvs.BatchDelete(ctx, expired)
+	})
+}
+
+// NewMemoryStore keeps the buckets in memory, it is meant for tests and single instance setups.
+func NewMemoryStore() *memoryStore {
+	return &memoryStore{
+		buckets: make(map[string]Bucket),
+	}
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]Bucket
+}
+
+func (s *memoryStore) Load(_ context.Context, key string) (Bucket, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	return b, ok, nil
+}
+
+func (s *memoryStore) Save(_ context.Context, key string, b Bucket) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.buckets[key].Version != b.Version {
+		return false, nil
+	}
+
+	b.Version++
+	s.buckets[key] = b
+	return true, nil
+}
+
+func (s *memoryStore) Purge(_ context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, b := range s.buckets {
+		if !now.Before(b.ExpiresAt) {
+			delete(s.buckets, key)
+		}
+	}
+	return nil
+}
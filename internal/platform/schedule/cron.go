@@ -0,0 +1,162 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// descriptors are the shorthands accepted in place of the five fields.
+var descriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// bounds are the minimum and maximum values of each field.
+var bounds = [5][2]uint{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week, 0 and 7 are sunday
+}
+
+// Schedule is a parsed cron expression, each field is a bitset of the values it matches.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// anyDay is set when either day field is *, the days then have to match both fields
+	// instead of any of them as cron does.
+	anyDay bool
+}
+
+// Parse parses a standard five field cron expression, minute hour day-of-month month
+// day-of-week, whose fields are lists of values, ranges and steps such as 0,30 or 9-17
+// or */15. The @hourly, @daily, @weekly and @monthly descriptors are accepted too.
+func Parse(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if descriptor, ok := descriptors[expr]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(bounds) {
+		return Schedule{}, fmt.Errorf("%w: %q must have %d fields", ErrorInvalidExpression, expr, len(bounds))
+	}
+
+	var sets [5]uint64
+	for i, field := range fields {
+		set, err := parseField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return Schedule{}, fmt.Errorf("%w: %q: %v", ErrorInvalidExpression, expr, err)
+		}
+		sets[i] = set
+	}
+
+	// sunday can be written as 7
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+
+	return Schedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		anyDay: fields[2] == "*" || fields[4] == "*",
+	}, nil
+}
+
+func parseField(field string, min, max uint) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, uint(1)
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.ParseUint(part[i+1:], 10, 8)
+			if err != nil || s == 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rng, step = part[:i], uint(s)
+		}
+
+		from, to := min, max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			ends := strings.SplitN(rng, "-", 2)
+			var err error
+			if from, err = parseValue(ends[0], min, max); err != nil {
+				return 0, err
+			}
+			if to, err = parseValue(ends[1], min, max); err != nil {
+				return 0, err
+			}
+			if from > to {
+				return 0, fmt.Errorf("invalid range %q", rng)
+			}
+		default:
+			value, err := parseValue(rng, min, max)
+			if err != nil {
+				return 0, err
+			}
+			from = value
+			// a single value only spans up to max when it has a step, as in 5/15
+			if step == 1 {
+				to = value
+			}
+		}
+
+		for v := from; v <= to; v += step {
+			set |= 1 << v
+		}
+	}
+
+	return set, nil
+}
+
+func parseValue(s string, min, max uint) (uint, error) {
+	v, err := strconv.ParseUint(s, 10, 8)
+	if err != nil || uint(v) < min || uint(v) > max {
+		return 0, fmt.Errorf("%q is not between %d and %d", s, min, max)
+	}
+
+	return uint(v), nil
+}
+
+// Next returns the first minute after t matched by the schedule, in the location of t,
+// or the zero time when none matches in the next five years.
+func (s Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s Schedule) matchesDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.anyDay {
+		return dom && dow
+	}
+
+	return dom || dow
+}
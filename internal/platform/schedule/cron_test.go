@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleNext(t *testing.T) {
+	from := time.Date(2022, 1, 31, 10, 7, 30, 0, time.UTC) // monday
+
+	tests := []struct {
+		name         string
+		expr         string
+		expectedNext time.Time
+	}{
+		{
+			name:         "every minute",
+			expr:         "* * * * *",
+			expectedNext: time.Date(2022, 1, 31, 10, 8, 0, 0, time.UTC),
+		},
+		{
+			name:         "steps",
+			expr:         "*/15 * * * *",
+			expectedNext: time.Date(2022, 1, 31, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:         "ranges and lists roll over to the next day",
+			expr:         "0,30 3-5 * * *",
+			expectedNext: time.Date(2022, 2, 1, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "day of month rolls over to the next month",
+			expr:         "0 0 1 * *",
+			expectedNext: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "sunday written as 7",
+			expr:         "0 12 * * 7",
+			expectedNext: time.Date(2022, 2, 6, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "either day field matches when both are set",
+			expr:         "0 0 15 * 3",
+			expectedNext: time.Date(2022, 2, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "descriptor",
+			expr:         "@hourly",
+			expectedNext: time.Date(2022, 1, 31, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "never",
+			expr: "0 0 30 2 *",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			schedule, err := Parse(tt.expr)
+			require.NoError(t, err)
+
+			// when
+			next := schedule.Next(from)
+
+			// then
+			require.Equal(t, tt.expectedNext, next)
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := Parse(expr)
+		require.ErrorIs(t, err, ErrorInvalidExpression, expr)
+	}
+}
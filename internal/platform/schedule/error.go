@@ -0,0 +1,9 @@
+package schedule
+
+import (
+	"errors"
+)
+
+var (
+	ErrorInvalidExpression = errors.New("invalid cron expression")
+)
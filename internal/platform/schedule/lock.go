@@ -0,0 +1,46 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/database"
+)
+
+// NewMySQLLock returns a lock that keeps the last tick of each schedule in a row, an
+// instance wins a tick when it is the first to move the row forward to it. The owner
+// identifies the instance in the row.
+func NewMySQLLock(queries *database.Queries, owner string) *mysqlLock {
+	return &mysqlLock{
+		queries,
+		owner,
+		time.Now,
+	}
+}
+
+type mysqlLock struct {
+	queries *database.Queries
+	owner   string
+	now     func() time.Time
+}
+
+func (l *mysqlLock) Acquire(ctx context.Context, name string, tick time.Time) (bool, error) {
+	result, err := l.queries.AcquireScheduleTick(ctx, database.AcquireScheduleTickParams{
+		Name:       name,
+		Tick:       tick.UTC(),
+		Owner:      l.owner,
+		AcquiredAt: l.now().UTC(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// the upsert reports 1 affected row for a new schedule, 2 when it moved the tick and 0
+	// when another instance already did
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
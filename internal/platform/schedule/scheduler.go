@@ -0,0 +1,104 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+// Lock elects the instance that runs each tick of a schedule.
+type Lock interface {
+	// Acquire reports whether the caller won the tick of the named schedule.
+	Acquire(ctx context.Context, name string, tick time.Time) (bool, error)
+}
+
+// Func is the work of a schedule.
+type Func func(ctx context.Context) error
+
+type entry struct {
+	name     string
+	schedule Schedule
+	run      Func
+}
+
+// Scheduler runs functions on cron schedules. Every instance runs a scheduler and the
+// lock lets a single one of them run each tick.
+type Scheduler struct {
+	lock    Lock
+	entries []entry
+	now     func() time.Time
+}
+
+func NewScheduler(lock Lock) *Scheduler {
+	return &Scheduler{
+		lock,
+		nil,
+		time.Now,
+	}
+}
+
+// Add schedules run under name on the cron expression expr, evaluated in UTC.
+func (s *Scheduler) Add(name, expr string, run Func) error {
+	schedule, err := Parse(expr)
+	if err != nil {
+		return err
+	}
+
+	s.entries = append(s.entries, entry{name, schedule, run})
+	return nil
+}
+
+// Run runs the scheduled functions on their ticks until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range s.entries {
+		wg.Add(1)
+		go func(e entry) {
+			defer wg.Done()
+			s.loop(ctx, e)
+		}(e)
+	}
+
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, e entry) {
+	for {
+		next := e.schedule.Next(s.now().UTC())
+		if next.IsZero() {
+			logging.Warn(ctx, "schedule never runs", log.String("schedule", e.name))
+			return
+		}
+
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.tick(ctx, e, next)
+		}
+	}
+}
+
+// tick runs e for the tick when this instance wins it.
+func (s *Scheduler) tick(ctx context.Context, e entry, tick time.Time) {
+	ctx = logging.WithFields(ctx, log.String("schedule", e.name))
+
+	won, err := s.lock.Acquire(ctx, e.name, tick)
+	if err != nil {
+		logging.Error(ctx, "cannot acquire schedule lock", log.Err(err))
+		return
+	}
+	if !won {
+		logging.Debug(ctx, "schedule tick run by another instance")
+		return
+	}
+
+	if err := e.run(ctx); err != nil {
+		logging.Error(ctx, "scheduled run failed", log.Err(err))
+	}
+}
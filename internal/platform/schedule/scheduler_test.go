@@ -0,0 +1,59 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLock struct {
+	won bool
+	err error
+}
+
+func (l fakeLock) Acquire(ctx context.Context, name string, tick time.Time) (bool, error) {
+	return l.won, l.err
+}
+
+func TestSchedulerTick(t *testing.T) {
+	tests := []struct {
+		name        string
+		lock        fakeLock
+		expectedRun bool
+	}{
+		{
+			name:        "runs the ticks it wins",
+			lock:        fakeLock{won: true},
+			expectedRun: true,
+		},
+		{
+			name: "skips the ticks won by another instance",
+			lock: fakeLock{won: false},
+		},
+		{
+			name: "skips the ticks it can't lock",
+			lock: fakeLock{err: errors.New("db down")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			s := NewScheduler(tt.lock)
+			ran := false
+			require.NoError(t, s.Add("test", "* * * * *", func(ctx context.Context) error {
+				ran = true
+				return nil
+			}))
+
+			// when
+			s.tick(context.Background(), s.entries[0], time.Now())
+
+			// then
+			require.Equal(t, tt.expectedRun, ran)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Doer sends HTTP requests, like *http.Client.
+type Doer interface {
+	Do(r *http.Request) (*http.Response, error)
+}
+
+// NewClient traces the requests sent through next and propagates the trace context to
+// the servers in the W3C headers.
+func NewClient(next Doer) *client {
+	return &client{
+		next,
+	}
+}
+
+type client struct {
+	next Doer
+}
+
+func (c *client) Do(r *http.Request) (*http.Response, error) {
+	ctx, span := Start(r.Context(), "HTTP "+r.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPClientAttributesFromHTTPRequest(r)...),
+	)
+
+	r = r.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+	res, err := c.next.Do(r)
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(res.StatusCode))
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(res.StatusCode, trace.SpanKindClient))
+	span.End()
+
+	return res, nil
+}
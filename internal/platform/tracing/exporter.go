@@ -0,0 +1,35 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// NewLogExporter logs the finished spans, it stands in for a collector exporter.
+func NewLogExporter() *logExporter {
+	return &logExporter{}
+}
+
+type logExporter struct{}
+
+func (e *logExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	for _, span := range spans {
+		logging.Debug(ctx, "span finished",
+			log.String("trace_id", span.SpanContext().TraceID().String()),
+			log.String("span_id", span.SpanContext().SpanID().String()),
+			log.String("parent_span_id", span.Parent().SpanID().String()),
+			log.String("name", span.Name()),
+			log.String("status", span.Status().Code.String()),
+			log.Duration("duration", span.EndTime().Sub(span.StartTime())),
+		)
+	}
+
+	return nil
+}
+
+func (e *logExporter) Shutdown(context.Context) error {
+	return nil
+}
@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/mercadolibre/fury_go-toolkit-kvs/pkg/kvs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewKVSClient traces the operations of client on container.
+func NewKVSClient(container string, client kvs.QueryableClient) *kvsClient {
+	return &kvsClient{
+		client,
+		container,
+	}
+}
+
+type kvsClient struct {
+	client    kvs.QueryableClient
+	container string
+}
+
+func (c *kvsClient) Get(ctx context.Context, key string) (item kvs.Item, err error) {
+	ctx, span := c.start(ctx, "Get", 1)
+	defer func() { End(span, err) }()
+
+	return c.client.Get(ctx, key)
+}
+
+func (c *kvsClient) Set(ctx context.Context, key string, value interface{}) (err error) {
+	ctx, span := c.start(ctx, "Set", 1)
+	defer func() { End(span, err) }()
+
+	return c.client.Set(ctx, key, value)
+}
+
+func (c *kvsClient) Delete(ctx context.Context, key string) (deleted bool, err error) {
+	ctx, span := c.start(ctx, "Delete", 1)
+	defer func() { End(span, err) }()
+
+	return c.client.Delete(ctx, key)
+}
+
+func (c *kvsClient) BatchGet(ctx context.Context, keys []string) (items map[string]kvs.Item, err error) {
+	ctx, span := c.start(ctx, "BatchGet", len(keys))
+	defer func() { End(span, err) }()
+
+	return c.client.BatchGet(ctx, keys)
+}
+
+func (c *kvsClient) BatchSet(ctx context.Context, items []kvs.Item) (err error) {
+	ctx, span := c.start(ctx, "BatchSet", len(items))
+	defer func() { End(span, err) }()
+
+	return c.client.BatchSet(ctx, items)
+}
+
+func (c *kvsClient) BatchDelete(ctx context.Context, keys []string) (err error) {
+	ctx, span := c.start(ctx, "BatchDelete", len(keys))
+	defer func() { End(span, err) }()
+
+	return c.client.BatchDelete(ctx, keys)
+}
+
+func (c *kvsClient) BulkGet(ctx context.Context, keys []string) (bulk kvs.Bulk, err error) {
+	ctx, span := c.start(ctx, "BulkGet", len(keys))
+	defer func() { End(span, err) }()
+
+	return c.client.BulkGet(ctx, keys)
+}
+
+func (c *kvsClient) BulkSet(ctx context.Context, items []kvs.Item) (bulk kvs.Bulk, err error) {
+	ctx, span := c.start(ctx, "BulkSet", len(items))
+	defer func() { End(span, err) }()
+
+	return c.client.BulkSet(ctx, items)
+}
+
+func (c *kvsClient) BulkDelete(ctx context.Context, keys []string) (bulk kvs.Bulk, err error) {
+	ctx, span := c.start(ctx, "BulkDelete", len(keys))
+	defer func() { End(span, err) }()
+
+	return c.client.BulkDelete(ctx, keys)
+}
+
+func (c *kvsClient) Range(ctx context.Context, query kvs.Query, fn func([]kvs.Item, error) error) (err error) {
+	ctx, span := c.start(ctx, "Range", 0)
+	defer func() { End(span, err) }()
+
+	return c.client.Range(ctx, query, fn)
+}
+
+func (c *kvsClient) start(ctx context.Context, operation string, keys int) (context.Context, trace.Span) {
+	return Start(ctx, "kvs."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("kvs.container", c.container),
+			attribute.Int("kvs.keys", keys),
+		),
+	)
+}
@@ -0,0 +1,9 @@
+package search
+
+import (
+	"errors"
+)
+
+var (
+	ErrorQueryTooShort = errors.New("query too short")
+)
@@ -0,0 +1,75 @@
+package search
+
+import (
+	"context"
+
+	"github.com/johan-ag/testing/internal/platform/database"
+)
+
+// Types of the hits.
+const (
+	TypeUser = "user"
+	TypeBook = "book"
+)
+
+// Index finds the users and books whose name or title matches a query, among the users
+// of scope.
+type Index interface {
+	Search(ctx context.Context, query string, scope Scope, limit uint) ([]Hit, error)
+}
+
+// Scope is the users a search can find: any of them with AllUsers, or else only the one of
+// UserID, none when it is zero.
+type Scope struct {
+	AllUsers bool
+	UserID   uint
+}
+
+// Hit is a user or a book matching a query. Highlight is Text, HTML escaped, with the
+// matched words wrapped in <em>.
+type Hit struct {
+	Type      string  `json:"type"`
+	ID        uint    `json:"id"`
+	Text      string  `json:"text"`
+	Highlight string  `json:"highlight"`
+	Score     float64 `json:"score"`
+}
+
+// entityName names the search in the authorization policy.
+const entityName = "search"
+
+func NewMySQLIndex(queries *database.Queries) *mysqlIndex {
+	return &mysqlIndex{
+		queries,
+	}
+}
+
+// mysqlIndex searches the FULLTEXT indexes of users.name and books.title, which are built
+// by the ngram parser so that misspelled words still share most of their n-grams.
+type mysqlIndex struct {
+	queries *database.Queries
+}
+
+func (i *mysqlIndex) Search(ctx context.Context, query string, scope Scope, limit uint) ([]Hit, error) {
+	rows, err := i.queries.SearchUsersAndBooks(ctx, database.SearchUsersAndBooksParams{
+		Query:    query,
+		AllUsers: scope.AllUsers,
+		UserID:   int32(scope.UserID),
+		Limit:    int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, 0, len(rows))
+	for _, row := range rows {
+		hits = append(hits, Hit{
+			Type:  row.Type,
+			ID:    uint(row.ID),
+			Text:  row.Text,
+			Score: row.Score,
+		})
+	}
+
+	return hits, nil
+}
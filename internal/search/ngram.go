@@ -0,0 +1,145 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// ngramSize is the length of the n-grams compared, as the ngram_token_size the FULLTEXT
+// indexes are built with. Shorter queries can't match anything.
+const ngramSize = 2
+
+// minSimilarity is the least similarity of a hit to the query, and of a word to a word of
+// the query to be highlighted.
+const minSimilarity = 0.4
+
+// word is a run of letters and digits of a text, from its byte start to end.
+type word struct {
+	text       string
+	start, end int
+}
+
+// words splits s in its words, lower cased.
+func words(s string) []word {
+	var list []word
+
+	start := -1
+	for i, r := range s {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			list = append(list, word{strings.ToLower(s[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		list = append(list, word{strings.ToLower(s[start:]), start, len(s)})
+	}
+
+	return list
+}
+
+// ngrams counts the n-grams of w padded with a space on each side, so that the edges of
+// the words weigh as much as their middle.
+func ngrams(w string) map[string]int {
+	runes := []rune(" " + w + " ")
+
+	counts := map[string]int{}
+	for i := 0; i+ngramSize <= len(runes); i++ {
+		counts[string(runes[i:i+ngramSize])]++
+	}
+
+	return counts
+}
+
+// dice is the Sørensen–Dice coefficient of the n-grams of a and b, one when they are the
+// same word and zero when they share no n-gram.
+func dice(a, b map[string]int) float64 {
+	var shared, total int
+	for gram, n := range a {
+		shared += min(n, b[gram])
+		total += n
+	}
+	for _, n := range b {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+
+	return 2 * float64(shared) / float64(total)
+}
+
+// similarity is how close text is to query: the mean, over the words of query, of the dice
+// coefficient of the word of text closest to each one.
+func similarity(query, text string) float64 {
+	queryWords := words(query)
+	if len(queryWords) == 0 {
+		return 0
+	}
+
+	var textGrams []map[string]int
+	for _, w := range words(text) {
+		textGrams = append(textGrams, ngrams(w.text))
+	}
+
+	var sum float64
+	for _, q := range queryWords {
+		grams := ngrams(q.text)
+
+		var best float64
+		for _, t := range textGrams {
+			if d := dice(grams, t); d > best {
+				best = d
+			}
+		}
+		sum += best
+	}
+
+	return sum / float64(len(queryWords))
+}
+
+// highlight escapes text for HTML and wraps in <em> the words similar enough to any word
+// of query.
+func highlight(query, text string) string {
+	var queryGrams []map[string]int
+	for _, q := range words(query) {
+		queryGrams = append(queryGrams, ngrams(q.text))
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, w := range words(text) {
+		grams := ngrams(w.text)
+
+		matched := false
+		for _, q := range queryGrams {
+			if dice(grams, q) >= minSimilarity {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		b.WriteString(html.EscapeString(text[last:w.start]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(text[w.start:w.end]))
+		b.WriteString("</em>")
+		last = w.end
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+
+	return b.String()
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
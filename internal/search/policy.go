@@ -0,0 +1,38 @@
+package search
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+)
+
+// Actions authorized by policy.
+const (
+	actionSearch   = "search:query"
+	actionReadUser = "users:read"
+)
+
+// policy lets any caller search, the users found are the ones they could read, as the
+// users policy lets: any of them for admins, their own record otherwise.
+var policy = authz.Policy{
+	actionSearch:   {authz.Authenticated()},
+	actionReadUser: {authz.HasRole(auth.RoleAdmin), authz.IsOwner()},
+}
+
+// scope is the users the caller of ctx can find: any of them when policy lets it read
+// users it doesn't own, or else its own record, if it is a user.
+func scope(ctx context.Context) Scope {
+	if policy.Allows(ctx, actionReadUser, authz.Resource{Type: TypeUser}) {
+		return Scope{AllUsers: true}
+	}
+
+	principal, _ := auth.PrincipalFrom(ctx)
+	id, err := strconv.ParseUint(principal.Subject, 10, 32)
+	if err != nil {
+		return Scope{}
+	}
+
+	return Scope{UserID: uint(id)}
+}
@@ -0,0 +1,80 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/johan-ag/testing/internal/platform/authz"
+)
+
+// MaxLimit is the most hits a search returns, and how many it returns without a limit.
+const MaxLimit = 100
+
+// candidates is how many hits are asked to the index for each one returned, so that the
+// ones filtered out by similarity don't leave the page short.
+const candidates = 3
+
+type Service interface {
+	Search(ctx context.Context, query string, limit uint) ([]Hit, error)
+}
+
+//go:generate mockgen -destination=./mocks.go -package=search github.com/johan-ag/testing/internal/search Index,Service
+type service struct {
+	index Index
+}
+
+func NewService(index Index) *service {
+	return &service{
+		index,
+	}
+}
+
+// Search returns the users and books matching query, the most similar first. The index
+// finds the candidates, which are ranked again by the n-gram similarity of their text to
+// query, so that the hits with typos still rank below the exact ones, and the ones barely
+// sharing an n-gram with query are dropped. The index only finds the users the caller can
+// read, so that the page isn't cut short by the ones it can't.
+func (s *service) Search(ctx context.Context, query string, limit uint) ([]Hit, error) {
+	if err := policy.Authorize(ctx, actionSearch, authz.Resource{Type: entityName}); err != nil {
+		return nil, err
+	}
+
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) < ngramSize {
+		return nil, ErrorQueryTooShort
+	}
+
+	if limit == 0 || limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	found, err := s.index.Search(ctx, query, scope(ctx), limit*candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, 0, len(found))
+	for _, hit := range found {
+		score := similarity(query, hit.Text)
+		if score < minSimilarity {
+			continue
+		}
+
+		hit.Score = score
+		hit.Highlight = highlight(query, hit.Text)
+		hits = append(hits, hit)
+	}
+
+	// the index ranks ties
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Score > hits[j].Score
+	})
+
+	if uint(len(hits)) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits, nil
+}
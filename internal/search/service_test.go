@@ -0,0 +1,158 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceSearch(t *testing.T) {
+	found := []Hit{
+		{Type: TypeBook, ID: 1, Text: "Harry & the Potters", Score: 2},
+		{Type: TypeUser, ID: 2, Text: "harry", Score: 1},
+		{Type: TypeUser, ID: 3, Text: "Harold", Score: 1},
+		{Type: TypeBook, ID: 4, Text: "Stray cats", Score: 0.5},
+	}
+
+	tests := []struct {
+		name              string
+		executeBeforeTest func(ctx context.Context, i *MockIndex)
+		principal         auth.Principal
+		query             string
+		limit             uint
+		expectedHits      []Hit
+		expectedError     error
+	}{
+		{
+			name: "search ranks by similarity among the own user of the caller",
+			executeBeforeTest: func(ctx context.Context, i *MockIndex) {
+				i.EXPECT().
+					Search(gomock.Eq(ctx), gomock.Eq("hary"), gomock.Eq(Scope{UserID: 2}), gomock.Eq(uint(30))).
+					Return([]Hit{found[0], found[1], found[3]}, nil)
+			},
+			principal: auth.Principal{Subject: "2", Roles: []string{auth.RoleUser}},
+			query:     " hary ",
+			limit:     10,
+			expectedHits: []Hit{
+				{Type: TypeBook, ID: 1, Text: "Harry & the Potters", Highlight: "<em>Harry</em> &amp; the Potters", Score: 10.0 / 11},
+				{Type: TypeUser, ID: 2, Text: "harry", Highlight: "<em>harry</em>", Score: 10.0 / 11},
+			},
+		},
+		{
+			name: "search lets admins find any user",
+			executeBeforeTest: func(ctx context.Context, i *MockIndex) {
+				i.EXPECT().
+					Search(gomock.Eq(ctx), gomock.Eq("harold"), gomock.Eq(Scope{AllUsers: true}), gomock.Eq(uint(3))).
+					Return(found, nil)
+			},
+			principal: auth.Principal{Subject: "1", Roles: []string{auth.RoleAdmin}},
+			query:     "harold",
+			limit:     1,
+			expectedHits: []Hit{
+				{Type: TypeUser, ID: 3, Text: "Harold", Highlight: "<em>Harold</em>", Score: 1},
+			},
+		},
+		{
+			name: "search caps the limit",
+			executeBeforeTest: func(ctx context.Context, i *MockIndex) {
+				i.EXPECT().
+					Search(gomock.Eq(ctx), gomock.Eq("cats"), gomock.Eq(Scope{UserID: 1}), gomock.Eq(uint(MaxLimit*candidates))).
+					Return(nil, nil)
+			},
+			principal:    auth.Principal{Subject: "1", Roles: []string{auth.RoleUser}},
+			query:        "cats",
+			limit:        1000,
+			expectedHits: []Hit{},
+		},
+		{
+			name: "search finds no users for service accounts",
+			executeBeforeTest: func(ctx context.Context, i *MockIndex) {
+				i.EXPECT().
+					Search(gomock.Eq(ctx), gomock.Eq("stray"), gomock.Eq(Scope{}), gomock.Eq(uint(30))).
+					Return([]Hit{found[3]}, nil)
+			},
+			principal: auth.Principal{Subject: "svc", Roles: []string{auth.RoleService}},
+			query:     "stray",
+			limit:     10,
+			expectedHits: []Hit{
+				{Type: TypeBook, ID: 4, Text: "Stray cats", Highlight: "<em>Stray</em> cats", Score: 1},
+			},
+		},
+		{
+			name:              "search rejects short queries",
+			executeBeforeTest: func(ctx context.Context, i *MockIndex) {},
+			principal:         auth.Principal{Subject: "1", Roles: []string{auth.RoleUser}},
+			query:             " a ",
+			expectedError:     ErrorQueryTooShort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx := auth.WithPrincipal(context.Background(), tt.principal)
+			ctrl := gomock.NewController(t)
+			index := NewMockIndex(ctrl)
+
+			tt.executeBeforeTest(ctx, index)
+
+			service := NewService(index)
+
+			// when
+			hits, err := service.Search(ctx, tt.query, tt.limit)
+
+			// then
+			require.True(t, errors.Is(err, tt.expectedError))
+			if tt.expectedError == nil {
+				require.Equal(t, len(tt.expectedHits), len(hits))
+				for i, expected := range tt.expectedHits {
+					require.InDelta(t, expected.Score, hits[i].Score, 1e-9)
+					expected.Score = hits[i].Score
+					require.Equal(t, expected, hits[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		text     string
+		expected string
+	}{
+		{
+			name:     "exact words",
+			query:    "potter",
+			text:     "Harry Potter",
+			expected: "Harry <em>Potter</em>",
+		},
+		{
+			name:     "misspelled words",
+			query:    "hary poter",
+			text:     "Harry Potter and the <Stone>",
+			expected: "<em>Harry</em> <em>Potter</em> and the &lt;Stone&gt;",
+		},
+		{
+			name:     "no match",
+			query:    "dune",
+			text:     "Harry Potter",
+			expected: "Harry Potter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			highlighted := highlight(tt.query, tt.text)
+
+			// then
+			require.Equal(t, tt.expected, highlighted)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package users
+
+import (
+	"context"
+
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+// BatchMode tells how SaveBatch handles the users that can't be saved.
+type BatchMode string
+
+const (
+	// BatchAllOrNothing saves every user in one transaction, or none when any is invalid.
+	BatchAllOrNothing BatchMode = "all_or_nothing"
+	// BatchBestEffort saves the valid users and reports the failure of the rest.
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+const (
+	// MaxBatchSize caps the users of a batch.
+	MaxBatchSize = 1000
+
+	// bestEffortChunkSize is the number of users saved per transaction in best effort mode,
+	// the users of a failed chunk are retried one by one.
+	bestEffortChunkSize = 100
+)
+
+// BatchResult is the outcome of the user at Index of a batch, either its ID or the reason
+// it wasn't saved.
+type BatchResult struct {
+	Index int    `json:"index"`
+	ID    uint   `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// SaveBatch validates and saves users according to mode. In all or nothing mode it fails
+// with ErrorInvalidBatch, along with the results of the invalid users, when any is invalid.
+func (s *service) SaveBatch(ctx context.Context, users []NewUser, mode BatchMode) ([]BatchResult, error) {
+	if err := policy.Authorize(ctx, actionCreate, authz.Resource{Type: entityName}); err != nil {
+		return nil, err
+	}
+
+	if mode != BatchAllOrNothing && mode != BatchBestEffort {
+		return nil, ErrorInvalidBatchMode
+	}
+	if len(users) == 0 {
+		return nil, ErrorEmptyBatch
+	}
+	if len(users) > MaxBatchSize {
+		return nil, ErrorBatchTooLarge
+	}
+
+	// the activation codes are set on a copy, users belongs to the caller
+	users = append([]NewUser(nil), users...)
+
+	results := make([]BatchResult, len(users))
+	valid := make([]int, 0, len(users))
+	for i, u := range users {
+		results[i].Index = i
+
+		if err := validate(u.Name, u.Age); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		random, err := generateRandom()
+		if err != nil {
+			return nil, err
+		}
+		users[i].Random = random
+		valid = append(valid, i)
+	}
+
+	if mode == BatchAllOrNothing {
+		if len(valid) < len(users) {
+			return results, ErrorInvalidBatch
+		}
+
+		ids, err := s.repository.SaveBatch(ctx, users)
+		if err != nil {
+			return nil, err
+		}
+		for i, id := range ids {
+			results[i].ID = id
+		}
+
+		logging.Info(ctx, "users batch saved", log.Int("saved", len(ids)))
+		return results, nil
+	}
+
+	saved := 0
+	for start := 0; start < len(valid); start += bestEffortChunkSize {
+		end := start + bestEffortChunkSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+		saved += s.saveChunk(ctx, users, valid[start:end], results)
+	}
+
+	logging.Info(ctx, "users batch saved", log.Int("saved", saved), log.Int("failed", len(users)-saved))
+	return results, nil
+}
+
+// saveChunk saves the users at indexes in one transaction, retrying them one by one when
+// it fails so that a single bad user doesn't sink the rest. It returns how many were saved.
+func (s *service) saveChunk(ctx context.Context, users []NewUser, indexes []int, results []BatchResult) int {
+	chunk := make([]NewUser, len(indexes))
+	for i, index := range indexes {
+		chunk[i] = users[index]
+	}
+
+	ids, err := s.repository.SaveBatch(ctx, chunk)
+	if err == nil {
+		for i, id := range ids {
+			results[indexes[i]].ID = id
+		}
+		return len(ids)
+	}
+
+	saved := 0
+	for _, index := range indexes {
+		u := users[index]
+
+		id, err := s.repository.Save(ctx, u.Name, u.Age, u.Random)
+		if err != nil {
+			results[index].Error = err.Error()
+			continue
+		}
+
+		results[index].ID = id
+		saved++
+	}
+
+	return saved
+}
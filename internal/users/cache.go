@@ -0,0 +1,132 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/johan-ag/testing/internal/platform/cache"
+	"github.com/johan-ag/testing/internal/platform/database"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+// CacheName names the users cache in the metrics and the invalidation broadcasts.
+const CacheName = "users"
+
+// NewCachedService serves Find from c, which the mutations of next invalidate. The callers
+// are still authorized on every Find, cached or not.
+func NewCachedService(next Service, c *cache.Cache) *cachedService {
+	return &cachedService{
+		next,
+		c,
+	}
+}
+
+type cachedService struct {
+	next  Service
+	cache *cache.Cache
+}
+
+func (s *cachedService) Save(ctx context.Context, name string, age uint) (uint, error) {
+	return s.next.Save(ctx, name, age)
+}
+
+func (s *cachedService) SaveBatch(ctx context.Context, users []NewUser, mode BatchMode) ([]BatchResult, error) {
+	return s.next.SaveBatch(ctx, users, mode)
+}
+
+// Find caches the users that aren't deleted, without their activation code.
+func (s *cachedService) Find(ctx context.Context, id uint, includeDeleted bool) (User, error) {
+	if includeDeleted {
+		return s.next.Find(ctx, id, includeDeleted)
+	}
+
+	if err := policy.Authorize(ctx, actionRead, resource(id)); err != nil {
+		return User{}, err
+	}
+
+	// the concurrent misses share the load of the first one, whose caller was authorized
+	// to read the user just like the others. The load reads the primary, a replica lagging
+	// behind an invalidation would cache the stale user for the whole RemoteTTL
+	value, err := s.cache.Get(ctx, cacheKey(id), decodeUser, func(ctx context.Context) (interface{}, error) {
+		user, err := s.next.Find(database.WithPrimary(ctx), id, false)
+		user.ActivationCode = ""
+		return user, err
+	})
+	if err != nil {
+		return User{}, err
+	}
+
+	return value.(User), nil
+}
+
+func (s *cachedService) FindMany(ctx context.Context, ids []uint) ([]User, map[uint]error, error) {
+	return s.next.FindMany(ctx, ids)
+}
+
+func (s *cachedService) BatchGet(ctx context.Context, ids []uint) (BatchGetResult, error) {
+	return s.next.BatchGet(ctx, ids)
+}
+
+func (s *cachedService) List(ctx context.Context, filter ListFilter) ([]User, error) {
+	return s.next.List(ctx, filter)
+}
+
+func (s *cachedService) ListByAge(ctx context.Context, filter AgeFilter) ([]User, error) {
+	return s.next.ListByAge(ctx, filter)
+}
+
+func (s *cachedService) Export(ctx context.Context, includeDeleted bool, fn func(User) error) error {
+	return s.next.Export(ctx, includeDeleted, fn)
+}
+
+func (s *cachedService) Delete(ctx context.Context, id uint) error {
+	if err := s.next.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, id)
+	return nil
+}
+
+// Restore needs no invalidation, the users not found aren't cached.
+func (s *cachedService) Restore(ctx context.Context, id uint) error {
+	return s.next.Restore(ctx, id)
+}
+
+func (s *cachedService) Update(ctx context.Context, id uint, name string, age uint) error {
+	if err := s.next.Update(ctx, id, name, age); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, id)
+	return nil
+}
+
+func (s *cachedService) Activate(ctx context.Context, id uint, code string) error {
+	if err := s.next.Activate(ctx, id, code); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, id)
+	return nil
+}
+
+// invalidate drops the user from the cache, the mutation already succeeded so failing to
+// is only logged.
+func (s *cachedService) invalidate(ctx context.Context, id uint) {
+	if err := s.cache.Invalidate(ctx, cacheKey(id)); err != nil {
+		logging.Error(ctx, "cannot invalidate cached user", log.Uint("user_id", id), log.Err(err))
+	}
+}
+
+func cacheKey(id uint) string {
+	return CacheName + ":" + strconv.FormatUint(uint64(id), 10)
+}
+
+func decodeUser(raw json.RawMessage) (interface{}, error) {
+	var user User
+	err := json.Unmarshal(raw, &user)
+	return user, err
+}
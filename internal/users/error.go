@@ -5,6 +5,18 @@ import (
 )
 
 var (
-	ErrorFindLastInsertedID = errors.New("error to find  the last inserted id")
-	ErrorSavingToDB         = errors.New("error saving to db")
+	ErrorFindLastInsertedID    = errors.New("error to find  the last inserted id")
+	ErrorSavingToDB            = errors.New("error saving to db")
+	ErrorUserNotFound          = errors.New("user not found")
+	ErrorUserAlreadyActivated  = errors.New("user already activated")
+	ErrorInvalidActivationCode = errors.New("invalid activation code")
+	ErrorInvalidName           = errors.New("name must have between 1 and 50 characters")
+	ErrorInvalidAge            = errors.New("age must be at most 150")
+	ErrorEmptyBatch            = errors.New("batch has no users")
+	ErrorBatchTooLarge         = errors.New("batch has too many users")
+	ErrorInvalidBatch          = errors.New("batch has invalid users")
+	ErrorInvalidBatchMode      = errors.New("invalid batch mode")
+	ErrorInvalidStatus         = errors.New("status must be pending or active")
+	ErrorNoIDs                 = errors.New("no ids to get")
+	ErrorTooManyIDs            = errors.New("too many ids to get")
 )
@@ -0,0 +1,19 @@
+package users
+
+import (
+	"github.com/johan-ag/testing/internal/audit"
+)
+
+// Domain events raised by the user mutations, downstream consumers get them through the outbox.
+const (
+	EventUserCreated   = "UserCreated"
+	EventUserActivated = "UserActivated"
+	EventUserDeleted   = "UserDeleted"
+)
+
+// domainEvents maps the audited actions to the domain event they raise.
+var domainEvents = map[string]string{
+	audit.ActionCreate:   EventUserCreated,
+	audit.ActionActivate: EventUserActivated,
+	audit.ActionDelete:   EventUserDeleted,
+}
@@ -0,0 +1,131 @@
+package users
+
+import (
+	"context"
+
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+// MaxBatchGetSize caps the ids of a BatchGet.
+const MaxBatchGetSize = 100
+
+// BatchGetResult holds the users found by BatchGet, in the order their ids were asked for,
+// the ids of the ones that don't exist or are deleted, and why the caller can't read the
+// users of the ids denied.
+type BatchGetResult struct {
+	Users   []User         `json:"users"`
+	Missing []uint         `json:"missing"`
+	Denied  map[uint]error `json:"-"`
+}
+
+// BatchGet finds the users of ids in their projection in KVS first, and then the ones
+// missing there in the database, reading each at once. The repeated ids are looked up
+// once. The ids the caller can't read are denied one by one, like FindMany does, without
+// failing the others. The projection lags behind the database, so its hits are checked to
+// still exist, reading only their ids, and the users deleted since they were projected are
+// missing.
+func (s *service) BatchGet(ctx context.Context, ids []uint) (BatchGetResult, error) {
+	if len(ids) == 0 {
+		return BatchGetResult{}, ErrorNoIDs
+	}
+	if len(ids) > MaxBatchGetSize {
+		return BatchGetResult{}, ErrorTooManyIDs
+	}
+
+	var denied map[uint]error
+	readable := make([]uint, 0, len(ids))
+	for _, id := range unique(ids) {
+		if err := policy.Authorize(ctx, actionRead, resource(id)); err != nil {
+			if denied == nil {
+				denied = map[uint]error{}
+			}
+			denied[id] = err
+			continue
+		}
+		readable = append(readable, id)
+	}
+
+	found, err := s.find(ctx, readable)
+	if err != nil {
+		return BatchGetResult{}, err
+	}
+
+	result := BatchGetResult{
+		Users:   make([]User, 0, len(readable)),
+		Missing: []uint{},
+		Denied:  denied,
+	}
+	for _, id := range readable {
+		if user, ok := found[id]; ok {
+			result.Users = append(result.Users, user)
+		} else {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+
+	return result, nil
+}
+
+// find returns the users of ids that exist and aren't deleted, by id.
+func (s *service) find(ctx context.Context, ids []uint) (map[uint]User, error) {
+	if len(ids) == 0 {
+		return map[uint]User{}, nil
+	}
+
+	// the projection is only a shortcut, the users it can't give are read from the database
+	found, err := s.projection.Get(ctx, ids)
+	if err != nil {
+		logging.Warn(ctx, "cannot get projected users", log.Int("count", len(ids)), log.Err(err))
+		found = map[uint]User{}
+	}
+
+	var hits, misses []uint
+	for _, id := range ids {
+		if user, ok := found[id]; ok && user.DeletedAt == nil {
+			hits = append(hits, id)
+		} else {
+			delete(found, id)
+			misses = append(misses, id)
+		}
+	}
+
+	if len(hits) > 0 {
+		exist, err := s.repository.Exist(ctx, hits)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range hits {
+			if !exist[id] {
+				delete(found, id)
+			}
+		}
+	}
+
+	if len(misses) > 0 {
+		list, err := s.repository.FindMany(ctx, misses)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range list {
+			user.ActivationCode = ""
+			found[user.ID] = user
+		}
+	}
+
+	return found, nil
+}
+
+// unique is ids without the repeated ones, in the order they first appear.
+func unique(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	list := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			list = append(list, id)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,161 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/metrics"
+)
+
+// Layers of the instrumented calls.
+const (
+	layerService    = "service"
+	layerRepository = "repository"
+)
+
+// NewInstrumentedRepository records the rate, errors and latency of each method of next.
+func NewInstrumentedRepository(next Repository) *instrumentedRepository {
+	return &instrumentedRepository{
+		next,
+	}
+}
+
+type instrumentedRepository struct {
+	next Repository
+}
+
+func (r *instrumentedRepository) Save(ctx context.Context, name string, age uint, random string) (id uint, err error) {
+	defer observe(layerRepository, "Save", time.Now(), &err)
+	return r.next.Save(ctx, name, age, random)
+}
+
+func (r *instrumentedRepository) SaveBatch(ctx context.Context, users []NewUser) (ids []uint, err error) {
+	defer observe(layerRepository, "SaveBatch", time.Now(), &err)
+	return r.next.SaveBatch(ctx, users)
+}
+
+func (r *instrumentedRepository) Find(ctx context.Context, id uint, includeDeleted bool) (user User, err error) {
+	defer observe(layerRepository, "Find", time.Now(), &err)
+	return r.next.Find(ctx, id, includeDeleted)
+}
+
+func (r *instrumentedRepository) FindMany(ctx context.Context, ids []uint) (list []User, err error) {
+	defer observe(layerRepository, "FindMany", time.Now(), &err)
+	return r.next.FindMany(ctx, ids)
+}
+
+func (r *instrumentedRepository) Exist(ctx context.Context, ids []uint) (exist map[uint]bool, err error) {
+	defer observe(layerRepository, "Exist", time.Now(), &err)
+	return r.next.Exist(ctx, ids)
+}
+
+func (r *instrumentedRepository) List(ctx context.Context, filter ListFilter) (list []User, err error) {
+	defer observe(layerRepository, "List", time.Now(), &err)
+	return r.next.List(ctx, filter)
+}
+
+func (r *instrumentedRepository) ListAfter(ctx context.Context, afterID uint, limit uint, includeDeleted bool) (list []User, err error) {
+	defer observe(layerRepository, "ListAfter", time.Now(), &err)
+	return r.next.ListAfter(ctx, afterID, limit, includeDeleted)
+}
+
+func (r *instrumentedRepository) Update(ctx context.Context, id uint, name string, age uint) (err error) {
+	defer observe(layerRepository, "Update", time.Now(), &err)
+	return r.next.Update(ctx, id, name, age)
+}
+
+func (r *instrumentedRepository) Activate(ctx context.Context, id uint) (err error) {
+	defer observe(layerRepository, "Activate", time.Now(), &err)
+	return r.next.Activate(ctx, id)
+}
+
+func (r *instrumentedRepository) Delete(ctx context.Context, id uint) (err error) {
+	defer observe(layerRepository, "Delete", time.Now(), &err)
+	return r.next.Delete(ctx, id)
+}
+
+func (r *instrumentedRepository) Restore(ctx context.Context, id uint) (err error) {
+	defer observe(layerRepository, "Restore", time.Now(), &err)
+	return r.next.Restore(ctx, id)
+}
+
+func (r *instrumentedRepository) Purge(ctx context.Context, deletedBefore time.Time) (purged int64, err error) {
+	defer observe(layerRepository, "Purge", time.Now(), &err)
+	return r.next.Purge(ctx, deletedBefore)
+}
+
+// NewInstrumentedService records the rate, errors and latency of each method of next.
+func NewInstrumentedService(next Service) *instrumentedService {
+	return &instrumentedService{
+		next,
+	}
+}
+
+type instrumentedService struct {
+	next Service
+}
+
+func (s *instrumentedService) Save(ctx context.Context, name string, age uint) (id uint, err error) {
+	defer observe(layerService, "Save", time.Now(), &err)
+	return s.next.Save(ctx, name, age)
+}
+
+func (s *instrumentedService) SaveBatch(ctx context.Context, users []NewUser, mode BatchMode) (results []BatchResult, err error) {
+	defer observe(layerService, "SaveBatch", time.Now(), &err)
+	return s.next.SaveBatch(ctx, users, mode)
+}
+
+func (s *instrumentedService) Find(ctx context.Context, id uint, includeDeleted bool) (user User, err error) {
+	defer observe(layerService, "Find", time.Now(), &err)
+	return s.next.Find(ctx, id, includeDeleted)
+}
+
+func (s *instrumentedService) FindMany(ctx context.Context, ids []uint) (list []User, denied map[uint]error, err error) {
+	defer observe(layerService, "FindMany", time.Now(), &err)
+	return s.next.FindMany(ctx, ids)
+}
+
+func (s *instrumentedService) BatchGet(ctx context.Context, ids []uint) (result BatchGetResult, err error) {
+	defer observe(layerService, "BatchGet", time.Now(), &err)
+	return s.next.BatchGet(ctx, ids)
+}
+
+func (s *instrumentedService) List(ctx context.Context, filter ListFilter) (list []User, err error) {
+	defer observe(layerService, "List", time.Now(), &err)
+	return s.next.List(ctx, filter)
+}
+
+func (s *instrumentedService) ListByAge(ctx context.Context, filter AgeFilter) (list []User, err error) {
+	defer observe(layerService, "ListByAge", time.Now(), &err)
+	return s.next.ListByAge(ctx, filter)
+}
+
+func (s *instrumentedService) Export(ctx context.Context, includeDeleted bool, fn func(User) error) (err error) {
+	defer observe(layerService, "Export", time.Now(), &err)
+	return s.next.Export(ctx, includeDeleted, fn)
+}
+
+func (s *instrumentedService) Delete(ctx context.Context, id uint) (err error) {
+	defer observe(layerService, "Delete", time.Now(), &err)
+	return s.next.Delete(ctx, id)
+}
+
+func (s *instrumentedService) Restore(ctx context.Context, id uint) (err error) {
+	defer observe(layerService, "Restore", time.Now(), &err)
+	return s.next.Restore(ctx, id)
+}
+
+func (s *instrumentedService) Update(ctx context.Context, id uint, name string, age uint) (err error) {
+	defer observe(layerService, "Update", time.Now(), &err)
+	return s.next.Update(ctx, id, name, age)
+}
+
+func (s *instrumentedService) Activate(ctx context.Context, id uint, code string) (err error) {
+	defer observe(layerService, "Activate", time.Now(), &err)
+	return s.next.Activate(ctx, id, code)
+}
+
+// observe is deferred by the instrumented methods, err points to their named result.
+func observe(layer, method string, start time.Time, err *error) {
+	metrics.ObserveCall(layer, entityName, method, start, *err)
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/johan-ag/testing/internal/users (interfaces: Repository,Service)
+// Source: github.com/johan-ag/testing/internal/users (interfaces: Repository,Service,Projection)
 
 // Package users is a generated GoMock package.
 package users
@@ -7,6 +7,7 @@ package users
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,19 +35,136 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Activate mocks base method.
+func (m *MockRepository) Activate(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Activate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Activate indicates an expected call of Activate.
+func (mr *MockRepositoryMockRecorder) Activate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Activate", reflect.TypeOf((*MockRepository)(nil).Activate), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockRepository) Delete(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRepositoryMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), arg0, arg1)
+}
+
+// Exist mocks base method.
+func (m *MockRepository) Exist(arg0 context.Context, arg1 []uint) (map[uint]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exist", arg0, arg1)
+	ret0, _ := ret[0].(map[uint]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exist indicates an expected call of Exist.
+func (mr *MockRepositoryMockRecorder) Exist(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exist", reflect.TypeOf((*MockRepository)(nil).Exist), arg0, arg1)
+}
+
 // Find mocks base method.
-func (m *MockRepository) Find(arg0 context.Context, arg1 uint) (User, error) {
+func (m *MockRepository) Find(arg0 context.Context, arg1 uint, arg2 bool) (User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret := m.ctrl.Call(m, "Find", arg0, arg1, arg2)
 	ret0, _ := ret[0].(User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Find indicates an expected call of Find.
-func (mr *MockRepositoryMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Find(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockRepository)(nil).Find), arg0, arg1, arg2)
+}
+
+// FindMany mocks base method.
+func (m *MockRepository) FindMany(arg0 context.Context, arg1 []uint) ([]User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMany", arg0, arg1)
+	ret0, _ := ret[0].([]User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMany indicates an expected call of FindMany.
+func (mr *MockRepositoryMockRecorder) FindMany(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMany", reflect.TypeOf((*MockRepository)(nil).FindMany), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockRepository) List(arg0 context.Context, arg1 ListFilter) ([]User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRepositoryMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepository)(nil).List), arg0, arg1)
+}
+
+// ListAfter mocks base method.
+func (m *MockRepository) ListAfter(arg0 context.Context, arg1, arg2 uint, arg3 bool) ([]User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAfter", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAfter indicates an expected call of ListAfter.
+func (mr *MockRepositoryMockRecorder) ListAfter(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAfter", reflect.TypeOf((*MockRepository)(nil).ListAfter), arg0, arg1, arg2, arg3)
+}
+
+// Purge mocks base method.
+func (m *MockRepository) Purge(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockRepositoryMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockRepository)(nil).Purge), arg0, arg1)
+}
+
+// Restore mocks base method.
+func (m *MockRepository) Restore(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRepositoryMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockRepository)(nil).Find), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRepository)(nil).Restore), arg0, arg1)
 }
 
 // Save mocks base method.
@@ -64,6 +182,35 @@ func (mr *MockRepositoryMockRecorder) Save(arg0, arg1, arg2, arg3 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRepository)(nil).Save), arg0, arg1, arg2, arg3)
 }
 
+// SaveBatch mocks base method.
+func (m *MockRepository) SaveBatch(arg0 context.Context, arg1 []NewUser) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBatch", arg0, arg1)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveBatch indicates an expected call of SaveBatch.
+func (mr *MockRepositoryMockRecorder) SaveBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBatch", reflect.TypeOf((*MockRepository)(nil).SaveBatch), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockRepository) Update(arg0 context.Context, arg1 uint, arg2 string, arg3 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRepositoryMockRecorder) Update(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), arg0, arg1, arg2, arg3)
+}
+
 // MockService is a mock of Service interface.
 type MockService struct {
 	ctrl     *gomock.Controller
@@ -87,19 +234,136 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// Activate mocks base method.
+func (m *MockService) Activate(arg0 context.Context, arg1 uint, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Activate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Activate indicates an expected call of Activate.
+func (mr *MockServiceMockRecorder) Activate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Activate", reflect.TypeOf((*MockService)(nil).Activate), arg0, arg1, arg2)
+}
+
+// BatchGet mocks base method.
+func (m *MockService) BatchGet(arg0 context.Context, arg1 []uint) (BatchGetResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchGet", arg0, arg1)
+	ret0, _ := ret[0].(BatchGetResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGet indicates an expected call of BatchGet.
+func (mr *MockServiceMockRecorder) BatchGet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGet", reflect.TypeOf((*MockService)(nil).BatchGet), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockService) Delete(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockServiceMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), arg0, arg1)
+}
+
+// Export mocks base method.
+func (m *MockService) Export(arg0 context.Context, arg1 bool, arg2 func(User) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockServiceMockRecorder) Export(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockService)(nil).Export), arg0, arg1, arg2)
+}
+
 // Find mocks base method.
-func (m *MockService) Find(arg0 context.Context, arg1 uint) (User, error) {
+func (m *MockService) Find(arg0 context.Context, arg1 uint, arg2 bool) (User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret := m.ctrl.Call(m, "Find", arg0, arg1, arg2)
 	ret0, _ := ret[0].(User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Find indicates an expected call of Find.
-func (mr *MockServiceMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Find(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockService)(nil).Find), arg0, arg1, arg2)
+}
+
+// FindMany mocks base method.
+func (m *MockService) FindMany(arg0 context.Context, arg1 []uint) ([]User, map[uint]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMany", arg0, arg1)
+	ret0, _ := ret[0].([]User)
+	ret1, _ := ret[1].(map[uint]error)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindMany indicates an expected call of FindMany.
+func (mr *MockServiceMockRecorder) FindMany(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMany", reflect.TypeOf((*MockService)(nil).FindMany), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockService) List(arg0 context.Context, arg1 ListFilter) ([]User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockService)(nil).Find), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockService)(nil).List), arg0, arg1)
+}
+
+// ListByAge mocks base method.
+func (m *MockService) ListByAge(arg0 context.Context, arg1 AgeFilter) ([]User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByAge", arg0, arg1)
+	ret0, _ := ret[0].([]User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByAge indicates an expected call of ListByAge.
+func (mr *MockServiceMockRecorder) ListByAge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByAge", reflect.TypeOf((*MockService)(nil).ListByAge), arg0, arg1)
+}
+
+// Restore mocks base method.
+func (m *MockService) Restore(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockServiceMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockService)(nil).Restore), arg0, arg1)
 }
 
 // Save mocks base method.
@@ -116,3 +380,128 @@ func (mr *MockServiceMockRecorder) Save(arg0, arg1, arg2 interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockService)(nil).Save), arg0, arg1, arg2)
 }
+
+// SaveBatch mocks base method.
+func (m *MockService) SaveBatch(arg0 context.Context, arg1 []NewUser, arg2 BatchMode) ([]BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBatch", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveBatch indicates an expected call of SaveBatch.
+func (mr *MockServiceMockRecorder) SaveBatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBatch", reflect.TypeOf((*MockService)(nil).SaveBatch), arg0, arg1, arg2)
+}
+
+// Update mocks base method.
+func (m *MockService) Update(arg0 context.Context, arg1 uint, arg2 string, arg3 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockServiceMockRecorder) Update(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), arg0, arg1, arg2, arg3)
+}
+
+// MockProjection is a mock of Projection interface.
+type MockProjection struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectionMockRecorder
+}
+
+// MockProjectionMockRecorder is the mock recorder for MockProjection.
+type MockProjectionMockRecorder struct {
+	mock *MockProjection
+}
+
+// NewMockProjection creates a new mock instance.
+func NewMockProjection(ctrl *gomock.Controller) *MockProjection {
+	mock := &MockProjection{ctrl: ctrl}
+	mock.recorder = &MockProjectionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjection) EXPECT() *MockProjectionMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockProjection) Get(arg0 context.Context, arg1 []uint) (map[uint]User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(map[uint]User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockProjectionMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProjection)(nil).Get), arg0, arg1)
+}
+
+// ListByAge mocks base method.
+func (m *MockProjection) ListByAge(arg0 context.Context, arg1 AgeFilter) ([]User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByAge", arg0, arg1)
+	ret0, _ := ret[0].([]User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByAge indicates an expected call of ListByAge.
+func (mr *MockProjectionMockRecorder) ListByAge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByAge", reflect.TypeOf((*MockProjection)(nil).ListByAge), arg0, arg1)
+}
+
+// Put mocks base method.
+func (m *MockProjection) Put(arg0 context.Context, arg1 []User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockProjectionMockRecorder) Put(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockProjection)(nil).Put), arg0, arg1)
+}
+
+// Reconcile mocks base method.
+func (m *MockProjection) Reconcile(arg0 context.Context, arg1 Repository) (Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", arg0, arg1)
+	ret0, _ := ret[0].(Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockProjectionMockRecorder) Reconcile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockProjection)(nil).Reconcile), arg0, arg1)
+}
+
+// Remove mocks base method.
+func (m *MockProjection) Remove(arg0 context.Context, arg1 []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockProjectionMockRecorder) Remove(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockProjection)(nil).Remove), arg0, arg1)
+}
@@ -0,0 +1,42 @@
+package users
+
+import (
+	"strconv"
+
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+)
+
+// Actions authorized by policy.
+const (
+	actionCreate   = "users:create"
+	actionRead     = "users:read"
+	actionList     = "users:list"
+	actionUpdate   = "users:update"
+	actionActivate = "users:activate"
+	actionDelete   = "users:delete"
+	actionRestore  = "users:restore"
+)
+
+// policy lets admins manage any user, service accounts create users and the users read,
+// update and activate their own record, whose ID is the subject of their tokens.
+var policy = authz.Policy{
+	actionCreate:   {authz.HasRole(auth.RoleAdmin), authz.HasRole(auth.RoleService)},
+	actionRead:     {authz.HasRole(auth.RoleAdmin), authz.IsOwner()},
+	actionList:     {authz.HasRole(auth.RoleAdmin)},
+	actionUpdate:   {authz.HasRole(auth.RoleAdmin), authz.IsOwner()},
+	actionActivate: {authz.HasRole(auth.RoleAdmin), authz.IsOwner()},
+	actionDelete:   {authz.HasRole(auth.RoleAdmin)},
+	actionRestore:  {authz.HasRole(auth.RoleAdmin)},
+}
+
+// resource is the user id as seen by policy, users own their record.
+func resource(id uint) authz.Resource {
+	userID := strconv.FormatUint(uint64(id), 10)
+
+	return authz.Resource{
+		Type:    entityName,
+		ID:      userID,
+		OwnerID: userID,
+	}
+}
@@ -0,0 +1,547 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/database"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+	"github.com/mercadolibre/fury_go-toolkit-kvs/pkg/kvs"
+)
+
+// Statuses of the users as indexed by the projection.
+const (
+	StatusPending = "pending"
+	StatusActive  = "active"
+)
+
+// reconcilePageSize is how many users the reconciliation reads from the database at a time.
+const reconcilePageSize = 500
+
+// errRangeDone stops a Range once it read enough.
+var errRangeDone = errors.New("range done")
+
+// Projection is a copy of the users that aren't deleted kept in KVS, indexed by age and
+// status so that they can be listed by age without querying the database.
+type Projection interface {
+	Put(ctx context.Context, users []User) error
+	Remove(ctx context.Context, ids []uint) error
+	ListByAge(ctx context.Context, filter AgeFilter) ([]User, error)
+	Get(ctx context.Context, ids []uint) (map[uint]User, error)
+	Reconcile(ctx context.Context, source Repository) (Reconciliation, error)
+}
+
+// AgeFilter selects the page of users returned by ListByAge, by age and then by id. Status
+// is either empty, for any, or one of the statuses of the users.
+type AgeFilter struct {
+	MinAge uint
+	Status string
+	Limit  uint
+	Offset uint
+}
+
+// Reconciliation counts the users a reconciliation checked against the database, the ones
+// it had to project again or remove, and the index entries it dropped.
+type Reconciliation struct {
+	Checked int
+	Put     int
+	Removed int
+	Dropped int
+}
+
+func NewKVSProjection(qkvs kvs.QueryableClient) *kvsProjection {
+	return &kvsProjection{
+		qkvs,
+	}
+}
+
+// kvsProjection keeps each user under users:projection:<id>, and a copy of it under one
+// index entry per user and index: users:index:age:<age>:<id> and
+// users:index:status:<status>:<age>:<id>. The numbers are zero padded, so that the entries
+// of an index are ranged by age and then by id. A user changed concurrently can be left
+// with an outdated entry, which the reconciliation drops.
+type kvsProjection struct {
+	qkvs kvs.QueryableClient
+}
+
+// Put projects users, moving their index entries to their current age and status.
+func (p *kvsProjection) Put(ctx context.Context, users []User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+
+	previous, err := p.find(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	var stale []string
+	items := make([]kvs.Item, 0, 3*len(users))
+	for _, user := range users {
+		user.ActivationCode = ""
+		keys := indexKeys(user)
+		if old, ok := previous[user.ID]; ok {
+			stale = append(stale, subtract(indexKeys(old), keys)...)
+		}
+
+		items = append(items, kvs.Item{Key: projectionKey(user.ID), Value: user})
+		for _, key := range keys {
+			items = append(items, kvs.Item{Key: key, Value: user})
+		}
+	}
+
+	if err := p.qkvs.BatchSet(ctx, items); err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	return p.qkvs.BatchDelete(ctx, stale)
+}
+
+// Remove drops the users of ids from the projection and its indexes.
+func (p *kvsProjection) Remove(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	previous, err := p.find(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, projectionKey(id))
+		if old, ok := previous[id]; ok {
+			keys = append(keys, indexKeys(old)...)
+		}
+	}
+
+	return p.qkvs.BatchDelete(ctx, keys)
+}
+
+// ListByAge ranges the entries of the age index, or of the status one when filter.Status
+// is set, from filter.MinAge on until the page is full.
+func (p *kvsProjection) ListByAge(ctx context.Context, filter AgeFilter) ([]User, error) {
+	prefix := ageIndexPrefix
+	if filter.Status != "" {
+		prefix = statusIndexPrefix(filter.Status)
+	}
+
+	list := []User{}
+	skip := filter.Offset
+	query := kvs.Query{From: prefix + pad(filter.MinAge), To: prefix + rangeEnd}
+	err := p.scan(ctx, query, func(items []kvs.Item) (bool, error) {
+		for _, item := range items {
+			if skip > 0 {
+				skip--
+				continue
+			}
+
+			var user User
+			if err := item.GetValue(&user); err != nil {
+				return false, err
+			}
+
+			list = append(list, user)
+			if uint(len(list)) == filter.Limit {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Reconcile projects again the users of source that are missing or outdated, then removes
+// the projected users that are gone from source and drops the index entries that don't
+// match their user in source. It reads a page of each at a time.
+func (p *kvsProjection) Reconcile(ctx context.Context, source Repository) (Reconciliation, error) {
+	var r Reconciliation
+
+	var afterID uint
+	for {
+		page, err := source.ListAfter(ctx, afterID, reconcilePageSize, false)
+		if err != nil {
+			return r, err
+		}
+
+		ids := make([]uint, len(page))
+		for i, user := range page {
+			ids[i] = user.ID
+		}
+
+		projected, err := p.find(ctx, ids)
+		if err != nil {
+			return r, err
+		}
+
+		var stale []User
+		for _, user := range page {
+			user.ActivationCode = ""
+			if old, ok := projected[user.ID]; !ok || !sameProjection(old, user) {
+				stale = append(stale, user)
+			}
+		}
+
+		if err := p.Put(ctx, stale); err != nil {
+			return r, err
+		}
+		r.Checked += len(page)
+		r.Put += len(stale)
+
+		if len(page) < reconcilePageSize {
+			break
+		}
+		afterID = page[len(page)-1].ID
+	}
+
+	query := kvs.Query{From: projectionPrefix, To: projectionPrefix + rangeEnd}
+	err := p.scan(ctx, query, func(items []kvs.Item) (bool, error) {
+		current, err := p.current(ctx, source, items)
+		if err != nil {
+			return false, err
+		}
+
+		var gone []uint
+		for _, item := range items {
+			var user User
+			if err := item.GetValue(&user); err != nil {
+				return false, err
+			}
+			if _, ok := current[user.ID]; !ok {
+				gone = append(gone, user.ID)
+			}
+		}
+
+		r.Removed += len(gone)
+		return true, p.Remove(ctx, gone)
+	})
+	if err != nil {
+		return r, err
+	}
+
+	query = kvs.Query{From: indexPrefix, To: indexPrefix + rangeEnd}
+	err = p.scan(ctx, query, func(items []kvs.Item) (bool, error) {
+		current, err := p.current(ctx, source, items)
+		if err != nil {
+			return false, err
+		}
+
+		var dropped []string
+		for _, item := range items {
+			var user User
+			if err := item.GetValue(&user); err != nil {
+				return false, err
+			}
+			if want, ok := current[user.ID]; !ok || !contains(indexKeys(want), item.Key) {
+				dropped = append(dropped, item.Key)
+			}
+		}
+		if len(dropped) == 0 {
+			return true, nil
+		}
+
+		r.Dropped += len(dropped)
+		return true, p.qkvs.BatchDelete(ctx, dropped)
+	})
+
+	return r, err
+}
+
+// Get reads the projected users of ids in one BulkGet, by id. The ones that aren't
+// projected are left out.
+func (p *kvsProjection) Get(ctx context.Context, ids []uint) (map[uint]User, error) {
+	if len(ids) == 0 {
+		return map[uint]User{}, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = projectionKey(id)
+	}
+
+	bulk, err := p.qkvs.BulkGet(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint]User, len(ids))
+	for _, item := range bulk.Items() {
+		var user User
+		if err := item.GetValue(&user); err != nil {
+			return nil, err
+		}
+		found[user.ID] = user
+	}
+
+	return found, nil
+}
+
+func (p *kvsProjection) find(ctx context.Context, ids []uint) (map[uint]User, error) {
+	if len(ids) == 0 {
+		return map[uint]User{}, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = projectionKey(id)
+	}
+
+	items, err := p.qkvs.BatchGet(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint]User, len(items))
+	for _, item := range items {
+		var user User
+		if err := item.GetValue(&user); err != nil {
+			return nil, err
+		}
+		found[user.ID] = user
+	}
+
+	return found, nil
+}
+
+// current reads from source the users projected in items, by id. The ones gone from source
+// are left out.
+func (p *kvsProjection) current(ctx context.Context, source Repository, items []kvs.Item) (map[uint]User, error) {
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		var user User
+		if err := item.GetValue(&user); err != nil {
+			return nil, err
+		}
+		ids = append(ids, user.ID)
+	}
+
+	list, err := source.FindMany(ctx, unique(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[uint]User, len(list))
+	for _, user := range list {
+		current[user.ID] = user
+	}
+
+	return current, nil
+}
+
+// scan ranges query a page at a time, until fn returns false or an error.
+func (p *kvsProjection) scan(ctx context.Context, query kvs.Query, fn func(items []kvs.Item) (bool, error)) error {
+	err := p.qkvs.Range(ctx, query, func(items []kvs.Item, err error) error {
+		if err != nil {
+			return err
+		}
+
+		more, err := fn(items)
+		if err != nil {
+			return err
+		}
+		if !more {
+			return errRangeDone
+		}
+		return nil
+	})
+	if errors.Is(err, errRangeDone) {
+		return nil
+	}
+
+	return err
+}
+
+// NewProjectedRepository keeps projection up to date with the mutations of next. The
+// mutations already succeeded when the projection fails, so it is only logged and left for
+// the reconciliation to repair.
+func NewProjectedRepository(next Repository, projection Projection) *projectedRepository {
+	return &projectedRepository{
+		next,
+		projection,
+	}
+}
+
+type projectedRepository struct {
+	next       Repository
+	projection Projection
+}
+
+func (r *projectedRepository) Save(ctx context.Context, name string, age uint, random string) (uint, error) {
+	id, err := r.next.Save(ctx, name, age, random)
+	if err != nil {
+		return 0, err
+	}
+
+	r.project(ctx, id)
+	return id, nil
+}
+
+func (r *projectedRepository) SaveBatch(ctx context.Context, users []NewUser) ([]uint, error) {
+	ids, err := r.next.SaveBatch(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+
+	r.project(ctx, ids...)
+	return ids, nil
+}
+
+func (r *projectedRepository) Find(ctx context.Context, id uint, includeDeleted bool) (User, error) {
+	return r.next.Find(ctx, id, includeDeleted)
+}
+
+func (r *projectedRepository) FindMany(ctx context.Context, ids []uint) ([]User, error) {
+	return r.next.FindMany(ctx, ids)
+}
+
+func (r *projectedRepository) Exist(ctx context.Context, ids []uint) (map[uint]bool, error) {
+	return r.next.Exist(ctx, ids)
+}
+
+func (r *projectedRepository) List(ctx context.Context, filter ListFilter) ([]User, error) {
+	return r.next.List(ctx, filter)
+}
+
+func (r *projectedRepository) ListAfter(ctx context.Context, afterID uint, limit uint, includeDeleted bool) ([]User, error) {
+	return r.next.ListAfter(ctx, afterID, limit, includeDeleted)
+}
+
+func (r *projectedRepository) Update(ctx context.Context, id uint, name string, age uint) error {
+	if err := r.next.Update(ctx, id, name, age); err != nil {
+		return err
+	}
+
+	r.project(ctx, id)
+	return nil
+}
+
+func (r *projectedRepository) Activate(ctx context.Context, id uint) error {
+	if err := r.next.Activate(ctx, id); err != nil {
+		return err
+	}
+
+	r.project(ctx, id)
+	return nil
+}
+
+func (r *projectedRepository) Delete(ctx context.Context, id uint) error {
+	if err := r.next.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if err := r.projection.Remove(ctx, []uint{id}); err != nil {
+		logging.Error(ctx, "cannot remove projected user", log.Uint("user_id", id), log.Err(err))
+	}
+	return nil
+}
+
+func (r *projectedRepository) Restore(ctx context.Context, id uint) error {
+	if err := r.next.Restore(ctx, id); err != nil {
+		return err
+	}
+
+	r.project(ctx, id)
+	return nil
+}
+
+// Purge needs no projection, the deleted users were already removed.
+func (r *projectedRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return r.next.Purge(ctx, deletedBefore)
+}
+
+// project reads the users of ids back from the primary, where the write just landed, and
+// puts them in the projection.
+func (r *projectedRepository) project(ctx context.Context, ids ...uint) {
+	list, err := r.next.FindMany(database.WithPrimary(ctx), ids)
+	if err == nil {
+		err = r.projection.Put(ctx, list)
+	}
+	if err != nil {
+		logging.Error(ctx, "cannot project users", log.Int("count", len(ids)), log.Err(err))
+	}
+}
+
+func status(user User) string {
+	if user.ActivatedAt != nil {
+		return StatusActive
+	}
+
+	return StatusPending
+}
+
+const (
+	projectionPrefix = "users:projection:"
+	indexPrefix      = "users:index:"
+	ageIndexPrefix   = indexPrefix + "age:"
+
+	// rangeEnd sorts after the padded numbers, it ends the ranges of a prefix.
+	rangeEnd = "~"
+)
+
+func indexKeys(user User) []string {
+	suffix := pad(user.Age) + ":" + pad(user.ID)
+
+	return []string{ageIndexPrefix + suffix, statusIndexPrefix(status(user)) + suffix}
+}
+
+func statusIndexPrefix(status string) string {
+	return indexPrefix + "status:" + status + ":"
+}
+
+func projectionKey(id uint) string {
+	return projectionPrefix + strconv.FormatUint(uint64(id), 10)
+}
+
+// pad formats n to sort as a string as it does as a number.
+func pad(n uint) string {
+	return fmt.Sprintf("%020d", n)
+}
+
+// sameProjection tells whether the projection of a user matches the user, as far as its
+// JSON goes.
+func sameProjection(projected, user User) bool {
+	a, errA := json.Marshal(projected)
+	b, errB := json.Marshal(user)
+
+	return errA == nil && errB == nil && string(a) == string(b)
+}
+
+// subtract returns the keys of a not in b.
+func subtract(a, b []string) []string {
+	var keys []string
+	for _, key := range a {
+		if !contains(b, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
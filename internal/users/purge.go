@@ -0,0 +1,46 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+// PurgeJob hard deletes the users that were soft deleted more than retention ago.
+type PurgeJob struct {
+	repository Repository
+	retention  time.Duration
+	now        func() time.Time
+}
+
+func NewPurgeJob(repository Repository, retention time.Duration) *PurgeJob {
+	return &PurgeJob{
+		repository,
+		retention,
+		time.Now,
+	}
+}
+
+// Run purges once per interval until ctx is done.
+func (j *PurgeJob) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := j.Purge(ctx); err != nil {
+				logging.Error(ctx, "cannot purge deleted users", log.Err(err))
+			}
+		}
+	}
+}
+
+// Purge removes the users past the retention window and returns how many were removed.
+func (j *PurgeJob) Purge(ctx context.Context) (int64, error) {
+	return j.repository.Purge(ctx, j.now().Add(-j.retention))
+}
@@ -0,0 +1,63 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/johan-ag/testing/internal/jobs"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/johan-ag/testing/internal/platform/schedule"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+// ReconcileJobType is the type of the jobs that reconcile the projection of the users.
+const ReconcileJobType = "users.reconcile_projection"
+
+// ReconcileJob checks the projection of the users in KVS against the database, repairing
+// what the mutations failed to project.
+type ReconcileJob struct {
+	repository Repository
+	projection Projection
+}
+
+func NewReconcileJob(repository Repository, projection Projection) *ReconcileJob {
+	return &ReconcileJob{
+		repository,
+		projection,
+	}
+}
+
+// Job is the definition of the reconcile jobs, one runs at a time per worker.
+func (j *ReconcileJob) Job() jobs.Definition {
+	return jobs.Definition{
+		Type:        ReconcileJobType,
+		Handler:     j.Handle,
+		Concurrency: 1,
+		Timeout:     time.Minute,
+		MaxAttempts: 3,
+	}
+}
+
+// Handle reconciles the projection.
+func (j *ReconcileJob) Handle(ctx context.Context, job jobs.Job) error {
+	r, err := j.projection.Reconcile(ctx, j.repository)
+	if err != nil {
+		return err
+	}
+
+	logging.Info(ctx, "users projection reconciled",
+		log.Int("checked", r.Checked),
+		log.Int("put", r.Put),
+		log.Int("removed", r.Removed),
+		log.Int("dropped", r.Dropped),
+	)
+	return nil
+}
+
+// EnqueueReconcile is the scheduled work that queues a reconcile job, the workers run it.
+func EnqueueReconcile(jobsService jobs.Service) schedule.Func {
+	return func(ctx context.Context) error {
+		_, err := jobsService.Enqueue(ctx, ReconcileJobType, nil)
+		return err
+	}
+}
@@ -2,62 +2,446 @@ package users
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
 
 	_ "github.com/golang/mock/mockgen/model"
+	"github.com/johan-ag/testing/internal/audit"
+	"github.com/johan-ag/testing/internal/outbox"
 	"github.com/johan-ag/testing/internal/platform/database"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
 )
 
 //---go:generate mockgen -destination=mocks/repository.go -package=mocks github.com/johan-ag/testing/internal/users Repository
 type Repository interface {
 	Save(ctx context.Context, name string, age uint, random string) (uint, error)
-	Find(ctx context.Context, id uint) (User, error)
+	SaveBatch(ctx context.Context, users []NewUser) ([]uint, error)
+	Find(ctx context.Context, id uint, includeDeleted bool) (User, error)
+	FindMany(ctx context.Context, ids []uint) ([]User, error)
+	Exist(ctx context.Context, ids []uint) (map[uint]bool, error)
+	List(ctx context.Context, filter ListFilter) ([]User, error)
+	ListAfter(ctx context.Context, afterID uint, limit uint, includeDeleted bool) ([]User, error)
+	Update(ctx context.Context, id uint, name string, age uint) error
+	Activate(ctx context.Context, id uint) error
+	Delete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) error
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 
 type User struct {
-	ID   uint   `json:"id"`
-	Name string `json:"name"`
-	Age  uint   `json:"age"`
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	Age            uint       `json:"age"`
+	ActivationCode string     `json:"-"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	ActivatedAt    *time.Time `json:"activated_at,omitempty"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+}
+
+// NewUser is a user to be saved in a batch.
+type NewUser struct {
+	Name   string `json:"name"`
+	Age    uint   `json:"age"`
+	Random string `json:"-"`
+}
+
+// entityName names users in the audit log and the outbox.
+const entityName = "user"
+
+
+// ListFilter selects the page of users returned by List.
+type ListFilter struct {
+	Limit          uint
+	Offset         uint
+	IncludeDeleted bool
 }
 
 func NewRepository(queries *database.Queries) *repository {
 	return &repository{
 		queries,
+		time.Now,
 	}
 }
 
 type repository struct {
 	queries *database.Queries
+	now     func() time.Time
 }
 
 func (r *repository) Save(ctx context.Context, name string, age uint, random string) (uint, error) {
-	result, err := r.queries.SaveUser(ctx, database.SaveUserParams{
-		Name:   name,
-		Age:    int32(age),
-		Random: random,
+	now := r.now().UTC()
+
+	return r.mutate(ctx, audit.ActionCreate, 0, func(q *database.Queries) (uint, error) {
+		result, err := q.SaveUser(ctx, database.SaveUserParams{
+			Name:      name,
+			Age:       int32(age),
+			Random:    random,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return 0, ErrorSavingToDB
+		}
+
+		lastInsertID, err := result.LastInsertId()
+		if err != nil {
+			return 0, ErrorFindLastInsertedID
+		}
+
+		return uint(lastInsertID), nil
+	})
+}
+
+// SaveBatch saves users in one transaction and returns their IDs in the same order. The
+// users are inserted one at a time, the IDs of a multi-row insert aren't consecutive when
+// InnoDB interleaves the auto-increments of concurrent inserts.
+func (r *repository) SaveBatch(ctx context.Context, users []NewUser) ([]uint, error) {
+	now := r.now().UTC()
+	ids := make([]uint, 0, len(users))
+
+	err := r.queries.ExecTx(ctx, func(q *database.Queries) error {
+		for _, u := range users {
+			result, err := q.SaveUser(ctx, database.SaveUserParams{
+				Name:      u.Name,
+				Age:       int32(u.Age),
+				Random:    u.Random,
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+			if err != nil {
+				return ErrorSavingToDB
+			}
+
+			id, err := result.LastInsertId()
+			if err != nil {
+				return ErrorFindLastInsertedID
+			}
+
+			after := User{
+				ID:             uint(id),
+				Name:           u.Name,
+				Age:            u.Age,
+				ActivationCode: u.Random,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}
+			if err := record(ctx, q, audit.ActionCreate, nil, after); err != nil {
+				return err
+			}
+			ids = append(ids, after.ID)
+		}
+
+		return nil
 	})
 	if err != nil {
-		return 0, ErrorSavingToDB
+		logging.Error(ctx, "cannot save users batch", log.Int("users", len(users)), log.Err(err))
+		return nil, err
 	}
 
-	lastInsertID, err := result.LastInsertId()
+	return ids, nil
+}
+
+func (r *repository) Find(ctx context.Context, id uint, includeDeleted bool) (User, error) {
+	find := r.queries.FindUser
+	if includeDeleted {
+		find = r.queries.FindUserIncludingDeleted
+	}
+
+	u, err := find(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, ErrorUserNotFound
+	}
 	if err != nil {
-		return 0, ErrorFindLastInsertedID
+		return User{}, err
 	}
 
-	return uint(lastInsertID), nil
+	return toUser(u), nil
 }
 
-func (r *repository) Find(ctx context.Context, id uint) (User, error) {
-	u, err := r.queries.FindUser(ctx, int32(id))
+// FindMany returns the users of ids that exist and aren't deleted, in id order.
+func (r *repository) FindMany(ctx context.Context, ids []uint) ([]User, error) {
+	keys := make([]int32, len(ids))
+	for i, id := range ids {
+		keys[i] = int32(id)
+	}
+
+	rows, err := r.queries.ListUsersByIDs(ctx, keys)
 	if err != nil {
-		return User{}, err
+		return nil, err
+	}
+
+	users := make([]User, 0, len(rows))
+	for _, u := range rows {
+		users = append(users, toUser(u))
+	}
+
+	return users, nil
+}
+
+// Exist tells which of ids belong to users that exist and aren't deleted, reading only
+// their ids.
+func (r *repository) Exist(ctx context.Context, ids []uint) (map[uint]bool, error) {
+	keys := make([]int32, len(ids))
+	for i, id := range ids {
+		keys[i] = int32(id)
+	}
+
+	rows, err := r.queries.ListUserIDs(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	exist := make(map[uint]bool, len(rows))
+	for _, id := range rows {
+		exist[uint(id)] = true
+	}
+
+	return exist, nil
+}
+
+func (r *repository) List(ctx context.Context, filter ListFilter) ([]User, error) {
+	var (
+		rows []database.User
+		err  error
+	)
+	if filter.IncludeDeleted {
+		rows, err = r.queries.ListUsersIncludingDeleted(ctx, database.ListUsersIncludingDeletedParams{
+			Limit:  int32(filter.Limit),
+			Offset: int32(filter.Offset),
+		})
+	} else {
+		rows, err = r.queries.ListUsers(ctx, database.ListUsersParams{
+			Limit:  int32(filter.Limit),
+			Offset: int32(filter.Offset),
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]User, 0, len(rows))
+	for _, u := range rows {
+		users = append(users, toUser(u))
+	}
+
+	return users, nil
+}
+
+// ListAfter lists up to limit users by id starting after afterID, it pages with the
+// primary key instead of an offset so that long walks over the table stay cheap.
+func (r *repository) ListAfter(ctx context.Context, afterID uint, limit uint, includeDeleted bool) ([]User, error) {
+	list := r.queries.ListUsersAfter
+	if includeDeleted {
+		list = func(ctx context.Context, arg database.ListUsersAfterParams) ([]database.User, error) {
+			return r.queries.ListUsersIncludingDeletedAfter(ctx, database.ListUsersIncludingDeletedAfterParams(arg))
+		}
+	}
+
+	rows, err := list(ctx, database.ListUsersAfterParams{
+		ID:    int32(afterID),
+		Limit: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]User, 0, len(rows))
+	for _, u := range rows {
+		users = append(users, toUser(u))
+	}
+
+	return users, nil
+}
+
+func (r *repository) Update(ctx context.Context, id uint, name string, age uint) error {
+	_, err := r.mutate(ctx, audit.ActionUpdate, id, func(q *database.Queries) (uint, error) {
+		result, err := q.UpdateUser(ctx, database.UpdateUserParams{
+			Name:      name,
+			Age:       int32(age),
+			UpdatedAt: r.now().UTC(),
+			ID:        int32(id),
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		return id, expectAffected(result)
+	})
+
+	return err
+}
+
+func (r *repository) Activate(ctx context.Context, id uint) error {
+	now := r.now().UTC()
+
+	_, err := r.mutate(ctx, audit.ActionActivate, id, func(q *database.Queries) (uint, error) {
+		result, err := q.ActivateUser(ctx, database.ActivateUserParams{
+			ActivatedAt: sql.NullTime{Time: now, Valid: true},
+			UpdatedAt:   now,
+			ID:          int32(id),
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		return id, expectAffected(result)
+	})
+
+	return err
+}
+
+// Delete soft deletes the user, it is kept until Purge removes it.
+func (r *repository) Delete(ctx context.Context, id uint) error {
+	now := r.now().UTC()
+
+	_, err := r.mutate(ctx, audit.ActionDelete, id, func(q *database.Queries) (uint, error) {
+		result, err := q.SoftDeleteUser(ctx, database.SoftDeleteUserParams{
+			DeletedAt: sql.NullTime{Time: now, Valid: true},
+			UpdatedAt: now,
+			ID:        int32(id),
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		return id, expectAffected(result)
+	})
+
+	return err
+}
+
+func (r *repository) Restore(ctx context.Context, id uint) error {
+	_, err := r.mutate(ctx, audit.ActionRestore, id, func(q *database.Queries) (uint, error) {
+		result, err := q.RestoreUser(ctx, database.RestoreUserParams{
+			UpdatedAt: r.now().UTC(),
+			ID:        int32(id),
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		return id, expectAffected(result)
+	})
+
+	return err
+}
+
+// Purge hard deletes the users soft deleted before deletedBefore.
+func (r *repository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result, err := r.queries.PurgeDeletedUsers(ctx, sql.NullTime{Time: deletedBefore.UTC(), Valid: true})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// mutate runs change in a transaction and records, in the same transaction, the audit event
+// of action with the user as it was before and after the change, and the domain event the
+// action raises in the outbox. id is zero for new users, change returns the id of the user
+// it changed.
+func (r *repository) mutate(ctx context.Context, action string, id uint, change func(q *database.Queries) (uint, error)) (uint, error) {
+	err := r.queries.ExecTx(ctx, func(q *database.Queries) error {
+		var before interface{}
+		if id != 0 {
+			u, err := q.FindUserIncludingDeleted(ctx, int32(id))
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrorUserNotFound
+			}
+			if err != nil {
+				return err
+			}
+			before = toUser(u)
+		}
+
+		changedID, err := change(q)
+		if err != nil {
+			return err
+		}
+		id = changedID
+
+		u, err := q.FindUserIncludingDeleted(ctx, int32(id))
+		if err != nil {
+			return err
+		}
+
+		return record(ctx, q, action, before, toUser(u))
+	})
+	if err != nil {
+		if !errors.Is(err, ErrorUserNotFound) {
+			logging.Error(ctx, "cannot mutate user", log.String("action", action), log.Uint("user_id", id), log.Err(err))
+		}
+		return 0, err
 	}
 
+	logging.Debug(ctx, "user mutated", log.String("action", action), log.Uint("user_id", id))
+	return id, nil
+}
+
+// record saves the audit event of the change from before to after, along with the domain
+// event it raises if any.
+func record(ctx context.Context, q *database.Queries, action string, before interface{}, after User) error {
+	entityID := strconv.FormatUint(uint64(after.ID), 10)
+
+	event, err := audit.NewEvent(ctx, entityName, entityID, action, before, after)
+	if err != nil {
+		return err
+	}
+
+	if err := audit.NewRepository(q).Save(ctx, event); err != nil {
+		return err
+	}
+
+	eventType, ok := domainEvents[action]
+	if !ok {
+		return nil
+	}
+
+	domainEvent, err := outbox.NewEvent(eventType, entityName, entityID, after)
+	if err != nil {
+		return err
+	}
+
+	return outbox.NewRepository(q).Save(ctx, domainEvent)
+}
+
+// expectAffected reports ErrorUserNotFound when the statement didn't touch any row.
+func expectAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrorUserNotFound
+	}
+
+	return nil
+}
+
+func toUser(u database.User) User {
 	user := User{
-		ID:   uint(u.ID),
-		Name: u.Name,
-		Age:  uint(u.Age),
+		ID:             uint(u.ID),
+		Name:           u.Name,
+		Age:            uint(u.Age),
+		ActivationCode: u.Random,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
+	}
+
+	if u.ActivatedAt.Valid {
+		activatedAt := u.ActivatedAt.Time
+		user.ActivatedAt = &activatedAt
+	}
+
+	if u.DeletedAt.Valid {
+		deletedAt := u.DeletedAt.Time
+		user.DeletedAt = &deletedAt
 	}
 
-	return user, nil
+	return user
 }
@@ -2,32 +2,64 @@ package users
 
 import (
 	"context"
+	"unicode/utf8"
 
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/johan-ag/testing/internal/platform/logging"
 	gonanoid "github.com/matoous/go-nanoid"
-	"github.com/mercadolibre/fury_go-toolkit-kvs/pkg/kvs"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
 )
 
 type Service interface {
 	Save(ctx context.Context, name string, age uint) (uint, error)
-	Find(ctx context.Context, id uint) (User, error)
+	SaveBatch(ctx context.Context, users []NewUser, mode BatchMode) ([]BatchResult, error)
+	Find(ctx context.Context, id uint, includeDeleted bool) (User, error)
+	FindMany(ctx context.Context, ids []uint) ([]User, map[uint]error, error)
+	BatchGet(ctx context.Context, ids []uint) (BatchGetResult, error)
+	List(ctx context.Context, filter ListFilter) ([]User, error)
+	ListByAge(ctx context.Context, filter AgeFilter) ([]User, error)
+	Export(ctx context.Context, includeDeleted bool, fn func(User) error) error
+	Delete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) error
+	Update(ctx context.Context, id uint, name string, age uint) error
+	Activate(ctx context.Context, id uint, code string) error
 }
 
-//go:generate mockgen -destination=./mocks.go -package=users github.com/johan-ag/testing/internal/users Repository,Service
+const (
+	// maxListLimit caps the page size of List.
+	maxListLimit = 100
+
+	// exportPageSize is the number of users Export reads at a time.
+	exportPageSize = 500
+
+	maxNameLength = 50
+	maxAge        = 150
+)
+
+//go:generate mockgen -destination=./mocks.go -package=users github.com/johan-ag/testing/internal/users Repository,Service,Projection
 //go:generate mockgen -destination=../../internal/platform/kvs/mock.go -package=kvs github.com/mercadolibre/fury_go-toolkit-kvs/pkg/kvs QueryableClient
 type service struct {
 	repository Repository
-	qkvs       kvs.QueryableClient
+	projection Projection
 }
 
-func NewService(repository Repository, qkvs kvs.QueryableClient) *service {
+func NewService(repository Repository, projection Projection) *service {
 	return &service{
 		repository,
-		qkvs,
+		projection,
 	}
 }
 
 // Save method save
 func (s *service) Save(ctx context.Context, name string, age uint) (uint, error) {
+	if err := policy.Authorize(ctx, actionCreate, authz.Resource{Type: entityName}); err != nil {
+		return 0, err
+	}
+
+	if err := validate(name, age); err != nil {
+		return 0, err
+	}
+
 	random, err := generateRandom()
 	if err != nil {
 		return 0, err
@@ -38,11 +70,16 @@ func (s *service) Save(ctx context.Context, name string, age uint) (uint, error)
 		return 0, err
 	}
 
+	logging.Info(ctx, "user created", log.Uint("user_id", id))
 	return id, nil
 }
 
-func (s *service) Find(ctx context.Context, id uint) (User, error) {
-	user, err := s.repository.Find(ctx, id)
+func (s *service) Find(ctx context.Context, id uint, includeDeleted bool) (User, error) {
+	if err := policy.Authorize(ctx, actionRead, resource(id)); err != nil {
+		return User{}, err
+	}
+
+	user, err := s.repository.Find(ctx, id, includeDeleted)
 	if err != nil {
 		return User{}, err
 	}
@@ -50,6 +87,149 @@ func (s *service) Find(ctx context.Context, id uint) (User, error) {
 	return user, nil
 }
 
+// FindMany returns the users of ids at once, those that don't exist or are deleted are
+// left out. So are those the caller can't read, their denials are returned by id so that
+// one of them doesn't fail the others.
+func (s *service) FindMany(ctx context.Context, ids []uint) ([]User, map[uint]error, error) {
+	readable := make([]uint, 0, len(ids))
+	var denied map[uint]error
+	for _, id := range ids {
+		if err := policy.Authorize(ctx, actionRead, resource(id)); err != nil {
+			if denied == nil {
+				denied = map[uint]error{}
+			}
+			denied[id] = err
+			continue
+		}
+		readable = append(readable, id)
+	}
+
+	if len(readable) == 0 {
+		return nil, denied, nil
+	}
+
+	list, err := s.repository.FindMany(ctx, readable)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return list, denied, nil
+}
+
+func (s *service) List(ctx context.Context, filter ListFilter) ([]User, error) {
+	if err := policy.Authorize(ctx, actionList, authz.Resource{Type: entityName}); err != nil {
+		return nil, err
+	}
+
+	if filter.Limit == 0 || filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+
+	return s.repository.List(ctx, filter)
+}
+
+// ListByAge lists the users at least filter.MinAge years old from their projection in KVS,
+// which lags behind the database until the mutations are projected.
+func (s *service) ListByAge(ctx context.Context, filter AgeFilter) ([]User, error) {
+	if err := policy.Authorize(ctx, actionList, authz.Resource{Type: entityName}); err != nil {
+		return nil, err
+	}
+
+	if filter.Status != "" && filter.Status != StatusPending && filter.Status != StatusActive {
+		return nil, ErrorInvalidStatus
+	}
+
+	if filter.Limit == 0 || filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+
+	return s.projection.ListByAge(ctx, filter)
+}
+
+// Export calls fn with every user in id order, reading them a page at a time so that the
+// whole table is never held in memory. It stops at the first error of fn.
+func (s *service) Export(ctx context.Context, includeDeleted bool, fn func(User) error) error {
+	if err := policy.Authorize(ctx, actionList, authz.Resource{Type: entityName}); err != nil {
+		return err
+	}
+
+	var afterID uint
+	for {
+		page, err := s.repository.ListAfter(ctx, afterID, exportPageSize, includeDeleted)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range page {
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+
+		if len(page) < exportPageSize {
+			return nil
+		}
+		afterID = page[len(page)-1].ID
+	}
+}
+
+// Delete soft deletes the user, Restore undoes it until the purge job removes the row.
+func (s *service) Delete(ctx context.Context, id uint) error {
+	if err := policy.Authorize(ctx, actionDelete, resource(id)); err != nil {
+		return err
+	}
+
+	return s.repository.Delete(ctx, id)
+}
+
+func (s *service) Restore(ctx context.Context, id uint) error {
+	if err := policy.Authorize(ctx, actionRestore, resource(id)); err != nil {
+		return err
+	}
+
+	return s.repository.Restore(ctx, id)
+}
+
+func (s *service) Update(ctx context.Context, id uint, name string, age uint) error {
+	if err := policy.Authorize(ctx, actionUpdate, resource(id)); err != nil {
+		return err
+	}
+
+	if err := validate(name, age); err != nil {
+		return err
+	}
+
+	return s.repository.Update(ctx, id, name, age)
+}
+
+// Activate activates the user when code matches the activation code generated on Save.
+func (s *service) Activate(ctx context.Context, id uint, code string) error {
+	if err := policy.Authorize(ctx, actionActivate, resource(id)); err != nil {
+		return err
+	}
+
+	user, err := s.repository.Find(ctx, id, false)
+	if err != nil {
+		return err
+	}
+
+	if user.ActivatedAt != nil {
+		return ErrorUserAlreadyActivated
+	}
+
+	if code != user.ActivationCode {
+		logging.Warn(ctx, "invalid activation code", log.Uint("user_id", id))
+		return ErrorInvalidActivationCode
+	}
+
+	if err := s.repository.Activate(ctx, id); err != nil {
+		return err
+	}
+
+	logging.Info(ctx, "user activated", log.Uint("user_id", id))
+	return nil
+}
+
 // generateRandom generate length six random string using go-nanoid library,
 func generateRandom() (string, error) {
 	activationAlphabet := "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ" //TODO
@@ -62,3 +242,16 @@ func generateRandom() (string, error) {
 
 	return random, nil
 }
+
+// validate applies the rules every user created or updated must follow.
+func validate(name string, age uint) error {
+	if length := utf8.RuneCountInString(name); length == 0 || length > maxNameLength {
+		return ErrorInvalidName
+	}
+
+	if age > maxAge {
+		return ErrorInvalidAge
+	}
+
+	return nil
+}
@@ -2,10 +2,24 @@ package users
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/johan-ag/testing/internal/platform/cache"
+	"github.com/johan-ag/testing/internal/platform/database"
+	mockkvs "github.com/johan-ag/testing/internal/platform/kvs"
 	"github.com/mercadolibre/fury_go-toolkit-kvs/pkg/kvs"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	adminCtx   = auth.WithPrincipal(context.Background(), auth.Principal{Subject: "100", Roles: []string{auth.RoleAdmin}})
+	serviceCtx = auth.WithPrincipal(context.Background(), auth.Principal{Subject: "svc", Roles: []string{auth.RoleService}})
+	ownerCtx   = auth.WithPrincipal(context.Background(), auth.Principal{Subject: "1", Roles: []string{auth.RoleUser}})
 )
 
 func TestServiceSave(t *testing.T) {
@@ -26,7 +40,7 @@ func TestServiceSave(t *testing.T) {
 					Save(gomock.Eq(ctx), gomock.Eq(expectedName), gomock.Eq(expectedAge), gomock.Any()).
 					Return(uint(1), nil)
 			},
-			expectedContext: context.Background(),
+			expectedContext: serviceCtx,
 			expectedName:    "name",
 			expectedAge:     43,
 			withError:       false,
@@ -40,12 +54,39 @@ func TestServiceSave(t *testing.T) {
 					Save(gomock.Eq(ctx), gomock.Eq(expectedName), gomock.Eq(expectedAge), gomock.Any()).
 					Return(uint(0), ErrorSavingToDB)
 			},
-			expectedContext: context.Background(),
+			expectedContext: serviceCtx,
 			expectedName:    "name",
 			expectedAge:     43,
 			withError:       true,
 			expectedError:   ErrorSavingToDB,
 		},
+		{
+			name:              "save service test denied to users",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, expectedName string, expectedAge uint) {},
+			expectedContext:   ownerCtx,
+			expectedName:      "name",
+			expectedAge:       43,
+			withError:         true,
+			expectedError:     authz.ErrorDenied,
+		},
+		{
+			name:              "save service test invalid name",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, expectedName string, expectedAge uint) {},
+			expectedContext:   serviceCtx,
+			expectedName:      "",
+			expectedAge:       43,
+			withError:         true,
+			expectedError:     ErrorInvalidName,
+		},
+		{
+			name:              "save service test invalid age",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, expectedName string, expectedAge uint) {},
+			expectedContext:   serviceCtx,
+			expectedName:      "name",
+			expectedAge:       151,
+			withError:         true,
+			expectedError:     ErrorInvalidAge,
+		},
 	}
 
 	for _, tt := range tests {
@@ -57,17 +98,780 @@ func TestServiceSave(t *testing.T) {
 			tt.executeBeforeTest(tt.expectedContext, repository, tt.expectedName, tt.expectedAge)
 
 			qkvs, _ := kvs.NewQueryableClient("")
-			service := NewService(repository, qkvs)
+			service := NewService(repository, NewKVSProjection(qkvs))
 
 			// when
 			_, err := service.Save(tt.expectedContext, tt.expectedName, tt.expectedAge)
 
 			// then
 
-			if err != tt.expectedError {
+			if !errors.Is(err, tt.expectedError) {
+				t.Fail()
+			}
+		})
+	}
+
+}
+
+func TestServiceDelete(t *testing.T) {
+	tests := []struct {
+		name              string
+		executeBeforeTest func(ctx context.Context, r *MockRepository, id uint)
+		expectedContext   context.Context
+		id                uint
+		expectedError     error
+	}{
+		{
+			name: "delete service test successful",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, id uint) {
+				r.
+					EXPECT().
+					Delete(gomock.Eq(ctx), gomock.Eq(id)).
+					Return(nil)
+			},
+			expectedContext: adminCtx,
+			id:              1,
+			expectedError:   nil,
+		},
+		{
+			name: "delete service test not found",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, id uint) {
+				r.
+					EXPECT().
+					Delete(gomock.Eq(ctx), gomock.Eq(id)).
+					Return(ErrorUserNotFound)
+			},
+			expectedContext: adminCtx,
+			id:              2,
+			expectedError:   ErrorUserNotFound,
+		},
+		{
+			name:              "delete service test denied to owner",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, id uint) {},
+			expectedContext:   ownerCtx,
+			id:                1,
+			expectedError:     authz.ErrorDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			repository := NewMockRepository(ctrl)
+
+			tt.executeBeforeTest(tt.expectedContext, repository, tt.id)
+
+			qkvs, _ := kvs.NewQueryableClient("")
+			service := NewService(repository, NewKVSProjection(qkvs))
+
+			// when
+			err := service.Delete(tt.expectedContext, tt.id)
+
+			// then
+			if !errors.Is(err, tt.expectedError) {
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestServiceList(t *testing.T) {
+	tests := []struct {
+		name           string
+		filter         ListFilter
+		expectedFilter ListFilter
+	}{
+		{
+			name:           "list service test default limit",
+			filter:         ListFilter{},
+			expectedFilter: ListFilter{Limit: maxListLimit},
+		},
+		{
+			name:           "list service test keeps include deleted",
+			filter:         ListFilter{Limit: 10, Offset: 20, IncludeDeleted: true},
+			expectedFilter: ListFilter{Limit: 10, Offset: 20, IncludeDeleted: true},
+		},
+		{
+			name:           "list service test caps limit",
+			filter:         ListFilter{Limit: 1000},
+			expectedFilter: ListFilter{Limit: maxListLimit},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx := adminCtx
+			ctrl := gomock.NewController(t)
+			repository := NewMockRepository(ctrl)
+			repository.
+				EXPECT().
+				List(gomock.Eq(ctx), gomock.Eq(tt.expectedFilter)).
+				Return([]User{}, nil)
+
+			qkvs, _ := kvs.NewQueryableClient("")
+			service := NewService(repository, NewKVSProjection(qkvs))
+
+			// when
+			_, err := service.List(ctx, tt.filter)
+
+			// then
+			if err != nil {
 				t.Fail()
 			}
 		})
 	}
+}
+
+func TestServiceFindMany(t *testing.T) {
+	tests := []struct {
+		name              string
+		executeBeforeTest func(ctx context.Context, r *MockRepository)
+		ctx               context.Context
+		ids               []uint
+		expectedUsers     []User
+		expectedDenied    []uint
+		expectedError     error
+	}{
+		{
+			name: "find many service test admin",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {
+				r.
+					EXPECT().
+					FindMany(gomock.Eq(ctx), gomock.Eq([]uint{1, 2})).
+					Return([]User{{ID: 1}, {ID: 2}}, nil)
+			},
+			ctx:           adminCtx,
+			ids:           []uint{1, 2},
+			expectedUsers: []User{{ID: 1}, {ID: 2}},
+		},
+		{
+			name: "find many service test owner",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {
+				r.
+					EXPECT().
+					FindMany(gomock.Eq(ctx), gomock.Eq([]uint{1})).
+					Return([]User{{ID: 1}}, nil)
+			},
+			ctx:           ownerCtx,
+			ids:           []uint{1},
+			expectedUsers: []User{{ID: 1}},
+		},
+		{
+			name: "find many service test leaves out the users that aren't owned",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {
+				r.
+					EXPECT().
+					FindMany(gomock.Eq(ctx), gomock.Eq([]uint{1})).
+					Return([]User{{ID: 1}}, nil)
+			},
+			ctx:            ownerCtx,
+			ids:            []uint{1, 2},
+			expectedUsers:  []User{{ID: 1}},
+			expectedDenied: []uint{2},
+		},
+		{
+			name:              "find many service test none owned",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {},
+			ctx:               ownerCtx,
+			ids:               []uint{2, 3},
+			expectedDenied:    []uint{2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			repository := NewMockRepository(ctrl)
+
+			tt.executeBeforeTest(tt.ctx, repository)
+
+			qkvs, _ := kvs.NewQueryableClient("")
+			service := NewService(repository, NewKVSProjection(qkvs))
+
+			// when
+			list, denied, err := service.FindMany(tt.ctx, tt.ids)
+
+			// then
+			require.ErrorIs(t, err, tt.expectedError)
+			require.Equal(t, tt.expectedUsers, list)
+			require.Len(t, denied, len(tt.expectedDenied))
+			for _, id := range tt.expectedDenied {
+				require.ErrorIs(t, denied[id], authz.ErrorDenied)
+			}
+		})
+	}
+}
+
+func TestServiceActivate(t *testing.T) {
+	activatedAt := time.Now()
+
+	tests := []struct {
+		name              string
+		executeBeforeTest func(ctx context.Context, r *MockRepository, id uint)
+		code              string
+		expectedError     error
+	}{
+		{
+			name: "activate service test successful",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, id uint) {
+				r.
+					EXPECT().
+					Find(gomock.Eq(ctx), gomock.Eq(id), gomock.Eq(false)).
+					Return(User{ID: id, ActivationCode: "ABC123"}, nil)
+				r.
+					EXPECT().
+					Activate(gomock.Eq(ctx), gomock.Eq(id)).
+					Return(nil)
+			},
+			code:          "ABC123",
+			expectedError: nil,
+		},
+		{
+			name: "activate service test invalid code",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, id uint) {
+				r.
+					EXPECT().
+					Find(gomock.Eq(ctx), gomock.Eq(id), gomock.Eq(false)).
+					Return(User{ID: id, ActivationCode: "ABC123"}, nil)
+			},
+			code:          "XYZ789",
+			expectedError: ErrorInvalidActivationCode,
+		},
+		{
+			name: "activate service test already activated",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, id uint) {
+				r.
+					EXPECT().
+					Find(gomock.Eq(ctx), gomock.Eq(id), gomock.Eq(false)).
+					Return(User{ID: id, ActivationCode: "ABC123", ActivatedAt: &activatedAt}, nil)
+			},
+			code:          "ABC123",
+			expectedError: ErrorUserAlreadyActivated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx := ownerCtx
+			ctrl := gomock.NewController(t)
+			repository := NewMockRepository(ctrl)
+
+			tt.executeBeforeTest(ctx, repository, 1)
+
+			qkvs, _ := kvs.NewQueryableClient("")
+			service := NewService(repository, NewKVSProjection(qkvs))
+
+			// when
+			err := service.Activate(ctx, 1, tt.code)
+
+			// then
+			require.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestServiceSaveBatch(t *testing.T) {
+	tests := []struct {
+		name              string
+		executeBeforeTest func(ctx context.Context, r *MockRepository)
+		users             []NewUser
+		mode              BatchMode
+		expectedResults   []BatchResult
+		expectedError     error
+	}{
+		{
+			name: "save batch service test all or nothing",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {
+				r.
+					EXPECT().
+					SaveBatch(gomock.Eq(ctx), gomock.Len(2)).
+					Return([]uint{1, 2}, nil)
+			},
+			users:           []NewUser{{Name: "rick", Age: 70}, {Name: "morty", Age: 14}},
+			mode:            BatchAllOrNothing,
+			expectedResults: []BatchResult{{Index: 0, ID: 1}, {Index: 1, ID: 2}},
+		},
+		{
+			name:              "save batch service test all or nothing rejects invalid users",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {},
+			users:             []NewUser{{Name: "rick", Age: 70}, {Name: "", Age: 14}},
+			mode:              BatchAllOrNothing,
+			expectedResults:   []BatchResult{{Index: 0}, {Index: 1, Error: ErrorInvalidName.Error()}},
+			expectedError:     ErrorInvalidBatch,
+		},
+		{
+			name: "save batch service test best effort retries failed chunk one by one",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {
+				r.
+					EXPECT().
+					SaveBatch(gomock.Eq(ctx), gomock.Len(2)).
+					Return(nil, ErrorSavingToDB)
+				r.
+					EXPECT().
+					Save(gomock.Eq(ctx), gomock.Eq("rick"), gomock.Eq(uint(70)), gomock.Any()).
+					Return(uint(1), nil)
+				r.
+					EXPECT().
+					Save(gomock.Eq(ctx), gomock.Eq("morty"), gomock.Eq(uint(14)), gomock.Any()).
+					Return(uint(0), ErrorSavingToDB)
+			},
+			users: []NewUser{{Name: "rick", Age: 70}, {Name: "summer", Age: 200}, {Name: "morty", Age: 14}},
+			mode:  BatchBestEffort,
+			expectedResults: []BatchResult{
+				{Index: 0, ID: 1},
+				{Index: 1, Error: ErrorInvalidAge.Error()},
+				{Index: 2, Error: ErrorSavingToDB.Error()},
+			},
+		},
+		{
+			name:              "save batch service test too large",
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {},
+			users:             make([]NewUser, MaxBatchSize+1),
+			mode:              BatchBestEffort,
+			expectedError:     ErrorBatchTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx := serviceCtx
+			ctrl := gomock.NewController(t)
+			repository := NewMockRepository(ctrl)
+
+			tt.executeBeforeTest(ctx, repository)
+
+			qkvs, _ := kvs.NewQueryableClient("")
+			service := NewService(repository, NewKVSProjection(qkvs))
+
+			// when
+			results, err := service.SaveBatch(ctx, tt.users, tt.mode)
+
+			// then
+			require.Equal(t, tt.expectedError, err)
+			require.Equal(t, tt.expectedResults, results)
+		})
+	}
+}
+
+func TestPurgeJobPurge(t *testing.T) {
+	// given
+	ctx := context.Background()
+	now := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	retention := 24 * time.Hour
+
+	ctrl := gomock.NewController(t)
+	repository := NewMockRepository(ctrl)
+	repository.
+		EXPECT().
+		Purge(gomock.Eq(ctx), gomock.Eq(now.Add(-retention))).
+		Return(int64(3), nil)
+
+	job := NewPurgeJob(repository, retention)
+	job.now = func() time.Time { return now }
+
+	// when
+	purged, err := job.Purge(ctx)
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, int64(3), purged)
+}
+
+func TestServiceExport(t *testing.T) {
+	fullPage := make([]User, exportPageSize)
+	for i := range fullPage {
+		fullPage[i] = User{ID: uint(i + 1)}
+	}
+
+	tests := []struct {
+		name              string
+		ctx               context.Context
+		executeBeforeTest func(r *MockRepository)
+		expectedCount     int
+		expectedError     error
+	}{
+		{
+			name: "pages after the last id until a short page",
+			ctx:  adminCtx,
+			executeBeforeTest: func(r *MockRepository) {
+				gomock.InOrder(
+					r.EXPECT().ListAfter(adminCtx, uint(0), uint(exportPageSize), false).Return(fullPage, nil),
+					r.EXPECT().ListAfter(adminCtx, uint(exportPageSize), uint(exportPageSize), false).Return([]User{{ID: 600}}, nil),
+				)
+			},
+			expectedCount: exportPageSize + 1,
+		},
+		{
+			name: "repository failure",
+			ctx:  adminCtx,
+			executeBeforeTest: func(r *MockRepository) {
+				r.EXPECT().ListAfter(adminCtx, uint(0), uint(exportPageSize), false).Return(nil, ErrorSavingToDB)
+			},
+			expectedError: ErrorSavingToDB,
+		},
+		{
+			name:              "denied to users",
+			ctx:               ownerCtx,
+			executeBeforeTest: func(r *MockRepository) {},
+			expectedError:     authz.ErrorDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			r := NewMockRepository(ctrl)
+			tt.executeBeforeTest(r)
+			s := NewService(r, nil)
+
+			// when
+			count := 0
+			err := s.Export(tt.ctx, false, func(User) error {
+				count++
+				return nil
+			})
+
+			// then
+			require.ErrorIs(t, err, tt.expectedError)
+			require.Equal(t, tt.expectedCount, count)
+		})
+	}
+}
+
+func TestCachedServiceFind(t *testing.T) {
+	user := User{ID: 1, Name: "name", Age: 30, ActivationCode: "ABC123"}
+
+	tests := []struct {
+		name              string
+		executeBeforeTest func(s *MockService, r *mockkvs.MockQueryableClient)
+		ctx               context.Context
+		expectedUser      User
+		expectedError     error
+	}{
+		{
+			name: "find loads once and caches the user without its activation code",
+			executeBeforeTest: func(s *MockService, r *mockkvs.MockQueryableClient) {
+				r.EXPECT().Get(gomock.Any(), gomock.Eq("users:1")).Return(kvs.Item{}, kvs.ErrKeyNotFound)
+				r.EXPECT().Set(gomock.Any(), gomock.Eq("users:1"), gomock.Any()).Return(nil)
+				s.EXPECT().Find(gomock.Any(), gomock.Eq(uint(1)), gomock.Eq(false)).Return(user, nil).Times(1)
+			},
+			ctx:          ownerCtx,
+			expectedUser: User{ID: 1, Name: "name", Age: 30},
+		},
+		{
+			name: "find doesn't cache the users not found",
+			executeBeforeTest: func(s *MockService, r *mockkvs.MockQueryableClient) {
+				r.EXPECT().Get(gomock.Any(), gomock.Eq("users:1")).Return(kvs.Item{}, kvs.ErrKeyNotFound).Times(2)
+				s.EXPECT().Find(gomock.Any(), gomock.Eq(uint(1)), gomock.Eq(false)).Return(User{}, ErrorUserNotFound).Times(2)
+			},
+			ctx:           adminCtx,
+			expectedError: ErrorUserNotFound,
+		},
+		{
+			name:              "find authorizes the cached users",
+			executeBeforeTest: func(s *MockService, r *mockkvs.MockQueryableClient) {},
+			ctx:               serviceCtx,
+			expectedError:     authz.ErrorDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			next := NewMockService(ctrl)
+			remote := mockkvs.NewMockQueryableClient(ctrl)
+
+			tt.executeBeforeTest(next, remote)
+
+			c := cache.New(CacheName, cache.Config{Size: 10, LocalTTL: time.Minute, RemoteTTL: time.Minute}, remote, cache.NewMockBroadcaster(ctrl))
+			s := NewCachedService(next, c)
+
+			for i := 0; i < 2; i++ {
+				// when
+				user, err := s.Find(tt.ctx, 1, false)
+
+				// then
+				require.True(t, errors.Is(err, tt.expectedError))
+				require.Equal(t, tt.expectedUser, user)
+			}
+		})
+	}
+}
+
+func TestCachedServiceUpdate(t *testing.T) {
+	// given
+	ctrl := gomock.NewController(t)
+	next := NewMockService(ctrl)
+	next.EXPECT().Update(gomock.Eq(adminCtx), gomock.Eq(uint(1)), gomock.Eq("name"), gomock.Eq(uint(30))).Return(nil)
+
+	remote := mockkvs.NewMockQueryableClient(ctrl)
+	remote.EXPECT().Delete(gomock.Eq(adminCtx), gomock.Eq("users:1")).Return(true, nil)
+
+	broadcaster := cache.NewMockBroadcaster(ctrl)
+	broadcaster.EXPECT().Broadcast(gomock.Eq(adminCtx), gomock.Eq(CacheName), gomock.Eq("users:1")).Return(nil)
+
+	s := NewCachedService(next, cache.New(CacheName, cache.Config{Size: 10, LocalTTL: time.Minute, RemoteTTL: time.Minute}, remote, broadcaster))
+
+	// when
+	err := s.Update(adminCtx, 1, "name", 30)
+
+	// then
+	require.NoError(t, err)
+}
+
+func TestProjectedRepositoryUpdate(t *testing.T) {
+	// given
+	ctrl := gomock.NewController(t)
+	user := User{ID: 1, Name: "name", Age: 30}
+
+	next := NewMockRepository(ctrl)
+	next.EXPECT().Update(gomock.Eq(adminCtx), gomock.Eq(uint(1)), gomock.Eq("name"), gomock.Eq(uint(30))).Return(nil)
+	next.EXPECT().FindMany(gomock.Eq(database.WithPrimary(adminCtx)), gomock.Eq([]uint{1})).Return([]User{user}, nil)
+
+	projection := NewMockProjection(ctrl)
+	projection.EXPECT().Put(gomock.Any(), gomock.Eq([]User{user})).Return(nil)
+
+	r := NewProjectedRepository(next, projection)
+
+	// when
+	err := r.Update(adminCtx, 1, "name", 30)
+
+	// then
+	require.NoError(t, err)
+}
+
+func TestKVSProjectionListByAge(t *testing.T) {
+	activatedAt := time.Now()
+	pending := User{ID: 2, Name: "pending", Age: 30}
+	active := User{ID: 3, Name: "active", Age: 41, ActivatedAt: &activatedAt}
+	older := User{ID: 1, Name: "older", Age: 52}
+
+	tests := []struct {
+		name              string
+		executeBeforeTest func(r *mockkvs.MockQueryableClient)
+		filter            AgeFilter
+		expectedUsers     []User
+	}{
+		{
+			name: "list by age ranges the age index from the minimum age",
+			executeBeforeTest: func(r *mockkvs.MockQueryableClient) {
+				r.EXPECT().
+					Range(gomock.Any(), gomock.Eq(kvs.Query{From: "users:index:age:00000000000000000028", To: "users:index:age:~"}), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ kvs.Query, fn func([]kvs.Item, error) error) error {
+						return fn([]kvs.Item{
+							{Key: indexKeys(pending)[0], Value: pending},
+							{Key: indexKeys(active)[0], Value: active},
+							{Key: indexKeys(older)[0], Value: older},
+						}, nil)
+					})
+			},
+			filter:        AgeFilter{MinAge: 28, Limit: 10},
+			expectedUsers: []User{pending, active, older},
+		},
+		{
+			name: "list by age and status stops ranging once the page is full",
+			executeBeforeTest: func(r *mockkvs.MockQueryableClient) {
+				r.EXPECT().
+					Range(gomock.Any(), gomock.Eq(kvs.Query{From: "users:index:status:active:00000000000000000028", To: "users:index:status:active:~"}), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ kvs.Query, fn func([]kvs.Item, error) error) error {
+						if err := fn([]kvs.Item{{Key: indexKeys(active)[1], Value: active}}, nil); err != nil {
+							return err
+						}
+						return fn([]kvs.Item{{Key: indexKeys(older)[1], Value: older}}, nil)
+					})
+			},
+			filter:        AgeFilter{MinAge: 28, Status: StatusActive, Limit: 1},
+			expectedUsers: []User{active},
+		},
+		{
+			name: "list by age skips the offset",
+			executeBeforeTest: func(r *mockkvs.MockQueryableClient) {
+				r.EXPECT().
+					Range(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ kvs.Query, fn func([]kvs.Item, error) error) error {
+						return fn([]kvs.Item{
+							{Key: indexKeys(pending)[0], Value: pending},
+							{Key: indexKeys(active)[0], Value: active},
+						}, nil)
+					})
+			},
+			filter:        AgeFilter{Limit: 10, Offset: 1},
+			expectedUsers: []User{active},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			qkvs := mockkvs.NewMockQueryableClient(ctrl)
+			tt.executeBeforeTest(qkvs)
+
+			projection := NewKVSProjection(qkvs)
+
+			// when
+			list, err := projection.ListByAge(context.Background(), tt.filter)
+
+			// then
+			require.NoError(t, err)
+			require.Len(t, list, len(tt.expectedUsers))
+			for i, user := range tt.expectedUsers {
+				require.Equal(t, user.ID, list[i].ID)
+			}
+		})
+	}
+}
+
+func TestKVSProjectionPut(t *testing.T) {
+	// given
+	old := User{ID: 1, Name: "name", Age: 30}
+	user := User{ID: 1, Name: "name", Age: 31, ActivationCode: "ABC123"}
+	projected := User{ID: 1, Name: "name", Age: 31}
+
+	ctrl := gomock.NewController(t)
+	qkvs := mockkvs.NewMockQueryableClient(ctrl)
+	qkvs.EXPECT().
+		BatchGet(gomock.Any(), gomock.Eq([]string{projectionKey(1)})).
+		Return(map[string]kvs.Item{projectionKey(1): {Key: projectionKey(1), Value: old}}, nil)
+	qkvs.EXPECT().
+		BatchSet(gomock.Any(), gomock.Eq([]kvs.Item{
+			{Key: projectionKey(1), Value: projected},
+			{Key: "users:index:age:00000000000000000031:00000000000000000001", Value: projected},
+			{Key: "users:index:status:pending:00000000000000000031:00000000000000000001", Value: projected},
+		})).
+		Return(nil)
+	qkvs.EXPECT().
+		BatchDelete(gomock.Any(), gomock.Eq([]string{
+			"users:index:age:00000000000000000030:00000000000000000001",
+			"users:index:status:pending:00000000000000000030:00000000000000000001",
+		})).
+		Return(nil)
+
+	projection := NewKVSProjection(qkvs)
+
+	// when
+	err := projection.Put(context.Background(), []User{user})
 
+	// then
+	require.NoError(t, err)
+}
+
+func TestServiceBatchGet(t *testing.T) {
+	tests := []struct {
+		name              string
+		executeBeforeTest func(r *MockRepository, p *MockProjection)
+		ctx               context.Context
+		ids               []uint
+		expectedResult    BatchGetResult
+		expectedError     error
+	}{
+		{
+			name: "batch get keeps the order asked for and reports the missing users",
+			executeBeforeTest: func(r *MockRepository, p *MockProjection) {
+				p.EXPECT().
+					Get(gomock.Eq(adminCtx), gomock.Eq([]uint{3, 1, 2})).
+					Return(map[uint]User{3: {ID: 3, Name: "three"}}, nil)
+				r.EXPECT().
+					Exist(gomock.Eq(adminCtx), gomock.Eq([]uint{3})).
+					Return(map[uint]bool{3: true}, nil)
+				r.EXPECT().
+					FindMany(gomock.Eq(adminCtx), gomock.Eq([]uint{1, 2})).
+					Return([]User{{ID: 1, Name: "one", ActivationCode: "ABC123"}}, nil)
+			},
+			ctx:            adminCtx,
+			ids:            []uint{3, 1, 3, 2},
+			expectedResult: BatchGetResult{Users: []User{{ID: 3, Name: "three"}, {ID: 1, Name: "one"}}, Missing: []uint{2}},
+		},
+		{
+			name: "batch get reads the database when KVS fails",
+			executeBeforeTest: func(r *MockRepository, p *MockProjection) {
+				p.EXPECT().
+					Get(gomock.Eq(ownerCtx), gomock.Eq([]uint{1})).
+					Return(nil, errors.New("timeout"))
+				r.EXPECT().
+					FindMany(gomock.Eq(ownerCtx), gomock.Eq([]uint{1})).
+					Return([]User{{ID: 1, Name: "one"}}, nil)
+			},
+			ctx:            ownerCtx,
+			ids:            []uint{1},
+			expectedResult: BatchGetResult{Users: []User{{ID: 1, Name: "one"}}, Missing: []uint{}},
+		},
+		{
+			name: "batch get reports the users deleted since they were projected as missing",
+			executeBeforeTest: func(r *MockRepository, p *MockProjection) {
+				deletedAt := time.Now()
+				p.EXPECT().
+					Get(gomock.Eq(adminCtx), gomock.Eq([]uint{1, 2})).
+					Return(map[uint]User{1: {ID: 1, Name: "one", DeletedAt: &deletedAt}, 2: {ID: 2, Name: "two"}}, nil)
+				r.EXPECT().
+					Exist(gomock.Eq(adminCtx), gomock.Eq([]uint{2})).
+					Return(map[uint]bool{}, nil)
+				r.EXPECT().
+					FindMany(gomock.Eq(adminCtx), gomock.Eq([]uint{1})).
+					Return(nil, nil)
+			},
+			ctx:            adminCtx,
+			ids:            []uint{1, 2},
+			expectedResult: BatchGetResult{Users: []User{}, Missing: []uint{1, 2}},
+		},
+		{
+			name:              "batch get rejects too many ids",
+			executeBeforeTest: func(r *MockRepository, p *MockProjection) {},
+			ctx:               adminCtx,
+			ids:               make([]uint, MaxBatchGetSize+1),
+			expectedError:     ErrorTooManyIDs,
+		},
+		{
+			name:              "batch get checks the ids before authorizing them",
+			executeBeforeTest: func(r *MockRepository, p *MockProjection) {},
+			ctx:               ownerCtx,
+			ids:               make([]uint, MaxBatchGetSize+1),
+			expectedError:     ErrorTooManyIDs,
+		},
+		{
+			name: "batch get denies the users the caller can't read without failing the others",
+			executeBeforeTest: func(r *MockRepository, p *MockProjection) {
+				p.EXPECT().
+					Get(gomock.Eq(ownerCtx), gomock.Eq([]uint{1})).
+					Return(map[uint]User{1: {ID: 1, Name: "one"}}, nil)
+				r.EXPECT().
+					Exist(gomock.Eq(ownerCtx), gomock.Eq([]uint{1})).
+					Return(map[uint]bool{1: true}, nil)
+			},
+			ctx: ownerCtx,
+			ids: []uint{1, 2},
+			expectedResult: BatchGetResult{
+				Users:   []User{{ID: 1, Name: "one"}},
+				Missing: []uint{},
+				Denied:  map[uint]error{2: &authz.DeniedError{Action: actionRead, Reason: authz.ReasonNotOwner}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := gomock.NewController(t)
+			repository := NewMockRepository(ctrl)
+			projection := NewMockProjection(ctrl)
+			tt.executeBeforeTest(repository, projection)
+
+			service := NewService(repository, projection)
+
+			// when
+			result, err := service.BatchGet(tt.ctx, tt.ids)
+
+			// then
+			require.True(t, errors.Is(err, tt.expectedError))
+			require.Equal(t, tt.expectedResult, result)
+		})
+	}
 }
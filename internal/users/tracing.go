@@ -0,0 +1,118 @@
+package users
+
+import (
+	"context"
+
+	"github.com/johan-ag/testing/internal/platform/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewTracedService runs each method of next in a span named after it.
+func NewTracedService(next Service) *tracedService {
+	return &tracedService{
+		next,
+	}
+}
+
+type tracedService struct {
+	next Service
+}
+
+func (s *tracedService) Save(ctx context.Context, name string, age uint) (id uint, err error) {
+	ctx, span := tracing.Start(ctx, "users.Service/Save")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Save(ctx, name, age)
+}
+
+func (s *tracedService) SaveBatch(ctx context.Context, users []NewUser, mode BatchMode) (results []BatchResult, err error) {
+	ctx, span := tracing.Start(ctx, "users.Service/SaveBatch")
+	span.SetAttributes(
+		attribute.Int("batch.size", len(users)),
+		attribute.String("batch.mode", string(mode)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.SaveBatch(ctx, users, mode)
+}
+
+func (s *tracedService) Find(ctx context.Context, id uint, includeDeleted bool) (user User, err error) {
+	ctx, span := start(ctx, "Find", id)
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Find(ctx, id, includeDeleted)
+}
+
+func (s *tracedService) FindMany(ctx context.Context, ids []uint) (list []User, denied map[uint]error, err error) {
+	ctx, span := tracing.Start(ctx, "users.Service/FindMany")
+	span.SetAttributes(attribute.Int("user.count", len(ids)))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.FindMany(ctx, ids)
+}
+
+func (s *tracedService) BatchGet(ctx context.Context, ids []uint) (result BatchGetResult, err error) {
+	ctx, span := tracing.Start(ctx, "users.Service/BatchGet")
+	span.SetAttributes(attribute.Int("user.count", len(ids)))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.BatchGet(ctx, ids)
+}
+
+func (s *tracedService) List(ctx context.Context, filter ListFilter) (list []User, err error) {
+	ctx, span := tracing.Start(ctx, "users.Service/List")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.List(ctx, filter)
+}
+
+func (s *tracedService) ListByAge(ctx context.Context, filter AgeFilter) (list []User, err error) {
+	ctx, span := tracing.Start(ctx, "users.Service/ListByAge")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.ListByAge(ctx, filter)
+}
+
+func (s *tracedService) Export(ctx context.Context, includeDeleted bool, fn func(User) error) (err error) {
+	ctx, span := tracing.Start(ctx, "users.Service/Export")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Export(ctx, includeDeleted, fn)
+}
+
+func (s *tracedService) Delete(ctx context.Context, id uint) (err error) {
+	ctx, span := start(ctx, "Delete", id)
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Delete(ctx, id)
+}
+
+func (s *tracedService) Restore(ctx context.Context, id uint) (err error) {
+	ctx, span := start(ctx, "Restore", id)
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Restore(ctx, id)
+}
+
+func (s *tracedService) Update(ctx context.Context, id uint, name string, age uint) (err error) {
+	ctx, span := start(ctx, "Update", id)
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Update(ctx, id, name, age)
+}
+
+func (s *tracedService) Activate(ctx context.Context, id uint, code string) (err error) {
+	ctx, span := start(ctx, "Activate", id)
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Activate(ctx, id, code)
+}
+
+// start starts the span of a method called for the user id.
+func start(ctx context.Context, method string, id uint) (context.Context, trace.Span) {
+	ctx, span := tracing.Start(ctx, "users.Service/"+method)
+	span.SetAttributes(attribute.Int64("user.id", int64(id)))
+
+	return ctx, span
+}
@@ -0,0 +1,78 @@
+package webhooks
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// Resolver looks up the addresses of a host, *net.Resolver satisfies it.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// NewClient returns the client that sends the deliveries. It refuses to connect to the
+// addresses that aren't public, whatever the url of the subscription resolves to at the
+// time of sending, and it never goes through a proxy.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// control is run by the dialer before connecting to address, an ip and port.
+func control(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !public(ip) {
+		return ErrorPrivateAddress
+	}
+
+	return nil
+}
+
+// checkHost fails with ErrorPrivateAddress when host, a name or an ip, has any address
+// that isn't public.
+func checkHost(ctx context.Context, resolver Resolver, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !public(ip) {
+			return ErrorPrivateAddress
+		}
+		return nil
+	}
+
+	addresses, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addresses) == 0 {
+		return ErrorInvalidURL
+	}
+
+	for _, address := range addresses {
+		if !public(address.IP) {
+			return ErrorPrivateAddress
+		}
+	}
+
+	return nil
+}
+
+// public tells whether ip is a unicast address that isn't loopback, link-local, private
+// nor unspecified.
+func public(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate()
+}
@@ -0,0 +1,162 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/johan-ag/testing/internal/outbox"
+	"github.com/johan-ag/testing/internal/platform/logging"
+	"github.com/mercadolibre/fury_go-core/pkg/log"
+)
+
+const (
+	// batchSize is how many deliveries a dispatch round claims.
+	batchSize = 50
+
+	// claimTimeout is how long the deliveries claimed by a round wait before they are due
+	// again, in case the dispatcher stops before recording how they went.
+	claimTimeout = 5 * time.Minute
+)
+
+// Doer sends http requests, *http.Client satisfies it.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Dispatcher sends the pending deliveries signed with the secret of their subscription,
+// retrying the failed ones with backoff until maxAttempts, when they are marked as failed.
+type Dispatcher struct {
+	repository  Repository
+	client      Doer
+	maxAttempts uint
+	now         func() time.Time
+}
+
+func NewDispatcher(repository Repository, client Doer, maxAttempts uint) *Dispatcher {
+	return &Dispatcher{
+		repository,
+		client,
+		maxAttempts,
+		time.Now,
+	}
+}
+
+// Run dispatches the pending deliveries once per interval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.Dispatch(ctx); err != nil {
+				logging.Error(ctx, "cannot dispatch webhook deliveries", log.Err(err))
+			}
+		}
+	}
+}
+
+// Dispatch sends a batch of the pending deliveries and returns how many succeeded. The
+// batch is claimed in a transaction of its own, so that no row stays locked while the
+// deliveries are sent.
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	var claimed []Delivery
+	err := d.repository.WithinTx(ctx, func(repository Repository) error {
+		deliveries, err := repository.PendingDeliveries(ctx, d.now(), batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, delivery := range deliveries {
+			delivery.Attempts++
+			if err := repository.Claim(ctx, delivery.ID, delivery.Attempts, d.now().Add(claimTimeout)); err != nil {
+				return err
+			}
+			claimed = append(claimed, delivery)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	succeeded := 0
+	for _, delivery := range claimed {
+		subscription, err := d.repository.FindSubscription(ctx, delivery.SubscriptionID)
+		if errors.Is(err, ErrorSubscriptionNotFound) {
+			if err := d.repository.MarkFailed(ctx, delivery.ID, delivery.Attempts, 0, err.Error()); err != nil {
+				return succeeded, err
+			}
+			continue
+		}
+		if err != nil {
+			return succeeded, err
+		}
+
+		status, err := d.send(ctx, subscription, delivery)
+		if err != nil {
+			if err := d.fail(ctx, delivery, status, err); err != nil {
+				return succeeded, err
+			}
+			continue
+		}
+
+		if err := d.repository.MarkSucceeded(ctx, delivery.ID, delivery.Attempts, status, d.now()); err != nil {
+			return succeeded, err
+		}
+		succeeded++
+	}
+
+	return succeeded, nil
+}
+
+// send posts the delivery to the subscription and returns the response status.
+func (d *Dispatcher) send(ctx context.Context, subscription Subscription, delivery Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-ID", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Event-ID", strconv.FormatUint(uint64(delivery.EventID), 10))
+	req.Header.Set("X-Event-Type", delivery.EventType)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("%w: %d", ErrorUnexpectedStatus, resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// fail retries the delivery with backoff, or gives up on it once it made maxAttempts.
+func (d *Dispatcher) fail(ctx context.Context, delivery Delivery, status int, cause error) error {
+	if delivery.Attempts >= d.maxAttempts {
+		logging.Warn(ctx, "webhook delivery failed",
+			log.Uint("delivery_id", delivery.ID),
+			log.Uint("subscription_id", delivery.SubscriptionID),
+			log.Uint("attempts", delivery.Attempts),
+			log.Err(cause),
+		)
+		return d.repository.MarkFailed(ctx, delivery.ID, delivery.Attempts, status, cause.Error())
+	}
+
+	return d.repository.Retry(ctx, delivery.ID, delivery.Attempts, status, cause.Error(), d.now().Add(outbox.Backoff(delivery.Attempts)))
+}
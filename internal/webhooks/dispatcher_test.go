@@ -0,0 +1,112 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcherDispatch(t *testing.T) {
+	now := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	secret := "secret"
+	payload := []byte(`{"id":1,"type":"UserCreated"}`)
+
+	tests := []struct {
+		name              string
+		status            int
+		attempts          uint
+		executeBeforeTest func(ctx context.Context, r *MockRepository, delivery Delivery)
+		expectedSucceeded int
+	}{
+		{
+			name:   "dispatch test succeeded",
+			status: http.StatusOK,
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, delivery Delivery) {
+				r.EXPECT().MarkSucceeded(gomock.Eq(ctx), gomock.Eq(delivery.ID), gomock.Eq(uint(1)), gomock.Eq(http.StatusOK), gomock.Eq(now)).Return(nil)
+			},
+			expectedSucceeded: 1,
+		},
+		{
+			name:     "dispatch test retried",
+			status:   http.StatusServiceUnavailable,
+			attempts: 1,
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, delivery Delivery) {
+				r.EXPECT().Retry(gomock.Eq(ctx), gomock.Eq(delivery.ID), gomock.Eq(uint(2)), gomock.Eq(http.StatusServiceUnavailable), gomock.Any(), gomock.Eq(now.Add(2*time.Second))).Return(nil)
+			},
+			expectedSucceeded: 0,
+		},
+		{
+			name:     "dispatch test failed",
+			status:   http.StatusBadRequest,
+			attempts: 2,
+			executeBeforeTest: func(ctx context.Context, r *MockRepository, delivery Delivery) {
+				r.EXPECT().MarkFailed(gomock.Eq(ctx), gomock.Eq(delivery.ID), gomock.Eq(uint(3)), gomock.Eq(http.StatusBadRequest), gomock.Any()).Return(nil)
+			},
+			expectedSucceeded: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var (
+				body      []byte
+				timestamp string
+				signature string
+			)
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+				timestamp = r.Header.Get(TimestampHeader)
+				signature = r.Header.Get(SignatureHeader)
+				w.WriteHeader(tt.status)
+			}))
+			defer receiver.Close()
+
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			repository := NewMockRepository(ctrl)
+
+			delivery := Delivery{ID: 5, SubscriptionID: 2, EventID: 1, EventType: "UserCreated", Payload: payload, Attempts: tt.attempts}
+
+			repository.
+				EXPECT().
+				WithinTx(gomock.Eq(ctx), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(Repository) error) error {
+					return fn(repository)
+				})
+			repository.
+				EXPECT().
+				PendingDeliveries(gomock.Eq(ctx), gomock.Eq(now), gomock.Eq(uint(batchSize))).
+				Return([]Delivery{delivery}, nil)
+			repository.
+				EXPECT().
+				Claim(gomock.Eq(ctx), gomock.Eq(delivery.ID), gomock.Eq(tt.attempts+1), gomock.Eq(now.Add(claimTimeout))).
+				Return(nil)
+			repository.
+				EXPECT().
+				FindSubscription(gomock.Eq(ctx), gomock.Eq(delivery.SubscriptionID)).
+				Return(Subscription{ID: 2, URL: receiver.URL, Secret: secret}, nil)
+			tt.executeBeforeTest(ctx, repository, delivery)
+
+			dispatcher := NewDispatcher(repository, receiver.Client(), 3)
+			dispatcher.now = func() time.Time { return now }
+
+			// when
+			succeeded, err := dispatcher.Dispatch(ctx)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedSucceeded, succeeded)
+			require.Equal(t, payload, body)
+			require.Equal(t, strconv.FormatInt(now.Unix(), 10), timestamp)
+			require.True(t, Verify(secret, now.Unix(), body, signature))
+		})
+	}
+}
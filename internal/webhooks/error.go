@@ -0,0 +1,15 @@
+package webhooks
+
+import (
+	"errors"
+)
+
+var (
+	ErrorSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrorDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrorInvalidURL           = errors.New("webhook url must be an absolute http or https url")
+	ErrorPrivateAddress       = errors.New("webhook url must not resolve to a loopback, link-local or private address")
+	ErrorInvalidEventTypes    = errors.New("webhook event types must be a non empty list of known events")
+	ErrorUnexpectedStatus     = errors.New("unexpected webhook response status")
+	ErrorSavingToDB           = errors.New("error saving webhook to db")
+)
@@ -0,0 +1,337 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/johan-ag/testing/internal/webhooks (interfaces: Repository,Service)
+
+// Package webhooks is a generated GoMock package.
+package webhooks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockRepository) Claim(arg0 context.Context, arg1, arg2 uint, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockRepositoryMockRecorder) Claim(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockRepository)(nil).Claim), arg0, arg1, arg2, arg3)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockRepository) DeleteSubscription(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockRepositoryMockRecorder) DeleteSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockRepository)(nil).DeleteSubscription), arg0, arg1)
+}
+
+// FindDelivery mocks base method.
+func (m *MockRepository) FindDelivery(arg0 context.Context, arg1, arg2 uint) (Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDelivery", arg0, arg1, arg2)
+	ret0, _ := ret[0].(Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDelivery indicates an expected call of FindDelivery.
+func (mr *MockRepositoryMockRecorder) FindDelivery(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDelivery", reflect.TypeOf((*MockRepository)(nil).FindDelivery), arg0, arg1, arg2)
+}
+
+// FindSubscription mocks base method.
+func (m *MockRepository) FindSubscription(arg0 context.Context, arg1 uint) (Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSubscription", arg0, arg1)
+	ret0, _ := ret[0].(Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSubscription indicates an expected call of FindSubscription.
+func (mr *MockRepositoryMockRecorder) FindSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSubscription", reflect.TypeOf((*MockRepository)(nil).FindSubscription), arg0, arg1)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockRepository) ListDeliveries(arg0 context.Context, arg1, arg2 uint) ([]Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockRepositoryMockRecorder) ListDeliveries(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockRepository)(nil).ListDeliveries), arg0, arg1, arg2)
+}
+
+// ListSubscriptions mocks base method.
+func (m *MockRepository) ListSubscriptions(arg0 context.Context) ([]Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptions", arg0)
+	ret0, _ := ret[0].([]Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptions indicates an expected call of ListSubscriptions.
+func (mr *MockRepositoryMockRecorder) ListSubscriptions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptions", reflect.TypeOf((*MockRepository)(nil).ListSubscriptions), arg0)
+}
+
+// MarkFailed mocks base method.
+func (m *MockRepository) MarkFailed(arg0 context.Context, arg1, arg2 uint, arg3 int, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockRepositoryMockRecorder) MarkFailed(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockRepository)(nil).MarkFailed), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MarkSucceeded mocks base method.
+func (m *MockRepository) MarkSucceeded(arg0 context.Context, arg1, arg2 uint, arg3 int, arg4 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSucceeded", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSucceeded indicates an expected call of MarkSucceeded.
+func (mr *MockRepositoryMockRecorder) MarkSucceeded(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSucceeded", reflect.TypeOf((*MockRepository)(nil).MarkSucceeded), arg0, arg1, arg2, arg3, arg4)
+}
+
+// PendingDeliveries mocks base method.
+func (m *MockRepository) PendingDeliveries(arg0 context.Context, arg1 time.Time, arg2 uint) ([]Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingDeliveries", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingDeliveries indicates an expected call of PendingDeliveries.
+func (mr *MockRepositoryMockRecorder) PendingDeliveries(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingDeliveries", reflect.TypeOf((*MockRepository)(nil).PendingDeliveries), arg0, arg1, arg2)
+}
+
+// Retry mocks base method.
+func (m *MockRepository) Retry(arg0 context.Context, arg1, arg2 uint, arg3 int, arg4 string, arg5 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Retry indicates an expected call of Retry.
+func (mr *MockRepositoryMockRecorder) Retry(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockRepository)(nil).Retry), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// SaveDelivery mocks base method.
+func (m *MockRepository) SaveDelivery(arg0 context.Context, arg1 Delivery) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDelivery", arg0, arg1)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveDelivery indicates an expected call of SaveDelivery.
+func (mr *MockRepositoryMockRecorder) SaveDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDelivery", reflect.TypeOf((*MockRepository)(nil).SaveDelivery), arg0, arg1)
+}
+
+// SaveSubscription mocks base method.
+func (m *MockRepository) SaveSubscription(arg0 context.Context, arg1 Subscription) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSubscription", arg0, arg1)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveSubscription indicates an expected call of SaveSubscription.
+func (mr *MockRepositoryMockRecorder) SaveSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSubscription", reflect.TypeOf((*MockRepository)(nil).SaveSubscription), arg0, arg1)
+}
+
+// WithinTx mocks base method.
+func (m *MockRepository) WithinTx(arg0 context.Context, arg1 func(Repository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockRepositoryMockRecorder) WithinTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockRepository)(nil).WithinTx), arg0, arg1)
+}
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// Deliveries mocks base method.
+func (m *MockService) Deliveries(arg0 context.Context, arg1 uint) ([]Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deliveries", arg0, arg1)
+	ret0, _ := ret[0].([]Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deliveries indicates an expected call of Deliveries.
+func (mr *MockServiceMockRecorder) Deliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deliveries", reflect.TypeOf((*MockService)(nil).Deliveries), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockService) Find(arg0 context.Context, arg1 uint) (Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockServiceMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockService)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockService) List(arg0 context.Context) ([]Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].([]Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockServiceMockRecorder) List(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockService)(nil).List), arg0)
+}
+
+// Replay mocks base method.
+func (m *MockService) Replay(arg0 context.Context, arg1, arg2 uint) (Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", arg0, arg1, arg2)
+	ret0, _ := ret[0].(Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replay indicates an expected call of Replay.
+func (mr *MockServiceMockRecorder) Replay(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockService)(nil).Replay), arg0, arg1, arg2)
+}
+
+// Subscribe mocks base method.
+func (m *MockService) Subscribe(arg0 context.Context, arg1 string, arg2 []string) (Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", arg0, arg1, arg2)
+	ret0, _ := ret[0].(Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockServiceMockRecorder) Subscribe(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockService)(nil).Subscribe), arg0, arg1, arg2)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockService) Unsubscribe(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockServiceMockRecorder) Unsubscribe(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockService)(nil).Unsubscribe), arg0, arg1)
+}
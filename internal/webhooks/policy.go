@@ -0,0 +1,36 @@
+package webhooks
+
+import (
+	"strconv"
+
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+)
+
+// Actions authorized by policy.
+const (
+	actionCreate = "webhooks:create"
+	actionRead   = "webhooks:read"
+	actionList   = "webhooks:list"
+	actionDelete = "webhooks:delete"
+	actionReplay = "webhooks:replay"
+)
+
+// entityName names the subscriptions in the authorization decisions.
+const entityName = "webhook_subscription"
+
+// policy lets only admins manage the subscriptions, which receive every event they ask for.
+var policy = authz.Policy{
+	actionCreate: {authz.HasRole(auth.RoleAdmin)},
+	actionRead:   {authz.HasRole(auth.RoleAdmin)},
+	actionList:   {authz.HasRole(auth.RoleAdmin)},
+	actionDelete: {authz.HasRole(auth.RoleAdmin)},
+	actionReplay: {authz.HasRole(auth.RoleAdmin)},
+}
+
+func resource(id uint) authz.Resource {
+	return authz.Resource{
+		Type: entityName,
+		ID:   strconv.FormatUint(uint64(id), 10),
+	}
+}
@@ -0,0 +1,52 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/johan-ag/testing/internal/outbox"
+)
+
+// NewPublisher returns an outbox publisher that queues a delivery of each event for every
+// subscription to its type, the Dispatcher sends them.
+func NewPublisher(repository Repository) *publisher {
+	return &publisher{
+		repository,
+	}
+}
+
+type publisher struct {
+	repository Repository
+}
+
+func (p *publisher) Publish(ctx context.Context, event outbox.Event) error {
+	subscriptions, err := p.repository.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.repository.WithinTx(ctx, func(repository Repository) error {
+		for _, subscription := range subscriptions {
+			if !subscription.Subscribes(event.Type) {
+				continue
+			}
+
+			_, err := repository.SaveDelivery(ctx, Delivery{
+				SubscriptionID: subscription.ID,
+				EventID:        event.ID,
+				EventType:      event.Type,
+				Payload:        payload,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,327 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/johan-ag/testing/internal/platform/database"
+)
+
+type Repository interface {
+	SaveSubscription(ctx context.Context, subscription Subscription) (uint, error)
+	FindSubscription(ctx context.Context, id uint) (Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]Subscription, error)
+	DeleteSubscription(ctx context.Context, id uint) error
+	SaveDelivery(ctx context.Context, delivery Delivery) (uint, error)
+	FindDelivery(ctx context.Context, subscriptionID uint, id uint) (Delivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID uint, limit uint) ([]Delivery, error)
+	PendingDeliveries(ctx context.Context, due time.Time, limit uint) ([]Delivery, error)
+	Claim(ctx context.Context, id uint, attempts uint, lockedUntil time.Time) error
+	MarkSucceeded(ctx context.Context, id uint, attempts uint, responseStatus int, deliveredAt time.Time) error
+	Retry(ctx context.Context, id uint, attempts uint, responseStatus int, lastError string, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id uint, attempts uint, responseStatus int, lastError string) error
+	WithinTx(ctx context.Context, fn func(Repository) error) error
+}
+
+// Subscription is an endpoint registered to receive the given event types. The secret
+// signs the deliveries, it is only shown when the subscription is created.
+type Subscription struct {
+	ID         uint      `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Subscribes reports whether the subscription wants the events of eventType.
+func (s Subscription) Subscribes(eventType string) bool {
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Delivery is an event sent, or to be sent, to a subscription.
+type Delivery struct {
+	ID             uint            `json:"id"`
+	SubscriptionID uint            `json:"subscription_id"`
+	EventID        uint            `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       uint            `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+func NewRepository(queries *database.Queries) *repository {
+	return &repository{
+		queries,
+		time.Now,
+	}
+}
+
+type repository struct {
+	queries *database.Queries
+	now     func() time.Time
+}
+
+func (r *repository) SaveSubscription(ctx context.Context, subscription Subscription) (uint, error) {
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.queries.SaveWebhookSubscription(ctx, database.SaveWebhookSubscriptionParams{
+		Url:        subscription.URL,
+		EventTypes: eventTypes,
+		Secret:     subscription.Secret,
+		CreatedAt:  r.now().UTC(),
+	})
+	if err != nil {
+		return 0, ErrorSavingToDB
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(id), nil
+}
+
+func (r *repository) FindSubscription(ctx context.Context, id uint) (Subscription, error) {
+	s, err := r.queries.FindWebhookSubscription(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Subscription{}, ErrorSubscriptionNotFound
+	}
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	return toSubscription(s)
+}
+
+func (r *repository) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	rows, err := r.queries.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]Subscription, 0, len(rows))
+	for _, s := range rows {
+		subscription, err := toSubscription(s)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteSubscription stops the deliveries to the subscription, its history is kept.
+func (r *repository) DeleteSubscription(ctx context.Context, id uint) error {
+	result, err := r.queries.DeleteWebhookSubscription(ctx, database.DeleteWebhookSubscriptionParams{
+		DeletedAt: sql.NullTime{Time: r.now().UTC(), Valid: true},
+		ID:        int32(id),
+	})
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrorSubscriptionNotFound
+	}
+
+	return nil
+}
+
+func (r *repository) SaveDelivery(ctx context.Context, delivery Delivery) (uint, error) {
+	now := r.now().UTC()
+
+	result, err := r.queries.SaveWebhookDelivery(ctx, database.SaveWebhookDeliveryParams{
+		SubscriptionID: int32(delivery.SubscriptionID),
+		EventID:        int64(delivery.EventID),
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	})
+	if err != nil {
+		return 0, ErrorSavingToDB
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(id), nil
+}
+
+func (r *repository) FindDelivery(ctx context.Context, subscriptionID uint, id uint) (Delivery, error) {
+	d, err := r.queries.FindWebhookDelivery(ctx, database.FindWebhookDeliveryParams{
+		ID:             int64(id),
+		SubscriptionID: int32(subscriptionID),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Delivery{}, ErrorDeliveryNotFound
+	}
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	return toDelivery(d), nil
+}
+
+// ListDeliveries returns the latest deliveries of the subscription, newest first.
+func (r *repository) ListDeliveries(ctx context.Context, subscriptionID uint, limit uint) ([]Delivery, error) {
+	rows, err := r.queries.ListWebhookDeliveries(ctx, database.ListWebhookDeliveriesParams{
+		SubscriptionID: int32(subscriptionID),
+		Limit:          int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toDeliveries(rows), nil
+}
+
+// PendingDeliveries returns the deliveries due to be sent and locks them until the
+// transaction ends, call it within WithinTx so that concurrent dispatchers skip them.
+func (r *repository) PendingDeliveries(ctx context.Context, due time.Time, limit uint) ([]Delivery, error) {
+	rows, err := r.queries.ListPendingWebhookDeliveries(ctx, database.ListPendingWebhookDeliveriesParams{
+		NextAttemptAt: due.UTC(),
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toDeliveries(rows), nil
+}
+
+// Claim counts the attempt and keeps the delivery from being due again until lockedUntil,
+// while it is sent.
+func (r *repository) Claim(ctx context.Context, id uint, attempts uint, lockedUntil time.Time) error {
+	return r.queries.ClaimWebhookDelivery(ctx, database.ClaimWebhookDeliveryParams{
+		Attempts:      int32(attempts),
+		NextAttemptAt: lockedUntil.UTC(),
+		ID:            int64(id),
+	})
+}
+
+func (r *repository) MarkSucceeded(ctx context.Context, id uint, attempts uint, responseStatus int, deliveredAt time.Time) error {
+	return r.queries.MarkWebhookDeliverySucceeded(ctx, database.MarkWebhookDeliverySucceededParams{
+		Attempts:       int32(attempts),
+		ResponseStatus: int32(responseStatus),
+		DeliveredAt:    sql.NullTime{Time: deliveredAt.UTC(), Valid: true},
+		ID:             int64(id),
+	})
+}
+
+func (r *repository) Retry(ctx context.Context, id uint, attempts uint, responseStatus int, lastError string, nextAttemptAt time.Time) error {
+	return r.queries.RetryWebhookDelivery(ctx, database.RetryWebhookDeliveryParams{
+		Attempts:       int32(attempts),
+		ResponseStatus: int32(responseStatus),
+		LastError:      truncate(lastError),
+		NextAttemptAt:  nextAttemptAt.UTC(),
+		ID:             int64(id),
+	})
+}
+
+// MarkFailed gives up on the delivery, it can still be replayed by hand.
+func (r *repository) MarkFailed(ctx context.Context, id uint, attempts uint, responseStatus int, lastError string) error {
+	return r.queries.MarkWebhookDeliveryFailed(ctx, database.MarkWebhookDeliveryFailedParams{
+		Attempts:       int32(attempts),
+		ResponseStatus: int32(responseStatus),
+		LastError:      truncate(lastError),
+		ID:             int64(id),
+	})
+}
+
+func (r *repository) WithinTx(ctx context.Context, fn func(Repository) error) error {
+	return r.queries.ExecTx(ctx, func(q *database.Queries) error {
+		return fn(NewRepository(q))
+	})
+}
+
+func toSubscription(s database.WebhookSubscription) (Subscription, error) {
+	var eventTypes []string
+	if err := json.Unmarshal(s.EventTypes, &eventTypes); err != nil {
+		return Subscription{}, err
+	}
+
+	return Subscription{
+		ID:         uint(s.ID),
+		URL:        s.Url,
+		EventTypes: eventTypes,
+		Secret:     s.Secret,
+		CreatedAt:  s.CreatedAt,
+	}, nil
+}
+
+func toDeliveries(rows []database.WebhookDelivery) []Delivery {
+	deliveries := make([]Delivery, 0, len(rows))
+	for _, d := range rows {
+		deliveries = append(deliveries, toDelivery(d))
+	}
+
+	return deliveries
+}
+
+func toDelivery(d database.WebhookDelivery) Delivery {
+	delivery := Delivery{
+		ID:             uint(d.ID),
+		SubscriptionID: uint(d.SubscriptionID),
+		EventID:        uint(d.EventID),
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       uint(d.Attempts),
+		ResponseStatus: int(d.ResponseStatus),
+		LastError:      d.LastError,
+		NextAttemptAt:  d.NextAttemptAt,
+		CreatedAt:      d.CreatedAt,
+	}
+
+	if d.DeliveredAt.Valid {
+		deliveredAt := d.DeliveredAt.Time
+		delivery.DeliveredAt = &deliveredAt
+	}
+
+	return delivery
+}
+
+// maxErrorLength is the size of the last_error column.
+const maxErrorLength = 1000
+
+func truncate(s string) string {
+	if len(s) > maxErrorLength {
+		return s[:maxErrorLength]
+	}
+
+	return s
+}
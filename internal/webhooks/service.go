@@ -0,0 +1,170 @@
+package webhooks
+
+import (
+	"context"
+	"net"
+	"net/url"
+
+	"github.com/johan-ag/testing/internal/platform/authz"
+)
+
+type Service interface {
+	Subscribe(ctx context.Context, url string, eventTypes []string) (Subscription, error)
+	Find(ctx context.Context, id uint) (Subscription, error)
+	List(ctx context.Context) ([]Subscription, error)
+	Unsubscribe(ctx context.Context, id uint) error
+	Deliveries(ctx context.Context, subscriptionID uint) ([]Delivery, error)
+	Replay(ctx context.Context, subscriptionID uint, deliveryID uint) (Delivery, error)
+}
+
+// historyLimit caps how many deliveries Deliveries returns.
+const historyLimit = 100
+
+//go:generate mockgen -destination=./mocks.go -package=webhooks github.com/johan-ag/testing/internal/webhooks Repository,Service
+type service struct {
+	repository Repository
+	eventTypes map[string]bool
+	resolver   Resolver
+}
+
+// NewService returns a webhooks service that accepts subscriptions to eventTypes.
+func NewService(repository Repository, eventTypes []string) *service {
+	known := make(map[string]bool, len(eventTypes))
+	for _, t := range eventTypes {
+		known[t] = true
+	}
+
+	return &service{
+		repository,
+		known,
+		net.DefaultResolver,
+	}
+}
+
+// Subscribe registers the endpoint, which must only resolve to public addresses, and
+// returns the subscription along with the secret that signs its deliveries.
+func (s *service) Subscribe(ctx context.Context, endpoint string, eventTypes []string) (Subscription, error) {
+	if err := policy.Authorize(ctx, actionCreate, authz.Resource{Type: entityName}); err != nil {
+		return Subscription{}, err
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Subscription{}, ErrorInvalidURL
+	}
+	if err := checkHost(ctx, s.resolver, u.Hostname()); err != nil {
+		return Subscription{}, err
+	}
+
+	if len(eventTypes) == 0 {
+		return Subscription{}, ErrorInvalidEventTypes
+	}
+	for _, t := range eventTypes {
+		if !s.eventTypes[t] {
+			return Subscription{}, ErrorInvalidEventTypes
+		}
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	subscription := Subscription{
+		URL:        endpoint,
+		EventTypes: eventTypes,
+		Secret:     secret,
+	}
+
+	id, err := s.repository.SaveSubscription(ctx, subscription)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	created, err := s.repository.FindSubscription(ctx, id)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	return created, nil
+}
+
+func (s *service) Find(ctx context.Context, id uint) (Subscription, error) {
+	if err := policy.Authorize(ctx, actionRead, resource(id)); err != nil {
+		return Subscription{}, err
+	}
+
+	subscription, err := s.repository.FindSubscription(ctx, id)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+func (s *service) List(ctx context.Context) ([]Subscription, error) {
+	if err := policy.Authorize(ctx, actionList, authz.Resource{Type: entityName}); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := s.repository.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	return subscriptions, nil
+}
+
+func (s *service) Unsubscribe(ctx context.Context, id uint) error {
+	if err := policy.Authorize(ctx, actionDelete, resource(id)); err != nil {
+		return err
+	}
+
+	return s.repository.DeleteSubscription(ctx, id)
+}
+
+// Deliveries returns the delivery history of the subscription, newest first.
+func (s *service) Deliveries(ctx context.Context, subscriptionID uint) ([]Delivery, error) {
+	if err := policy.Authorize(ctx, actionRead, resource(subscriptionID)); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repository.FindSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	return s.repository.ListDeliveries(ctx, subscriptionID, historyLimit)
+}
+
+// Replay queues a new delivery of the event of deliveryID, the original stays in the history.
+func (s *service) Replay(ctx context.Context, subscriptionID uint, deliveryID uint) (Delivery, error) {
+	if err := policy.Authorize(ctx, actionReplay, resource(subscriptionID)); err != nil {
+		return Delivery{}, err
+	}
+
+	if _, err := s.repository.FindSubscription(ctx, subscriptionID); err != nil {
+		return Delivery{}, err
+	}
+
+	delivery, err := s.repository.FindDelivery(ctx, subscriptionID, deliveryID)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	id, err := s.repository.SaveDelivery(ctx, Delivery{
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+	})
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	return s.repository.FindDelivery(ctx, subscriptionID, id)
+}
@@ -0,0 +1,199 @@
+package webhooks
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johan-ag/testing/internal/platform/auth"
+	"github.com/johan-ag/testing/internal/platform/authz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceSubscribe(t *testing.T) {
+	tests := []struct {
+		name              string
+		url               string
+		eventTypes        []string
+		executeBeforeTest func(ctx context.Context, r *MockRepository)
+		expectedError     error
+	}{
+		{
+			name:       "subscribe service test successful",
+			url:        "https://example.com/hooks",
+			eventTypes: []string{"UserCreated"},
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {
+				r.
+					EXPECT().
+					SaveSubscription(gomock.Eq(ctx), gomock.Any()).
+					Return(uint(1), nil)
+				r.
+					EXPECT().
+					FindSubscription(gomock.Eq(ctx), gomock.Eq(uint(1))).
+					Return(Subscription{ID: 1, Secret: "secret"}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:              "subscribe service test relative url",
+			url:               "/hooks",
+			eventTypes:        []string{"UserCreated"},
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {},
+			expectedError:     ErrorInvalidURL,
+		},
+		{
+			name:              "subscribe service test loopback address",
+			url:               "http://127.0.0.1:8080/hooks",
+			eventTypes:        []string{"UserCreated"},
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {},
+			expectedError:     ErrorPrivateAddress,
+		},
+		{
+			name:              "subscribe service test name of a private address",
+			url:               "https://internal.example.com/hooks",
+			eventTypes:        []string{"UserCreated"},
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {},
+			expectedError:     ErrorPrivateAddress,
+		},
+		{
+			name:              "subscribe service test unknown event type",
+			url:               "https://example.com/hooks",
+			eventTypes:        []string{"BookCreated"},
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {},
+			expectedError:     ErrorInvalidEventTypes,
+		},
+		{
+			name:              "subscribe service test no event types",
+			url:               "https://example.com/hooks",
+			eventTypes:        nil,
+			executeBeforeTest: func(ctx context.Context, r *MockRepository) {},
+			expectedError:     ErrorInvalidEventTypes,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "100", Roles: []string{auth.RoleAdmin}})
+			ctrl := gomock.NewController(t)
+			repository := NewMockRepository(ctrl)
+
+			tt.executeBeforeTest(ctx, repository)
+
+			service := NewService(repository, []string{"UserCreated", "UserActivated"})
+			service.resolver = resolver{
+				"example.com":          {{IP: net.ParseIP("93.184.216.34")}},
+				"internal.example.com": {{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("10.0.0.7")}},
+			}
+
+			// when
+			_, err := service.Subscribe(ctx, tt.url, tt.eventTypes)
+
+			// then
+			require.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestServiceOnlyAdmins(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(ctx context.Context, s Service) error
+	}{
+		{
+			name: "subscribe",
+			call: func(ctx context.Context, s Service) error {
+				_, err := s.Subscribe(ctx, "https://example.com/hooks", []string{"UserCreated"})
+				return err
+			},
+		},
+		{
+			name: "find",
+			call: func(ctx context.Context, s Service) error {
+				_, err := s.Find(ctx, 1)
+				return err
+			},
+		},
+		{
+			name: "list",
+			call: func(ctx context.Context, s Service) error {
+				_, err := s.List(ctx)
+				return err
+			},
+		},
+		{
+			name: "unsubscribe",
+			call: func(ctx context.Context, s Service) error {
+				return s.Unsubscribe(ctx, 1)
+			},
+		},
+		{
+			name: "deliveries",
+			call: func(ctx context.Context, s Service) error {
+				_, err := s.Deliveries(ctx, 1)
+				return err
+			},
+		},
+		{
+			name: "replay",
+			call: func(ctx context.Context, s Service) error {
+				_, err := s.Replay(ctx, 1, 2)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "1", Roles: []string{auth.RoleUser}})
+			ctrl := gomock.NewController(t)
+			service := NewService(NewMockRepository(ctrl), []string{"UserCreated"})
+
+			// when
+			err := tt.call(ctx, service)
+
+			// then
+			require.ErrorIs(t, err, authz.ErrorDenied)
+		})
+	}
+}
+
+func TestPublic(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected bool
+	}{
+		{ip: "93.184.216.34", expected: true},
+		{ip: "2606:2800:220:1::248", expected: true},
+		{ip: "127.0.0.1", expected: false},
+		{ip: "::1", expected: false},
+		{ip: "169.254.169.254", expected: false},
+		{ip: "fe80::1", expected: false},
+		{ip: "10.1.2.3", expected: false},
+		{ip: "172.16.0.1", expected: false},
+		{ip: "192.168.1.1", expected: false},
+		{ip: "fd00::1", expected: false},
+		{ip: "0.0.0.0", expected: false},
+		{ip: "::ffff:127.0.0.1", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			require.Equal(t, tt.expected, public(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+// resolver resolves the hosts it has, by name.
+type resolver map[string][]net.IPAddr
+
+func (r resolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	addresses, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	return addresses, nil
+}
@@ -0,0 +1,45 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	// TimestampHeader carries the unix time the delivery was signed at.
+	TimestampHeader = "X-Webhook-Timestamp"
+	// SignatureHeader carries the signature of the delivery, see Sign.
+	SignatureHeader = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+	secretLength    = 32
+)
+
+// Sign returns the HMAC-SHA256, keyed with the subscription secret, of the timestamp and
+// the body joined by a dot, hex encoded and prefixed by "sha256=".
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of timestamp and body, receivers should
+// also reject the timestamps too far in the past to avoid replay attacks.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+func generateSecret() (string, error) {
+	secret := make([]byte, secretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
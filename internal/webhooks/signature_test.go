@@ -0,0 +1,23 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	// given
+	body := []byte(`{"id":1}`)
+
+	// when
+	signature := Sign("secret", 1659312000, body)
+
+	// then
+	require.Equal(t, "sha256=", signature[:7])
+	require.Len(t, signature, 7+64)
+	require.True(t, Verify("secret", 1659312000, body, signature))
+	require.False(t, Verify("other", 1659312000, body, signature))
+	require.False(t, Verify("secret", 1659312001, body, signature))
+	require.False(t, Verify("secret", 1659312000, []byte(`{"id":2}`), signature))
+}
@@ -9,4 +9,227 @@ CREATE TABLE IF NOT EXISTS books (
     `id`  INTEGER UNSIGNED AUTO_INCREMENT NOT NULL PRIMARY KEY,
     `title` VARCHAR(50) NOT NULL,
     `author`  INTEGER UNSIGNED NOT NULL
-);
\ No newline at end of file
+);
+
+-- Users: audit timestamps and soft delete. MySQL has no ADD COLUMN IF NOT EXISTS, so each
+-- change is only made when information_schema doesn't list it yet
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'users' AND column_name = 'created_at') = 0,
+    'ALTER TABLE users
+        ADD COLUMN `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        ADD COLUMN `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        ADD COLUMN `deleted_at` DATETIME NULL,
+        ADD INDEX `idx_users_deleted_at` (`deleted_at`)',
+    'DO 0'
+);
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+
+-- Users: activation
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'users' AND column_name = 'activated_at') = 0,
+    'ALTER TABLE users ADD COLUMN `activated_at` DATETIME NULL',
+    'DO 0'
+);
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+
+-- Audit log of every mutation
+CREATE TABLE IF NOT EXISTS audit_events (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT NOT NULL PRIMARY KEY,
+    `entity` VARCHAR(50) NOT NULL,
+    `entity_id` VARCHAR(50) NOT NULL,
+    `action` VARCHAR(50) NOT NULL,
+    `actor` VARCHAR(100) NOT NULL,
+    `request_id` VARCHAR(100) NOT NULL,
+    `diff` JSON NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    INDEX `idx_audit_events_entity` (`entity`, `entity_id`)
+);
+
+-- Transactional outbox of domain events
+CREATE TABLE IF NOT EXISTS outbox_events (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT NOT NULL PRIMARY KEY,
+    `event_type` VARCHAR(100) NOT NULL,
+    `aggregate_type` VARCHAR(50) NOT NULL,
+    `aggregate_id` VARCHAR(50) NOT NULL,
+    `payload` JSON NOT NULL,
+    `status` VARCHAR(20) NOT NULL,
+    `attempts` INTEGER UNSIGNED NOT NULL,
+    `last_error` VARCHAR(1000) NOT NULL DEFAULT '',
+    `next_attempt_at` DATETIME NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    `delivered_at` DATETIME NULL,
+    INDEX `idx_outbox_events_status` (`status`, `next_attempt_at`)
+);
+
+-- Webhook subscriptions and their delivery history
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    `id` INTEGER UNSIGNED AUTO_INCREMENT NOT NULL PRIMARY KEY,
+    `url` VARCHAR(2048) NOT NULL,
+    `event_types` JSON NOT NULL,
+    `secret` VARCHAR(64) NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    `deleted_at` DATETIME NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT NOT NULL PRIMARY KEY,
+    `subscription_id` INTEGER UNSIGNED NOT NULL,
+    `event_id` BIGINT UNSIGNED NOT NULL,
+    `event_type` VARCHAR(100) NOT NULL,
+    `payload` JSON NOT NULL,
+    `status` VARCHAR(20) NOT NULL,
+    `attempts` INTEGER UNSIGNED NOT NULL,
+    `response_status` INTEGER NOT NULL DEFAULT 0,
+    `last_error` VARCHAR(1000) NOT NULL DEFAULT '',
+    `next_attempt_at` DATETIME NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    `delivered_at` DATETIME NULL,
+    INDEX `idx_webhook_deliveries_subscription` (`subscription_id`, `id`),
+    INDEX `idx_webhook_deliveries_status` (`status`, `next_attempt_at`)
+);
+
+-- Users: bulk imports and their error reports
+CREATE TABLE IF NOT EXISTS user_imports (
+    `id` INTEGER UNSIGNED AUTO_INCREMENT NOT NULL PRIMARY KEY,
+    `format` VARCHAR(10) NOT NULL,
+    `status` VARCHAR(20) NOT NULL,
+    `created_by` VARCHAR(100) NOT NULL,
+    `processed_rows` INTEGER UNSIGNED NOT NULL DEFAULT 0,
+    `failed_rows` INTEGER UNSIGNED NOT NULL DEFAULT 0,
+    `progress` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+    `error` VARCHAR(1000) NOT NULL DEFAULT '',
+    `error_report` MEDIUMTEXT NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    `finished_at` DATETIME NULL
+);
+
+-- The uploads of the imports, split in parts that any worker can read until the import runs
+CREATE TABLE IF NOT EXISTS user_import_parts (
+    `import_id` INTEGER UNSIGNED NOT NULL,
+    `part` INTEGER UNSIGNED NOT NULL,
+    `content` MEDIUMBLOB NOT NULL,
+    PRIMARY KEY (`import_id`, `part`)
+);
+
+-- Background jobs, claimed by the workers with SELECT ... FOR UPDATE SKIP LOCKED
+CREATE TABLE IF NOT EXISTS jobs (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT NOT NULL PRIMARY KEY,
+    `type` VARCHAR(50) NOT NULL,
+    `payload` JSON NOT NULL,
+    `status` VARCHAR(20) NOT NULL,
+    `attempts` INTEGER UNSIGNED NOT NULL DEFAULT 0,
+    `last_error` VARCHAR(1000) NOT NULL DEFAULT '',
+    `principal` JSON NOT NULL,
+    `request_id` VARCHAR(100) NOT NULL,
+    `run_at` DATETIME NOT NULL,
+    `locked_until` DATETIME NULL,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    `finished_at` DATETIME NULL,
+    INDEX `idx_jobs_claim` (`type`, `status`, `run_at`),
+    INDEX `idx_jobs_locked_until` (`type`, `status`, `locked_until`)
+);
+
+-- Cards: the character catalog synced from the upstream api and the history of the syncs
+CREATE TABLE IF NOT EXISTS cards (
+    `id` INTEGER UNSIGNED NOT NULL PRIMARY KEY,
+    `name` VARCHAR(255) NOT NULL,
+    `status` VARCHAR(20) NOT NULL,
+    `species` VARCHAR(100) NOT NULL,
+    `gender` VARCHAR(20) NOT NULL,
+    `image` VARCHAR(2048) NOT NULL,
+    `hash` CHAR(64) NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS card_sync_runs (
+    `id` INTEGER UNSIGNED AUTO_INCREMENT NOT NULL PRIMARY KEY,
+    `status` VARCHAR(20) NOT NULL,
+    `added` INTEGER UNSIGNED NOT NULL DEFAULT 0,
+    `updated` INTEGER UNSIGNED NOT NULL DEFAULT 0,
+    `duration_ms` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    `error` VARCHAR(1000) NOT NULL DEFAULT '',
+    `started_at` DATETIME NOT NULL,
+    `finished_at` DATETIME NULL
+);
+
+-- Cards: the last upstream page read by each sync, which the next one resumes from
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'card_sync_runs' AND column_name = 'last_page') = 0,
+    'ALTER TABLE card_sync_runs ADD COLUMN `last_page` INTEGER UNSIGNED NOT NULL DEFAULT 0',
+    'DO 0'
+);
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+
+-- Scheduler: the last tick of each schedule and the instance that ran it
+CREATE TABLE IF NOT EXISTS schedule_locks (
+    `name` VARCHAR(100) NOT NULL PRIMARY KEY,
+    `tick` DATETIME NOT NULL,
+    `owner` VARCHAR(100) NOT NULL,
+    `acquired_at` DATETIME NOT NULL
+);
+
+-- GraphQL: the books of an author and the cards each user owns are loaded in batches
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.statistics
+     WHERE table_schema = DATABASE() AND table_name = 'books' AND index_name = 'idx_books_author') = 0,
+    'ALTER TABLE books ADD INDEX `idx_books_author` (`author`)',
+    'DO 0'
+);
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+
+-- Cards: the cards assigned to each user by the admins
+CREATE TABLE IF NOT EXISTS user_cards (
+    `user_id` INTEGER UNSIGNED NOT NULL,
+    `card_id` INTEGER UNSIGNED NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    PRIMARY KEY (`user_id`, `card_id`)
+);
+
+-- Search: full-text indexes over the names of the users and the titles of the books. The
+-- ngram parser indexes every pair of characters, so misspelled and partial words still
+-- share most of their tokens with the words they are meant to match. MySQL has no ADD INDEX
+-- IF NOT EXISTS, so each index is only added when information_schema doesn't list it yet
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.statistics
+     WHERE table_schema = DATABASE() AND table_name = 'users' AND index_name = 'ft_users_name') = 0,
+    'ALTER TABLE users ADD FULLTEXT INDEX `ft_users_name` (`name`) WITH PARSER ngram',
+    'DO 0'
+);
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.statistics
+     WHERE table_schema = DATABASE() AND table_name = 'books' AND index_name = 'ft_books_title') = 0,
+    'ALTER TABLE books ADD FULLTEXT INDEX `ft_books_title` (`title`) WITH PARSER ngram',
+    'DO 0'
+);
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+
+-- Cache: the keys dropped from a cache, which every instance polls to evict them from its
+-- in-process tier
+CREATE TABLE IF NOT EXISTS cache_invalidations (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT NOT NULL PRIMARY KEY,
+    `cache` VARCHAR(100) NOT NULL,
+    `key` VARCHAR(255) NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    INDEX `idx_cache_invalidations_cache` (`cache`, `id`),
+    INDEX `idx_cache_invalidations_created_at` (`created_at`)
+);
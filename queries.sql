@@ -1,11 +1,49 @@
 -- Users
 -- name: SaveUser :execresult
 INSERT INTO `users` (
-    `name`, `age`, `random`
-) VALUES ( ?, ?, ? );
+    `name`, `age`, `random`, `created_at`, `updated_at`
+) VALUES ( ?, ?, ?, ?, ? );
 
 -- name: FindUser :one
-SELECT * FROM `users` WHERE `id` = ? ;  
+SELECT * FROM `users` WHERE `id` = ? AND `deleted_at` IS NULL ;
+
+-- name: FindUserIncludingDeleted :one
+SELECT * FROM `users` WHERE `id` = ? ;
+
+-- name: ListUsers :many
+SELECT * FROM `users` WHERE `deleted_at` IS NULL
+ORDER BY `id` LIMIT ? OFFSET ? ;
+
+-- name: ListUsersIncludingDeleted :many
+SELECT * FROM `users`
+ORDER BY `id` LIMIT ? OFFSET ? ;
+
+-- name: ListUsersAfter :many
+SELECT * FROM `users` WHERE `id` > ? AND `deleted_at` IS NULL
+ORDER BY `id` LIMIT ? ;
+
+-- name: ListUsersIncludingDeletedAfter :many
+SELECT * FROM `users` WHERE `id` > ?
+ORDER BY `id` LIMIT ? ;
+
+-- name: SoftDeleteUser :execresult
+UPDATE `users` SET `deleted_at` = ?, `updated_at` = ?
+WHERE `id` = ? AND `deleted_at` IS NULL ;
+
+-- name: RestoreUser :execresult
+UPDATE `users` SET `deleted_at` = NULL, `updated_at` = ?
+WHERE `id` = ? AND `deleted_at` IS NOT NULL ;
+
+-- name: PurgeDeletedUsers :execresult
+DELETE FROM `users` WHERE `deleted_at` IS NOT NULL AND `deleted_at` < ? ;
+
+-- name: UpdateUser :execresult
+UPDATE `users` SET `name` = ?, `age` = ?, `updated_at` = ?
+WHERE `id` = ? AND `deleted_at` IS NULL ;
+
+-- name: ActivateUser :execresult
+UPDATE `users` SET `activated_at` = ?, `updated_at` = ?
+WHERE `id` = ? AND `activated_at` IS NULL AND `deleted_at` IS NULL ;
 
 -- Books
 -- name: SaveBook :execresult
@@ -14,4 +52,242 @@ INSERT INTO `books` (
 ) VALUES ( ?, ? );
 
 -- name: FindBook :one
-SELECT * FROM `books` WHERE `id` = ? ;  
\ No newline at end of file
+SELECT * FROM `books` WHERE `id` = ? ;
+
+-- Audit
+-- name: SaveAuditEvent :exec
+INSERT INTO `audit_events` (
+    `entity`, `entity_id`, `action`, `actor`, `request_id`, `diff`, `created_at`
+) VALUES ( ?, ?, ?, ?, ?, ?, ? );
+
+-- name: ListAuditEvents :many
+SELECT * FROM `audit_events`
+WHERE `entity` = ? AND `entity_id` = ?
+ORDER BY `id` ;
+
+-- Outbox
+-- name: SaveOutboxEvent :exec
+INSERT INTO `outbox_events` (
+    `event_type`, `aggregate_type`, `aggregate_id`, `payload`, `status`, `attempts`, `next_attempt_at`, `created_at`
+) VALUES ( ?, ?, ?, ?, 'pending', 0, ?, ? );
+
+-- name: ListPendingOutboxEvents :many
+SELECT * FROM `outbox_events`
+WHERE `status` = 'pending' AND `next_attempt_at` <= ?
+ORDER BY `id` LIMIT ?
+FOR UPDATE SKIP LOCKED ;
+
+-- name: MarkOutboxEventDelivered :exec
+UPDATE `outbox_events` SET `status` = 'delivered', `attempts` = ?, `delivered_at` = ?
+WHERE `id` = ? ;
+
+-- name: RetryOutboxEvent :exec
+UPDATE `outbox_events` SET `attempts` = ?, `last_error` = ?, `next_attempt_at` = ?
+WHERE `id` = ? ;
+
+-- name: DeadLetterOutboxEvent :exec
+UPDATE `outbox_events` SET `status` = 'dead', `attempts` = ?, `last_error` = ?
+WHERE `id` = ? ;
+
+-- Webhooks
+-- name: SaveWebhookSubscription :execresult
+INSERT INTO `webhook_subscriptions` (
+    `url`, `event_types`, `secret`, `created_at`
+) VALUES ( ?, ?, ?, ? );
+
+-- name: FindWebhookSubscription :one
+SELECT * FROM `webhook_subscriptions` WHERE `id` = ? AND `deleted_at` IS NULL ;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM `webhook_subscriptions` WHERE `deleted_at` IS NULL ORDER BY `id` ;
+
+-- name: DeleteWebhookSubscription :execresult
+UPDATE `webhook_subscriptions` SET `deleted_at` = ?
+WHERE `id` = ? AND `deleted_at` IS NULL ;
+
+-- name: SaveWebhookDelivery :execresult
+INSERT INTO `webhook_deliveries` (
+    `subscription_id`, `event_id`, `event_type`, `payload`, `status`, `attempts`, `next_attempt_at`, `created_at`
+) VALUES ( ?, ?, ?, ?, 'pending', 0, ?, ? );
+
+-- name: FindWebhookDelivery :one
+SELECT * FROM `webhook_deliveries` WHERE `id` = ? AND `subscription_id` = ? ;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM `webhook_deliveries` WHERE `subscription_id` = ?
+ORDER BY `id` DESC LIMIT ? ;
+
+-- name: ListPendingWebhookDeliveries :many
+SELECT * FROM `webhook_deliveries`
+WHERE `status` = 'pending' AND `next_attempt_at` <= ?
+ORDER BY `id` LIMIT ?
+FOR UPDATE SKIP LOCKED ;
+
+-- name: ClaimWebhookDelivery :exec
+UPDATE `webhook_deliveries` SET `attempts` = ?, `next_attempt_at` = ?
+WHERE `id` = ? ;
+
+-- name: MarkWebhookDeliverySucceeded :exec
+UPDATE `webhook_deliveries` SET `status` = 'succeeded', `attempts` = ?, `response_status` = ?, `delivered_at` = ?
+WHERE `id` = ? ;
+
+-- name: RetryWebhookDelivery :exec
+UPDATE `webhook_deliveries` SET `attempts` = ?, `response_status` = ?, `last_error` = ?, `next_attempt_at` = ?
+WHERE `id` = ? ;
+
+-- name: MarkWebhookDeliveryFailed :exec
+UPDATE `webhook_deliveries` SET `status` = 'failed', `attempts` = ?, `response_status` = ?, `last_error` = ?
+WHERE `id` = ? ;
+
+-- User imports
+
+-- name: SaveUserImport :execresult
+INSERT INTO `user_imports` (
+    `format`, `status`, `created_by`, `error_report`, `created_at`, `updated_at`
+) VALUES ( ?, 'pending', ?, '', ?, ? );
+
+-- name: FindUserImport :one
+SELECT * FROM `user_imports` WHERE `id` = ? ;
+
+-- name: UpdateUserImportProgress :exec
+UPDATE `user_imports` SET `status` = 'running', `processed_rows` = ?, `failed_rows` = ?, `progress` = ?, `updated_at` = ?
+WHERE `id` = ? ;
+
+-- name: FinishUserImport :exec
+UPDATE `user_imports` SET `status` = ?, `processed_rows` = ?, `failed_rows` = ?, `progress` = ?, `error` = ?, `error_report` = ?, `updated_at` = ?, `finished_at` = ?
+WHERE `id` = ? ;
+
+-- name: SaveUserImportPart :exec
+INSERT INTO `user_import_parts` (
+    `import_id`, `part`, `content`
+) VALUES ( ?, ?, ? );
+
+-- name: FindUserImportPart :one
+SELECT `content` FROM `user_import_parts` WHERE `import_id` = ? AND `part` = ? ;
+
+-- name: DeleteUserImportParts :exec
+DELETE FROM `user_import_parts` WHERE `import_id` = ? ;
+
+-- Jobs
+
+-- name: SaveJob :execresult
+INSERT INTO `jobs` (
+    `type`, `payload`, `status`, `attempts`, `principal`, `request_id`, `run_at`, `created_at`, `updated_at`
+) VALUES ( ?, ?, 'queued', 0, ?, ?, ?, ?, ? );
+
+-- name: FindJob :one
+SELECT * FROM `jobs` WHERE `id` = ? ;
+
+-- name: ListClaimableJobs :many
+SELECT * FROM `jobs`
+WHERE `type` = ? AND (
+    (`status` = 'queued' AND `run_at` <= ?) OR (`status` = 'running' AND `locked_until` <= ?)
+)
+ORDER BY `id` LIMIT ?
+FOR UPDATE SKIP LOCKED ;
+
+-- name: ClaimJob :exec
+UPDATE `jobs` SET `status` = 'running', `attempts` = ?, `locked_until` = ?, `updated_at` = ?
+WHERE `id` = ? ;
+
+-- name: ExtendJobLock :execresult
+UPDATE `jobs` SET `locked_until` = ?, `updated_at` = ?
+WHERE `id` = ? AND `attempts` = ? AND `status` = 'running' ;
+
+-- name: CompleteJob :execresult
+UPDATE `jobs` SET `status` = 'succeeded', `locked_until` = NULL, `updated_at` = ?, `finished_at` = ?
+WHERE `id` = ? AND `attempts` = ? AND `status` = 'running' ;
+
+-- name: RetryJob :execresult
+UPDATE `jobs` SET `status` = 'queued', `last_error` = ?, `run_at` = ?, `locked_until` = NULL, `updated_at` = ?
+WHERE `id` = ? AND `attempts` = ? AND `status` = 'running' ;
+
+-- name: FailJob :execresult
+UPDATE `jobs` SET `status` = 'failed', `last_error` = ?, `locked_until` = NULL, `updated_at` = ?, `finished_at` = ?
+WHERE `id` = ? AND `attempts` = ? AND `status` = 'running' ;
+
+-- Cards
+
+-- name: ListCardHashes :many
+SELECT `id`, `hash` FROM `cards` ;
+
+-- name: UpsertCard :exec
+INSERT INTO `cards` (
+    `id`, `name`, `status`, `species`, `gender`, `image`, `hash`, `created_at`, `updated_at`
+) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ? )
+ON DUPLICATE KEY UPDATE
+    `name` = VALUES(`name`), `status` = VALUES(`status`), `species` = VALUES(`species`), `gender` = VALUES(`gender`),
+    `image` = VALUES(`image`), `hash` = VALUES(`hash`), `updated_at` = VALUES(`updated_at`) ;
+
+-- name: FindCard :one
+SELECT * FROM `cards` WHERE `id` = ? ;
+
+-- name: ListCards :many
+SELECT * FROM `cards`
+ORDER BY `id` LIMIT ? OFFSET ? ;
+
+-- name: AssignCard :exec
+INSERT INTO `user_cards` (
+    `user_id`, `card_id`, `created_at`
+) VALUES ( ?, ?, ? )
+ON DUPLICATE KEY UPDATE `created_at` = `created_at` ;
+
+-- name: UnassignCard :execresult
+DELETE FROM `user_cards` WHERE `user_id` = ? AND `card_id` = ? ;
+
+-- name: SaveCardSyncRun :execresult
+INSERT INTO `card_sync_runs` (
+    `status`, `started_at`
+) VALUES ( 'running', ? );
+
+-- name: FinishCardSyncRun :exec
+UPDATE `card_sync_runs` SET `status` = ?, `added` = ?, `updated` = ?, `duration_ms` = ?, `error` = ?, `finished_at` = ?, `last_page` = ?
+WHERE `id` = ? ;
+
+-- name: FailStaleCardSyncRuns :execresult
+UPDATE `card_sync_runs` SET `status` = 'failed', `error` = ?, `finished_at` = ?
+WHERE `status` = 'running' AND `started_at` < ? ;
+
+-- name: FindLastSucceededCardSyncRunPage :one
+SELECT `last_page` FROM `card_sync_runs` WHERE `status` = 'succeeded' ORDER BY `id` DESC LIMIT 1 ;
+
+-- name: FindLastCardSyncRun :one
+SELECT * FROM `card_sync_runs` ORDER BY `id` DESC LIMIT 1 ;
+
+-- Search
+
+-- name: SearchUsersAndBooks :many
+SELECT 'user' AS `type`, `id`, `name` AS `text`, MATCH(`name`) AGAINST (sqlc.arg(query) IN NATURAL LANGUAGE MODE) AS `score`
+FROM `users` WHERE `deleted_at` IS NULL AND (sqlc.arg(all_users) OR `id` = sqlc.arg(user_id)) AND MATCH(`name`) AGAINST (sqlc.arg(query) IN NATURAL LANGUAGE MODE)
+UNION ALL
+SELECT 'book' AS `type`, `id`, `title` AS `text`, MATCH(`title`) AGAINST (sqlc.arg(query) IN NATURAL LANGUAGE MODE) AS `score`
+FROM `books` WHERE MATCH(`title`) AGAINST (sqlc.arg(query) IN NATURAL LANGUAGE MODE)
+ORDER BY `score` DESC LIMIT ? ;
+
+-- Cache
+
+-- name: SaveCacheInvalidation :exec
+INSERT INTO `cache_invalidations` (
+    `cache`, `key`, `created_at`
+) VALUES ( ?, ?, ? ) ;
+
+-- name: FindLastCacheInvalidationID :one
+SELECT `id` FROM `cache_invalidations` ORDER BY `id` DESC LIMIT 1 ;
+
+-- name: ListCacheInvalidations :many
+SELECT `id`, `key` FROM `cache_invalidations`
+WHERE `cache` = ? AND `id` > ? ORDER BY `id` LIMIT ? ;
+
+-- name: DeleteCacheInvalidations :execrows
+DELETE FROM `cache_invalidations` WHERE `created_at` < ? ;
+
+-- Scheduler
+
+-- name: AcquireScheduleTick :execresult
+INSERT INTO `schedule_locks` (
+    `name`, `tick`, `owner`, `acquired_at`
+) VALUES ( ?, ?, ?, ? )
+ON DUPLICATE KEY UPDATE
+    `owner` = IF(`tick` < VALUES(`tick`), VALUES(`owner`), `owner`),
+    `acquired_at` = IF(`tick` < VALUES(`tick`), VALUES(`acquired_at`), `acquired_at`),
+    `tick` = GREATEST(`tick`, VALUES(`tick`)) ;